@@ -0,0 +1,319 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types.
+const (
+	// TypeFilterPolicyValid resources report whether their filter policy
+	// will be accepted by SNS.
+	TypeFilterPolicyValid xpv1.ConditionType = "FilterPolicyValid"
+
+	// TypeEncryptionCompliant resources report whether they satisfy the
+	// encryption requirement of their ProviderConfig.
+	TypeEncryptionCompliant xpv1.ConditionType = "EncryptionCompliant"
+
+	// TypePartitionMatch resources report whether the partition of their ARN
+	// is that of their region. A mismatch usually means a custom endpoint
+	// routes requests to the wrong partition.
+	TypePartitionMatch xpv1.ConditionType = "PartitionMatch"
+
+	// TypeAttributesApplied resources report whether the attributes set by
+	// their latest update took effect.
+	TypeAttributesApplied xpv1.ConditionType = "AttributesApplied"
+
+	// TypeTagsApplied resources report whether the tags set by their latest
+	// update took effect.
+	TypeTagsApplied xpv1.ConditionType = "TagsApplied"
+
+	// TypeDependenciesReady resources report whether the resources they
+	// reference, e.g. KMS Keys, exist and are ready.
+	TypeDependenciesReady xpv1.ConditionType = "DependenciesReady"
+
+	// TypeUpdateComplete resources report whether their latest update
+	// completed, or was throttled by AWS and will be retried.
+	TypeUpdateComplete xpv1.ConditionType = "UpdateComplete"
+
+	// TypeExclusive resources report whether they're the only resource that
+	// addresses their external resource.
+	TypeExclusive xpv1.ConditionType = "Exclusive"
+
+	// TypeARNUnchanged resources report whether they still address the
+	// external resource they were created or imported with.
+	TypeARNUnchanged xpv1.ConditionType = "ARNUnchanged"
+)
+
+// Condition reasons.
+const (
+	ReasonFilterPolicyValid   xpv1.ConditionReason = "ValidFilterPolicy"
+	ReasonFilterPolicyInvalid xpv1.ConditionReason = "InvalidFilterPolicy"
+
+	ReasonEncrypted     xpv1.ConditionReason = "Encrypted"
+	ReasonUnencrypted   xpv1.ConditionReason = "Unencrypted"
+	ReasonAWSManagedKey xpv1.ConditionReason = "AWSManagedKey"
+
+	ReasonPartitionMatch    xpv1.ConditionReason = "PartitionMatch"
+	ReasonPartitionMismatch xpv1.ConditionReason = "PartitionMismatch"
+
+	ReasonAttributesApplied    xpv1.ConditionReason = "AttributesApplied"
+	ReasonAttributesNotApplied xpv1.ConditionReason = "AttributesNotApplied"
+
+	ReasonTagsApplied    xpv1.ConditionReason = "TagsApplied"
+	ReasonTagsNotApplied xpv1.ConditionReason = "TagsNotApplied"
+
+	ReasonDependenciesReady   xpv1.ConditionReason = "DependenciesReady"
+	ReasonWaitingOnDependency xpv1.ConditionReason = "WaitingOnDependency"
+
+	ReasonUpdateComplete  xpv1.ConditionReason = "UpdateComplete"
+	ReasonUpdateThrottled xpv1.ConditionReason = "UpdateThrottled"
+
+	ReasonExclusive xpv1.ConditionReason = "Exclusive"
+	ReasonShared    xpv1.ConditionReason = "SharedExternalResource"
+
+	ReasonARNUnchanged xpv1.ConditionReason = "ARNUnchanged"
+	ReasonARNChanged   xpv1.ConditionReason = "ARNChanged"
+)
+
+// FilterPolicyValid returns a condition that indicates the filter policy of
+// a resource is valid.
+func FilterPolicyValid() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeFilterPolicyValid,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonFilterPolicyValid,
+	}
+}
+
+// FilterPolicyInvalid returns a condition that indicates the filter policy
+// of a resource is invalid, and thus matches no messages.
+func FilterPolicyInvalid(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeFilterPolicyValid,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonFilterPolicyInvalid,
+		Message:            err.Error(),
+	}
+}
+
+// EncryptionCompliant returns a condition that indicates a resource is
+// encrypted as required by its ProviderConfig.
+func EncryptionCompliant() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeEncryptionCompliant,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonEncrypted,
+	}
+}
+
+// EncryptionNotCompliant returns a condition that indicates a resource is
+// not encrypted although its ProviderConfig requires it to be, and won't be
+// reconciled until it is.
+func EncryptionNotCompliant(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeEncryptionCompliant,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUnencrypted,
+		Message:            err.Error(),
+	}
+}
+
+// EncryptionKeyNotCompliant returns a condition that indicates a resource is
+// encrypted with an AWS managed key although its ProviderConfig requires a
+// customer managed key, and won't be reconciled until it is.
+func EncryptionKeyNotCompliant(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeEncryptionCompliant,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAWSManagedKey,
+		Message:            err.Error(),
+	}
+}
+
+// PartitionMatch returns a condition that indicates the ARN of a resource is
+// in the partition of its region.
+func PartitionMatch() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePartitionMatch,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPartitionMatch,
+	}
+}
+
+// PartitionMismatch returns a condition that indicates the ARN of a resource
+// is in a different partition than its region, which usually means the
+// endpoint configuration of its ProviderConfig is wrong.
+func PartitionMismatch(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypePartitionMatch,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPartitionMismatch,
+		Message:            err.Error(),
+	}
+}
+
+// AttributesApplied returns a condition that indicates the attributes set by
+// the latest update of a resource took effect.
+func AttributesApplied() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeAttributesApplied,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAttributesApplied,
+	}
+}
+
+// AttributesNotApplied returns a condition that indicates AWS accepted but
+// didn't apply some of the attributes set by the latest update of a resource.
+func AttributesNotApplied(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeAttributesApplied,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAttributesNotApplied,
+		Message:            err.Error(),
+	}
+}
+
+// TagsApplied returns a condition that indicates the tags set by the latest
+// update of a resource took effect.
+func TagsApplied() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTagsApplied,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTagsApplied,
+	}
+}
+
+// TagsNotApplied returns a condition that indicates AWS accepted but didn't
+// apply some of the tags set or removed by the latest update of a resource.
+func TagsNotApplied(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTagsApplied,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTagsNotApplied,
+		Message:            err.Error(),
+	}
+}
+
+// DependenciesReady returns a condition that indicates the resources a
+// resource references exist and are ready.
+func DependenciesReady() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDependenciesReady,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDependenciesReady,
+	}
+}
+
+// WaitingOnDependency returns a condition that indicates a resource a
+// resource references doesn't exist or isn't ready yet. The resource isn't
+// reconciled until it is.
+func WaitingOnDependency(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDependenciesReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonWaitingOnDependency,
+		Message:            err.Error(),
+	}
+}
+
+// UpdateComplete returns a condition that indicates the latest update of a
+// resource completed.
+func UpdateComplete() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUpdateComplete,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUpdateComplete,
+	}
+}
+
+// UpdateThrottled returns a condition that indicates the latest update of a
+// resource was throttled by AWS, or conflicted with a concurrent update. The
+// update is retried with backoff.
+func UpdateThrottled(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeUpdateComplete,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUpdateThrottled,
+		Message:            err.Error(),
+	}
+}
+
+// Exclusive returns a condition that indicates no other resource addresses
+// the external resource of a resource.
+func Exclusive() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeExclusive,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonExclusive,
+	}
+}
+
+// Shared returns a condition that indicates other resources address the
+// external resource of a resource, and thus compete to manage it.
+func Shared(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeExclusive,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonShared,
+		Message:            err.Error(),
+	}
+}
+
+// ARNUnchanged returns a condition that indicates a resource still addresses
+// the external resource it was created or imported with.
+func ARNUnchanged() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeARNUnchanged,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonARNUnchanged,
+	}
+}
+
+// ARNChanged returns a condition that indicates the spec of a resource
+// implies another ARN than that of the external resource it manages. The
+// resource isn't reconciled until the change is reverted.
+func ARNChanged(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeARNUnchanged,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonARNChanged,
+		Message:            err.Error(),
+	}
+}
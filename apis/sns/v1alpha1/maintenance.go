@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maintenanceWindowStartLayout is the layout of the start of a maintenance
+// window.
+const maintenanceWindowStartLayout = "15:04"
+
+// A MaintenanceWindow is a recurring period during which a resource is only
+// observed. It's neither created, updated nor deleted until the window ends.
+type MaintenanceWindow struct {
+	// Start is the time of day the window starts at, as HH:MM in UTC.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
+
+	// Duration of the window, e.g. 2h.
+	Duration metav1.Duration `json:"duration"`
+
+	// Days of the week the window starts on. The window starts every day if
+	// none are given.
+	// +optional
+	Days []MaintenanceWindowDay `json:"days,omitempty"`
+}
+
+// A MaintenanceWindowDay is a day of the week a maintenance window starts on.
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type MaintenanceWindowDay string
+
+// Contains returns true if the supplied time is within the window. A nil
+// window or one whose start can't be parsed contains no time.
+func (w *MaintenanceWindow) Contains(t time.Time) bool {
+	if w == nil || w.Duration.Duration <= 0 {
+		return false
+	}
+	start, err := time.Parse(maintenanceWindowStartLayout, w.Start)
+	if err != nil {
+		return false
+	}
+	t = t.UTC()
+	// A window may have started on an earlier day, e.g. before midnight.
+	days := int(w.Duration.Duration / (24 * time.Hour))
+	for offset := 0; offset <= days+1; offset++ {
+		d := t.AddDate(0, 0, -offset)
+		s := time.Date(d.Year(), d.Month(), d.Day(), start.Hour(), start.Minute(), 0, 0, time.UTC)
+		if !w.startsOn(s.Weekday()) {
+			continue
+		}
+		if !t.Before(s) && t.Before(s.Add(w.Duration.Duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *MaintenanceWindow) startsOn(d time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, day := range w.Days {
+		if string(day) == d.String() {
+			return true
+		}
+	}
+	return false
+}
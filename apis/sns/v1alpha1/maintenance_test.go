@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMaintenanceWindowContains(t *testing.T) {
+	// 1 October 2021 is a Friday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2021, time.October, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	cases := map[string]struct {
+		reason string
+		w      *MaintenanceWindow
+		t      time.Time
+		want   bool
+	}{
+		"NoWindow": {
+			reason: "A nil window should contain no time.",
+			t:      at(1, 12, 0),
+		},
+		"InWindow": {
+			reason: "A time between the start and end of the window should be contained.",
+			w:      &MaintenanceWindow{Start: "09:30", Duration: metav1.Duration{Duration: time.Hour}},
+			t:      at(1, 10, 0),
+			want:   true,
+		},
+		"AtEnd": {
+			reason: "The end of the window should not be contained.",
+			w:      &MaintenanceWindow{Start: "09:30", Duration: metav1.Duration{Duration: time.Hour}},
+			t:      at(1, 10, 30),
+		},
+		"AcrossMidnight": {
+			reason: "A window that started the day before should contain times after midnight.",
+			w:      &MaintenanceWindow{Start: "22:00", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			t:      at(2, 1, 0),
+			want:   true,
+		},
+		"OtherTimeZone": {
+			reason: "Times should be compared in UTC.",
+			w:      &MaintenanceWindow{Start: "09:30", Duration: metav1.Duration{Duration: time.Hour}},
+			t:      at(1, 10, 0).In(time.FixedZone("UTC+2", 2*60*60)),
+			want:   true,
+		},
+		"OnDay": {
+			reason: "A window should be contained on the days it starts on.",
+			w:      &MaintenanceWindow{Start: "22:00", Duration: metav1.Duration{Duration: 4 * time.Hour}, Days: []MaintenanceWindowDay{"Friday"}},
+			t:      at(2, 1, 0),
+			want:   true,
+		},
+		"NotOnDay": {
+			reason: "A window should not be contained on days it doesn't start on.",
+			w:      &MaintenanceWindow{Start: "22:00", Duration: metav1.Duration{Duration: 4 * time.Hour}, Days: []MaintenanceWindowDay{"Saturday"}},
+			t:      at(2, 1, 0),
+		},
+		"InvalidStart": {
+			reason: "A window whose start can't be parsed should contain no time.",
+			w:      &MaintenanceWindow{Start: "9am", Duration: metav1.Duration{Duration: time.Hour}},
+			t:      at(1, 9, 30),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.w.Contains(tc.t)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nContains(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errGetKMSKey        = "cannot get referenced KMS Key"
+	errListKMSKeys      = "cannot list KMS Keys"
+	errNoKMSKeyMatches  = "no KMS Key matches the selector"
+	errKMSKeyNotCreated = "referenced KMS Key does not have an external name yet"
+	errKMSKeyNotReady   = "referenced KMS Key is not ready yet"
+)
+
+// KMSKeyGroupVersionKind is the kind of the KMS Keys a Topic may reference.
+// They're managed by provider-aws rather than this provider, so they're read
+// as unstructured objects.
+var KMSKeyGroupVersionKind = schema.GroupVersionKind{Group: "kms.aws.crossplane.io", Version: "v1alpha1", Kind: "Key"}
+
+// ResolveReferences of this Topic. It's called by the managed reconciler
+// before every observation. A Topic whose KMS Key doesn't exist or isn't
+// ready yet is waiting on a dependency, which its DependenciesReady
+// condition reports. The reconciler retries until the key is ready.
+func (mg *Topic) ResolveReferences(ctx context.Context, c client.Reader) error {
+	p := &mg.Spec.ForProvider
+	if meta.WasDeleted(mg) || (p.KMSMasterKeyIDRef == nil && p.KMSMasterKeyIDSelector == nil) {
+		return nil
+	}
+
+	// Resolved values are cached like they are by reference.APIResolver; the
+	// reference is only resolved again if the key ID is cleared.
+	if reference.FromPtrValue(p.KMSMasterKeyID) == "" {
+		waiting, err := resolveKMSMasterKeyID(ctx, c, mg)
+		if waiting {
+			mg.SetConditions(WaitingOnDependency(err))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	mg.SetConditions(DependenciesReady())
+	return nil
+}
+
+// resolveKMSMasterKeyID resolves the KMS master key ID of the supplied Topic
+// from its reference or selector. It reports whether an error is due to the
+// KMS Key not existing or not being ready yet.
+func resolveKMSMasterKeyID(ctx context.Context, c client.Reader, mg *Topic) (bool, error) {
+	p := &mg.Spec.ForProvider
+	switch {
+	case p.KMSMasterKeyIDRef != nil:
+		key := &unstructured.Unstructured{}
+		key.SetGroupVersionKind(KMSKeyGroupVersionKind)
+		if err := c.Get(ctx, types.NamespacedName{Name: p.KMSMasterKeyIDRef.Name}, key); err != nil {
+			return kerrors.IsNotFound(err), errors.Wrap(err, errGetKMSKey)
+		}
+		return setKMSMasterKeyID(p, key)
+	case p.KMSMasterKeyIDSelector != nil:
+		keys := &unstructured.UnstructuredList{}
+		keys.SetGroupVersionKind(KMSKeyGroupVersionKind.GroupVersion().WithKind(KMSKeyGroupVersionKind.Kind + "List"))
+		if err := c.List(ctx, keys, client.MatchingLabels(p.KMSMasterKeyIDSelector.MatchLabels)); err != nil {
+			return false, errors.Wrap(err, errListKMSKeys)
+		}
+		for i := range keys.Items {
+			key := &keys.Items[i]
+			if reference.ControllersMustMatch(p.KMSMasterKeyIDSelector) && !meta.HaveSameController(mg, key) {
+				continue
+			}
+			p.KMSMasterKeyIDRef = &xpv1.Reference{Name: key.GetName()}
+			return setKMSMasterKeyID(p, key)
+		}
+		return true, errors.New(errNoKMSKeyMatches)
+	}
+	return false, nil
+}
+
+// setKMSMasterKeyID sets the KMSMasterKeyID of the supplied parameters to the
+// key ID of the supplied KMS Key, which is its external name. It reports
+// whether an error is due to the KMS Key not being created or ready yet.
+func setKMSMasterKeyID(p *TopicParameters, key *unstructured.Unstructured) (bool, error) {
+	id := meta.GetExternalName(key)
+	if id == "" {
+		return true, errors.New(errKMSKeyNotCreated)
+	}
+	if !kmsKeyReady(key) {
+		return true, errors.New(errKMSKeyNotReady)
+	}
+	p.KMSMasterKeyID = reference.ToPtrValue(id)
+	return false, nil
+}
+
+// kmsKeyReady returns true if the supplied KMS Key has a Ready condition
+// that is true.
+func kmsKeyReady(key *unstructured.Unstructured) bool {
+	s := xpv1.ConditionedStatus{}
+	if err := fieldpath.Pave(key.Object).GetValueInto("status", &s); err != nil {
+		return false
+	}
+	return s.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue
+}
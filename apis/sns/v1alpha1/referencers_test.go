@@ -0,0 +1,222 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// The managed reconciler resolves references of resources implementing this.
+var _ interface {
+	ResolveReferences(context.Context, client.Reader) error
+} = &Topic{}
+
+const keyID = "1234abcd-12ab-34cd-56ef-1234567890ab"
+
+func kmsKey(name, externalName string, c ...xpv1.Condition) unstructured.Unstructured {
+	u := unstructured.Unstructured{}
+	u.SetGroupVersionKind(KMSKeyGroupVersionKind)
+	u.SetName(name)
+	if externalName != "" {
+		meta.SetExternalName(&u, externalName)
+	}
+	if len(c) > 0 {
+		s := xpv1.ConditionedStatus{}
+		s.SetConditions(c...)
+		if err := fieldpath.Pave(u.Object).SetValue("status", s); err != nil {
+			panic(err)
+		}
+	}
+	return u
+}
+
+func waiting(err error) *xpv1.Condition {
+	c := WaitingOnDependency(err)
+	return &c
+}
+
+func TestTopicResolveReferences(t *testing.T) {
+	errBoom := errors.New("boom")
+	errNotFound := kerrors.NewNotFound(schema.GroupResource{Group: KMSKeyGroupVersionKind.Group, Resource: "keys"}, "key")
+
+	type want struct {
+		p         TopicParameters
+		err       error
+		condition *xpv1.Condition
+	}
+
+	ready := DependenciesReady()
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Reader
+		p      TopicParameters
+		want   want
+	}{
+		"NoReference": {
+			reason: "Nothing should be resolved without a reference or selector.",
+			kube:   &test.MockClient{},
+			p:      TopicParameters{},
+			want:   want{p: TopicParameters{}},
+		},
+		"AlreadyResolved": {
+			reason: "A key ID that is already set should not be resolved again.",
+			kube:   &test.MockClient{},
+			p: TopicParameters{
+				KMSMasterKeyID:    &[]string{"existing"}[0],
+				KMSMasterKeyIDRef: &xpv1.Reference{Name: "key"},
+			},
+			want: want{
+				p: TopicParameters{
+					KMSMasterKeyID:    &[]string{"existing"}[0],
+					KMSMasterKeyIDRef: &xpv1.Reference{Name: "key"},
+				},
+				condition: &ready,
+			},
+		},
+		"Reference": {
+			reason: "A reference should resolve to the external name of the KMS Key.",
+			kube: &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					k := kmsKey("key", keyID, xpv1.Available())
+					k.DeepCopyInto(obj.(*unstructured.Unstructured))
+					return nil
+				},
+			},
+			p: TopicParameters{KMSMasterKeyIDRef: &xpv1.Reference{Name: "key"}},
+			want: want{
+				p: TopicParameters{
+					KMSMasterKeyID:    &[]string{keyID}[0],
+					KMSMasterKeyIDRef: &xpv1.Reference{Name: "key"},
+				},
+				condition: &ready,
+			},
+		},
+		"ReferenceNotCreated": {
+			reason: "A KMS Key without an external name can't be resolved yet.",
+			kube: &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					k := kmsKey("key", "")
+					k.DeepCopyInto(obj.(*unstructured.Unstructured))
+					return nil
+				},
+			},
+			p: TopicParameters{KMSMasterKeyIDRef: &xpv1.Reference{Name: "key"}},
+			want: want{
+				p:         TopicParameters{KMSMasterKeyIDRef: &xpv1.Reference{Name: "key"}},
+				err:       errors.New(errKMSKeyNotCreated),
+				condition: waiting(errors.New(errKMSKeyNotCreated)),
+			},
+		},
+		"ReferenceNotReady": {
+			reason: "A KMS Key that is still being created can't be resolved yet.",
+			kube: &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					k := kmsKey("key", keyID, xpv1.Creating())
+					k.DeepCopyInto(obj.(*unstructured.Unstructured))
+					return nil
+				},
+			},
+			p: TopicParameters{KMSMasterKeyIDRef: &xpv1.Reference{Name: "key"}},
+			want: want{
+				p:         TopicParameters{KMSMasterKeyIDRef: &xpv1.Reference{Name: "key"}},
+				err:       errors.New(errKMSKeyNotReady),
+				condition: waiting(errors.New(errKMSKeyNotReady)),
+			},
+		},
+		"ReferenceNotFound": {
+			reason: "A KMS Key that doesn't exist yet can't be resolved yet.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errNotFound)},
+			p:      TopicParameters{KMSMasterKeyIDRef: &xpv1.Reference{Name: "key"}},
+			want: want{
+				p:         TopicParameters{KMSMasterKeyIDRef: &xpv1.Reference{Name: "key"}},
+				err:       errors.Wrap(errNotFound, errGetKMSKey),
+				condition: waiting(errors.Wrap(errNotFound, errGetKMSKey)),
+			},
+		},
+		"GetFailed": {
+			reason: "Errors getting the referenced KMS Key should be returned.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			p:      TopicParameters{KMSMasterKeyIDRef: &xpv1.Reference{Name: "key"}},
+			want: want{
+				p:   TopicParameters{KMSMasterKeyIDRef: &xpv1.Reference{Name: "key"}},
+				err: errors.Wrap(errBoom, errGetKMSKey),
+			},
+		},
+		"Selector": {
+			reason: "A selector should resolve to the first matching KMS Key and set the reference.",
+			kube: &test.MockClient{
+				MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+					obj.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{kmsKey("key", keyID, xpv1.Available())}
+					return nil
+				},
+			},
+			p: TopicParameters{KMSMasterKeyIDSelector: &xpv1.Selector{MatchLabels: map[string]string{"app": "example"}}},
+			want: want{
+				p: TopicParameters{
+					KMSMasterKeyID:         &[]string{keyID}[0],
+					KMSMasterKeyIDRef:      &xpv1.Reference{Name: "key"},
+					KMSMasterKeyIDSelector: &xpv1.Selector{MatchLabels: map[string]string{"app": "example"}},
+				},
+				condition: &ready,
+			},
+		},
+		"SelectorNoMatches": {
+			reason: "A selector that matches no KMS Key should return an error.",
+			kube:   &test.MockClient{MockList: test.NewMockListFn(nil)},
+			p:      TopicParameters{KMSMasterKeyIDSelector: &xpv1.Selector{}},
+			want: want{
+				p:         TopicParameters{KMSMasterKeyIDSelector: &xpv1.Selector{}},
+				err:       errors.New(errNoKMSKeyMatches),
+				condition: waiting(errors.New(errNoKMSKeyMatches)),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &Topic{Spec: TopicSpec{ForProvider: tc.p}}
+			err := cr.ResolveReferences(context.Background(), tc.kube)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveReferences(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.p, cr.Spec.ForProvider); diff != "" {
+				t.Errorf("\n%s\nResolveReferences(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			var got *xpv1.Condition
+			if c := cr.GetCondition(TypeDependenciesReady); c.Status != corev1.ConditionUnknown {
+				got = &c
+			}
+			if diff := cmp.Diff(tc.want.condition, got, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nResolveReferences(...): -want condition, +got condition:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Enum for subscription attributes
+const (
+	SubscriptionRawMessageDelivery  = "RawMessageDelivery"
+	SubscriptionFilterPolicy        = "FilterPolicy"
+	SubscriptionPendingConfirmation = "PendingConfirmation"
+	SubscriptionOwner               = "Owner"
+	SubscriptionArn                 = "SubscriptionArn"
+
+	SubscriptionConfirmationWasAuthenticated = "ConfirmationWasAuthenticated"
+)
+
+// SubscriptionPendingConfirmationArn is the value SNS returns in place of a
+// SubscriptionArn until the endpoint confirms the subscription.
+const SubscriptionPendingConfirmationArn = "pending confirmation"
+
+// SubscriptionParameters are the configurable fields of a Subscription.
+type SubscriptionParameters struct {
+	Region string `json:"region"`
+
+	// TopicArn is the ARN of the topic to subscribe to.
+	// +immutable
+	TopicArn string `json:"topicArn"`
+
+	// Protocol is the protocol used to deliver messages to the endpoint.
+	// +immutable
+	// +kubebuilder:validation:Enum=http;https;email;email-json;sms;sqs;application;lambda;firehose
+	Protocol string `json:"protocol"`
+
+	// Endpoint that receives the notifications. Its format depends on the
+	// protocol, e.g. an URL for http, an email address for email or an ARN
+	// for sqs and lambda.
+	// +immutable
+	// +optional
+	Endpoint *string `json:"endpoint,omitempty"`
+
+	// RawMessageDelivery disables the JSON envelope SNS adds to the
+	// messages delivered to sqs, http and https endpoints.
+	// +optional
+	RawMessageDelivery *bool `json:"rawMessageDelivery,omitempty"`
+
+	// FilterPolicy is the JSON filter policy that selects the messages
+	// delivered to the endpoint.
+	// +optional
+	FilterPolicy *string `json:"filterPolicy,omitempty"`
+
+	// MaintenanceWindow during which the Subscription is only observed.
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+
+	// ObserveOnly, if true, only observes the Subscription. It's neither created,
+	// updated nor deleted; what would have been done is logged instead.
+	// +optional
+	ObserveOnly *bool `json:"observeOnly,omitempty"`
+}
+
+// SubscriptionObservation are the observable fields of a Subscription.
+type SubscriptionObservation struct {
+	// SubscriptionArn – The subscription's ARN
+	SubscriptionArn *string `json:"subscriptionArn,omitempty"`
+
+	// Owner – The AWS account ID of the subscription's owner.
+	Owner *string `json:"owner,omitempty"`
+
+	// PendingConfirmation – True if the subscription hasn't been
+	// confirmed by its endpoint yet.
+	PendingConfirmation *bool `json:"pendingConfirmation,omitempty"`
+
+	// ConfirmationWasAuthenticated is true if the subscription was confirmed
+	// with an authenticated request, i.e. only the owner of the topic can
+	// unsubscribe the endpoint. It's not observed until the subscription is
+	// confirmed.
+	ConfirmationWasAuthenticated *bool `json:"confirmationWasAuthenticated,omitempty"`
+}
+
+// A SubscriptionSpec defines the desired state of a Subscription.
+type SubscriptionSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       SubscriptionParameters `json:"forProvider"`
+}
+
+// A SubscriptionStatus represents the observed state of a Subscription.
+type SubscriptionStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          SubscriptionObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Subscription subscribes an endpoint to an SNS Topic.
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,template}
+type Subscription struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SubscriptionSpec   `json:"spec"`
+	Status SubscriptionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SubscriptionList contains a list of Subscriptions
+type SubscriptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Subscription `json:"items"`
+}
+
+// Subscription type metadata.
+var (
+	SubscriptionKind             = reflect.TypeOf(Subscription{}).Name()
+	SubscriptionGroupKind        = schema.GroupKind{Group: Group, Kind: SubscriptionKind}.String()
+	SubscriptionKindAPIVersion   = SubscriptionKind + "." + SchemeGroupVersion.String()
+	SubscriptionGroupVersionKind = SchemeGroupVersion.WithKind(SubscriptionKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Subscription{}, &SubscriptionList{})
+}
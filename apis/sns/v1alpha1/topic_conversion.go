@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+const errNotTopicHub = "conversion hub is not a v1alpha1 Topic"
+
+// Hub marks v1alpha1 as the version every other Topic version converts
+// through.
+func (*Topic) Hub() {}
+
+// ConvertTo converts this Topic to the hub version. v1alpha1 is currently
+// the only version so the conversion is an identity copy. Newer versions
+// should implement ConvertTo and ConvertFrom the same way, mapping their
+// fields onto the v1alpha1 Topic.
+func (tr *Topic) ConvertTo(dst conversion.Hub) error {
+	hub, ok := dst.(*Topic)
+	if !ok {
+		return errors.New(errNotTopicHub)
+	}
+	tr.DeepCopyInto(hub)
+	return nil
+}
+
+// ConvertFrom converts the hub version to this Topic.
+func (tr *Topic) ConvertFrom(src conversion.Hub) error {
+	hub, ok := src.(*Topic)
+	if !ok {
+		return errors.New(errNotTopicHub)
+	}
+	hub.DeepCopyInto(tr)
+	return nil
+}
+
+// SetupWebhookWithManager registers the conversion and validating webhooks
+// for Topics with the supplied manager.
+func (tr *Topic) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(tr).
+		Complete()
+}
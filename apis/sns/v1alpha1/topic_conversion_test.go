@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+type otherHub struct{ Topic }
+
+func (*otherHub) Hub() {}
+
+func topic() *Topic {
+	name := "example"
+	fifo := true
+	arn := "arn:aws:sns:us-west-2:123456789012:example"
+	return &Topic{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "example",
+			Annotations: map[string]string{"crossplane.io/external-name": arn},
+		},
+		Spec: TopicSpec{
+			ResourceSpec: xpv1.ResourceSpec{
+				ProviderConfigReference: &xpv1.Reference{Name: "default"},
+			},
+			ForProvider: TopicParameters{
+				Region:      "us-west-2",
+				DisplayName: &name,
+				FifoTopic:   &fifo,
+				Tags:        map[string]string{"owner": "orchestration"},
+			},
+		},
+		Status: TopicStatus{
+			ResourceStatus: xpv1.ResourceStatus{
+				ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}},
+			},
+			AtProvider: TopicObservation{TopicArn: &arn},
+		},
+	}
+}
+
+func TestTopicConversionRoundTrip(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		hub    conversion.Hub
+		want   error
+	}{
+		"RoundTrip": {
+			reason: "Converting to the hub and back should not lose any fields.",
+			hub:    &Topic{},
+		},
+		"WrongHub": {
+			reason: "Converting to a hub that is not a v1alpha1 Topic should fail.",
+			hub:    &otherHub{},
+			want:   errors.New(errNotTopicHub),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			in := topic()
+			err := in.ConvertTo(tc.hub)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nConvertTo(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			out := &Topic{}
+			if err := out.ConvertFrom(tc.hub); err != nil {
+				t.Fatalf("\n%s\nConvertFrom(...): %s\n", tc.reason, err)
+			}
+			if diff := cmp.Diff(in, out); diff != "" {
+				t.Errorf("\n%s\nConvertFrom(ConvertTo(...)): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
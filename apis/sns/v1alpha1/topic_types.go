@@ -36,18 +36,208 @@ const(
 	TopicSubscriptionPending = "SubscriptionsPending"
 	TopicEffectiveDeliveryPolicy = "EffectiveDeliveryPolicy"
 	TopicArn = "TopicArn"
+	TopicOwner = "Owner"
+	TopicSignatureVersion = "SignatureVersion"
+	TopicTracingConfig = "TracingConfig"
+	TopicArchivePolicy = "ArchivePolicy"
+	TopicBeginningArchiveTime = "BeginningArchiveTime"
+)
+
+// Signature versions SNS signs the notifications it publishes with.
+const (
+	SignatureVersion1 = "1"
+	SignatureVersion2 = "2"
+)
+
+// Tracing modes of a topic.
+const (
+	// TracingConfigPassThrough passes through the tracing header SNS
+	// receives from its caller. It's the default.
+	TracingConfigPassThrough = "PassThrough"
+
+	// TracingConfigActive traces every message with AWS X-Ray.
+	TracingConfigActive = "Active"
+)
+
+// The delivery status logging attributes of a topic are named after the
+// protocol they configure, e.g. HTTPSuccessFeedbackRoleArn.
+const (
+	DeliveryStatusProtocolHTTP        = "HTTP"
+	DeliveryStatusProtocolSQS         = "SQS"
+	DeliveryStatusProtocolLambda      = "Lambda"
+	DeliveryStatusProtocolApplication = "Application"
+	DeliveryStatusProtocolFirehose    = "Firehose"
+
+	TopicSuccessFeedbackRoleArn    = "SuccessFeedbackRoleArn"
+	TopicSuccessFeedbackSampleRate = "SuccessFeedbackSampleRate"
+	TopicFailureFeedbackRoleArn    = "FailureFeedbackRoleArn"
 )
 
 //TopicParameters are the configurable fields of an Topic.
 type TopicParameters struct {
 	Region string `json:"region"`
+
+	// DeliveryPolicy is the JSON delivery policy of http and https
+	// subscriptions. Setting it to an empty string leaves the topic's
+	// delivery policy as it is, since SNS doesn't accept an empty one.
 	DeliveryPolicy *string `json:"deliveryPolicy,omitempty"`
+
+	// DisplayName of the topic. Setting it to an empty string removes the
+	// display name.
 	DisplayName *string `json:"displayName,omitempty"`
+
+	// Policy is the JSON access policy of the topic. Setting it to an empty
+	// string leaves the topic's policy as it is, since SNS doesn't accept an
+	// empty one.
 	Policy *string `json:"policy,omitempty"`
+
 	FifoTopic *bool `json:"fifoTopic,omitempty"`
 	ContentBasedDeduplication *bool `json:"contentBasedDeduplication,omitempty"`
+
+	// KMSMasterKeyID is the KMS key topic messages are encrypted with.
+	// Setting it to an empty string disables server-side encryption.
 	KMSMasterKeyID *string `json:"kmsMasterKeyId,omitempty"`
+
+	// KMSMasterKeyIDRef references a KMS Key to retrieve its key ID.
+	// +optional
+	KMSMasterKeyIDRef *xpv1.Reference `json:"kmsMasterKeyIdRef,omitempty"`
+
+	// KMSMasterKeyIDSelector selects a reference to a KMS Key to retrieve
+	// its key ID.
+	// +optional
+	KMSMasterKeyIDSelector *xpv1.Selector `json:"kmsMasterKeyIdSelector,omitempty"`
+
 	Tags map[string]string `json:"tags,omitempty"`
+
+	// MaintenanceWindow during which the Topic is only observed.
+	// +optional
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+
+	// ObserveOnly, if true, only observes the Topic. It's neither created,
+	// updated nor deleted; what would have been done is logged instead.
+	// +optional
+	ObserveOnly *bool `json:"observeOnly,omitempty"`
+
+	// DeliveryStatusLogging configures the logging of the delivery status
+	// of messages to CloudWatch Logs, for each protocol.
+	// +optional
+	DeliveryStatusLogging *DeliveryStatusLogging `json:"deliveryStatusLogging,omitempty"`
+
+	// SignatureVersion of the signatures of the notifications the topic
+	// publishes. Version 1 signs with SHA1, version 2 with SHA256.
+	// +optional
+	// +kubebuilder:validation:Enum="1";"2"
+	SignatureVersion *string `json:"signatureVersion,omitempty"`
+
+	// TracingConfig is how messages published to the topic are traced with
+	// AWS X-Ray. PassThrough, the default, passes through the tracing header
+	// of the publisher, and Active traces every message.
+	// +optional
+	// +kubebuilder:validation:Enum=PassThrough;Active
+	TracingConfig *string `json:"tracingConfig,omitempty"`
+
+	// ArchivePolicy is the JSON policy that archives the messages published
+	// to the topic so they can be replayed, e.g. {"MessageRetentionPeriod":
+	// "30"}. Only FIFO topics support it. Setting it to an empty string
+	// leaves the topic's archive policy as it is; set it to {} to stop
+	// archiving.
+	// +optional
+	ArchivePolicy *string `json:"archivePolicy,omitempty"`
+
+	// ARNChangePolicy decides what happens if the external name of the Topic
+	// implies another ARN than that of the topic it manages. Refuse, the
+	// default, stops reconciling the Topic until the change is reverted.
+	// Adopt manages the topic of the new ARN from then on, and leaves the
+	// previous topic as it is. A Topic whose region isn't that of its ARN is
+	// never reconciled.
+	// +optional
+	ARNChangePolicy *ARNChangePolicy `json:"arnChangePolicy,omitempty"`
+
+	// ExistingResourcePolicy decides what happens if a topic of the same
+	// name already exists when the Topic is first reconciled, e.g. because
+	// it was created with the console or another tool. Adopt, the default,
+	// manages the existing topic. Fail never manages a topic the Topic
+	// didn't create, including one whose creation was interrupted before
+	// its ARN was recorded; set the external name to its ARN to import it.
+	// +optional
+	ExistingResourcePolicy *ExistingResourcePolicy `json:"existingResourcePolicy,omitempty"`
+}
+
+// An ARNChangePolicy decides what happens if a Topic implies another ARN
+// than that of the topic it manages.
+// +kubebuilder:validation:Enum=Refuse;Adopt
+type ARNChangePolicy string
+
+// ARN change policies.
+const (
+	// ARNChangePolicyRefuse stops reconciling a Topic whose ARN changed.
+	ARNChangePolicyRefuse ARNChangePolicy = "Refuse"
+
+	// ARNChangePolicyAdopt manages the topic of the new ARN.
+	ARNChangePolicyAdopt ARNChangePolicy = "Adopt"
+)
+
+// An ExistingResourcePolicy decides what happens if a topic of the same name
+// as a Topic already exists when the Topic is first reconciled.
+// +kubebuilder:validation:Enum=Adopt;Fail
+type ExistingResourcePolicy string
+
+// Existing resource policies.
+const (
+	// ExistingResourcePolicyAdopt manages the existing topic.
+	ExistingResourcePolicyAdopt ExistingResourcePolicy = "Adopt"
+
+	// ExistingResourcePolicyFail fails to create the Topic.
+	ExistingResourcePolicyFail ExistingResourcePolicy = "Fail"
+)
+
+// DeliveryStatusLogging configures the logging of the delivery status of
+// messages for each protocol SNS supports it for. Protocols that aren't
+// configured are left as they are.
+type DeliveryStatusLogging struct {
+	// HTTP configures the logging of deliveries to http and https
+	// endpoints.
+	// +optional
+	HTTP *DeliveryStatusLoggingConfig `json:"http,omitempty"`
+
+	// SQS configures the logging of deliveries to SQS queues.
+	// +optional
+	SQS *DeliveryStatusLoggingConfig `json:"sqs,omitempty"`
+
+	// Lambda configures the logging of deliveries to Lambda functions.
+	// +optional
+	Lambda *DeliveryStatusLoggingConfig `json:"lambda,omitempty"`
+
+	// Application configures the logging of deliveries to platform
+	// application endpoints.
+	// +optional
+	Application *DeliveryStatusLoggingConfig `json:"application,omitempty"`
+
+	// Firehose configures the logging of deliveries to Kinesis Data
+	// Firehose delivery streams.
+	// +optional
+	Firehose *DeliveryStatusLoggingConfig `json:"firehose,omitempty"`
+}
+
+// DeliveryStatusLoggingConfig configures the logging of the delivery status
+// of messages for a protocol.
+type DeliveryStatusLoggingConfig struct {
+	// SuccessFeedbackRoleARN is the IAM role SNS assumes to log successful
+	// deliveries.
+	// +optional
+	SuccessFeedbackRoleARN *string `json:"successFeedbackRoleArn,omitempty"`
+
+	// SuccessFeedbackSampleRate is the percentage of successful deliveries
+	// that are logged.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	SuccessFeedbackSampleRate *int32 `json:"successFeedbackSampleRate,omitempty"`
+
+	// FailureFeedbackRoleARN is the IAM role SNS assumes to log failed
+	// deliveries.
+	// +optional
+	FailureFeedbackRoleARN *string `json:"failureFeedbackRoleArn,omitempty"`
 }
 
 //TopicObservation are the observable fields of an Topic.
@@ -55,6 +245,16 @@ type TopicObservation struct {
 	// TopicArn – The topic's ARN
 	TopicArn *string `json:"topicArn"`
 
+	// Name of the topic, including the .fifo suffix of FIFO topics.
+	Name *string `json:"name,omitempty"`
+
+	// BaseName is the name of the topic without the .fifo suffix of FIFO
+	// topics.
+	BaseName *string `json:"baseName,omitempty"`
+
+	// Owner is the ID of the AWS account that owns the topic.
+	Owner *string `json:"owner,omitempty"`
+
 	// SubscriptionsConfirmed – The number of
 	// confirmed subscriptions for the topic.
 	SubscriptionsConfirmed *int `json:"subscriptionsConfirmed,omitempty"`
@@ -70,6 +270,99 @@ type TopicObservation struct {
 	// EffectiveDeliveryPolicy – The JSON serialization of the effective
 	// delivery policy, taking system defaults into account.
 	EffectiveDeliveryPolicy *string `json:"effectiveDeliveryPolicy,omitempty"`
+
+	// EffectiveHTTPDeliveryPolicy is the http section of the effective
+	// delivery policy, i.e. how deliveries to http and https endpoints are
+	// retried. It's not observed if the effective delivery policy can't be
+	// parsed.
+	EffectiveHTTPDeliveryPolicy *DeliveryPolicyObservation `json:"effectiveHTTPDeliveryPolicy,omitempty"`
+
+	// UsesCustomerManagedKey is true if the topic is encrypted with a
+	// customer managed KMS key, and false if it's encrypted with the AWS
+	// managed key of SNS. It's not observed if the topic isn't encrypted.
+	UsesCustomerManagedKey *bool `json:"usesCustomerManagedKey,omitempty"`
+
+	// KMSMasterKeyID is the KMS key the topic is encrypted with. It's not
+	// observed if the topic isn't encrypted.
+	KMSMasterKeyID *string `json:"kmsMasterKeyId,omitempty"`
+
+	// BeginningArchiveTime is the earliest time messages published to the
+	// topic can be replayed from. It's only observed for topics that archive
+	// messages.
+	BeginningArchiveTime *string `json:"beginningArchiveTime,omitempty"`
+
+	// SubscriptionsByProtocol is the number of subscriptions to the topic for
+	// each protocol, e.g. sqs or https. It's only observed if the provider is
+	// configured to list the subscriptions of topics.
+	SubscriptionsByProtocol map[string]int `json:"subscriptionsByProtocol,omitempty"`
+
+	// Tags are the tags of the topic, as observed.
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// TagsObservedAt is when the tags were first observed as they are now.
+	// SNS doesn't record when tags change, so this is only updated when a
+	// change is observed and roughly infers when the tags last changed.
+	TagsObservedAt *metav1.Time `json:"tagsObservedAt,omitempty"`
+
+	// CostTags summarizes the cost allocation tags of the topic that the
+	// ProviderConfig recommends. It's only observed if the ProviderConfig
+	// recommends any.
+	CostTags *CostTagsObservation `json:"costTags,omitempty"`
+}
+
+// A DeliveryPolicyObservation is the structured observation of a delivery
+// policy.
+type DeliveryPolicyObservation struct {
+	// HealthyRetryPolicy is how failed deliveries are retried.
+	HealthyRetryPolicy *RetryPolicyObservation `json:"healthyRetryPolicy,omitempty"`
+
+	// DisableSubscriptionOverrides is true if subscriptions can't override
+	// the delivery policy of the topic.
+	DisableSubscriptionOverrides *bool `json:"disableSubscriptionOverrides,omitempty"`
+
+	// MaxReceivesPerSecond is the maximum number of deliveries per second
+	// to a subscription.
+	MaxReceivesPerSecond *int `json:"maxReceivesPerSecond,omitempty"`
+}
+
+// A RetryPolicyObservation is the observed retry behaviour of a delivery
+// policy.
+type RetryPolicyObservation struct {
+	// NumRetries is the total number of retries, including those without
+	// delay, with the minimum delay, backing off and with the maximum delay.
+	NumRetries *int `json:"numRetries,omitempty"`
+
+	// NumNoDelayRetries is the number of retries made immediately.
+	NumNoDelayRetries *int `json:"numNoDelayRetries,omitempty"`
+
+	// NumMinDelayRetries is the number of retries made with the minimum
+	// delay.
+	NumMinDelayRetries *int `json:"numMinDelayRetries,omitempty"`
+
+	// NumMaxDelayRetries is the number of retries made with the maximum
+	// delay.
+	NumMaxDelayRetries *int `json:"numMaxDelayRetries,omitempty"`
+
+	// MinDelayTarget is the minimum delay of a retry, in seconds.
+	MinDelayTarget *int `json:"minDelayTarget,omitempty"`
+
+	// MaxDelayTarget is the maximum delay of a retry, in seconds.
+	MaxDelayTarget *int `json:"maxDelayTarget,omitempty"`
+
+	// BackoffFunction is how the delay grows between the minimum and the
+	// maximum delay, i.e. arithmetic, exponential, geometric or linear.
+	BackoffFunction *string `json:"backoffFunction,omitempty"`
+}
+
+// A CostTagsObservation summarizes the recommended cost allocation tags of a
+// resource.
+type CostTagsObservation struct {
+	// Present are the recommended cost allocation tags the resource has.
+	Present map[string]string `json:"present,omitempty"`
+
+	// Missing are the keys of the recommended cost allocation tags the
+	// resource doesn't have.
+	Missing []string `json:"missing,omitempty"`
 }
 
 
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const fifoTopicSuffix = ".fifo"
+
+// +kubebuilder:webhook:path=/validate-sns-awscontrolapi-crossplane-io-v1alpha1-topic,mutating=false,failurePolicy=fail,sideEffects=None,groups=sns.awscontrolapi.crossplane.io,resources=topics,verbs=create;update,versions=v1alpha1,name=topics.sns.awscontrolapi.crossplane.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &Topic{}
+
+// ValidateCreate rejects Topics whose FIFO settings SNS wouldn't accept.
+func (tr *Topic) ValidateCreate() error {
+	return tr.validateFIFO()
+}
+
+// ValidateUpdate rejects Topics whose FIFO settings SNS wouldn't accept.
+// Topics that are being deleted aren't validated, so that their finalizer
+// can be removed even if they were admitted before this validation existed.
+func (tr *Topic) ValidateUpdate(_ runtime.Object) error {
+	if meta.WasDeleted(tr) {
+		return nil
+	}
+	return tr.validateFIFO()
+}
+
+// ValidateDelete accepts every deletion.
+func (tr *Topic) ValidateDelete() error {
+	return nil
+}
+
+// validateFIFO checks that only FIFO topics enable content-based
+// deduplication, and that the name of a topic ends with .fifo if and only if
+// it's a FIFO topic.
+func (tr *Topic) validateFIFO() error {
+	var errs field.ErrorList
+	fp := field.NewPath("spec", "forProvider")
+	fifo := tr.Spec.ForProvider.FifoTopic != nil && *tr.Spec.ForProvider.FifoTopic
+
+	if d := tr.Spec.ForProvider.ContentBasedDeduplication; d != nil && *d && !fifo {
+		errs = append(errs, field.Invalid(fp.Child("contentBasedDeduplication"), *d, "can only be enabled for FIFO topics"))
+	}
+
+	name, path := tr.topicName()
+	switch {
+	case fifo && !strings.HasSuffix(name, fifoTopicSuffix):
+		errs = append(errs, field.Invalid(path, name, "the name of a FIFO topic must end with "+fifoTopicSuffix))
+	case !fifo && strings.HasSuffix(name, fifoTopicSuffix):
+		errs = append(errs, field.Invalid(path, name, "only FIFO topics can have a name ending with "+fifoTopicSuffix))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return kerrors.NewInvalid(TopicGroupVersionKind.GroupKind(), tr.GetName(), errs)
+}
+
+// topicName returns the name the topic is created with and the path of the
+// field it's taken from. That's the external name, which may be the ARN of
+// the topic, or the name of the object if the external name isn't set.
+func (tr *Topic) topicName() (string, *field.Path) {
+	name := meta.GetExternalName(tr)
+	if a, err := arn.Parse(name); err == nil {
+		name = a.Resource
+	}
+	if name == "" {
+		return tr.GetName(), field.NewPath("metadata", "name")
+	}
+	return name, field.NewPath("metadata", "annotations").Key(meta.AnnotationKeyExternalName)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func webhookTopic(name, externalName string, fifo, dedup *bool) *Topic {
+	tr := &Topic{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	meta.SetExternalName(tr, externalName)
+	tr.Spec.ForProvider.FifoTopic = fifo
+	tr.Spec.ForProvider.ContentBasedDeduplication = dedup
+	return tr
+}
+
+func invalidTopic(name string, errs ...*field.Error) error {
+	return kerrors.NewInvalid(TopicGroupVersionKind.GroupKind(), name, errs)
+}
+
+func TestTopicValidate(t *testing.T) {
+	yes, no := true, false
+	name := field.NewPath("metadata", "name")
+	externalName := field.NewPath("metadata", "annotations").Key(meta.AnnotationKeyExternalName)
+	dedup := field.NewPath("spec", "forProvider", "contentBasedDeduplication")
+
+	cases := map[string]struct {
+		reason string
+		tr     *Topic
+		want   error
+	}{
+		"StandardTopic": {
+			reason: "A standard topic without content-based deduplication should be accepted.",
+			tr:     webhookTopic("orders", "", nil, nil),
+		},
+		"FIFOTopic": {
+			reason: "A FIFO topic named with the .fifo suffix should be accepted.",
+			tr:     webhookTopic("orders.fifo", "", &yes, &yes),
+		},
+		"DeduplicationWithoutFIFO": {
+			reason: "Content-based deduplication should be rejected for standard topics.",
+			tr:     webhookTopic("orders", "", &no, &yes),
+			want:   invalidTopic("orders", field.Invalid(dedup, true, "can only be enabled for FIFO topics")),
+		},
+		"FIFOWithoutSuffix": {
+			reason: "A FIFO topic whose name doesn't end with .fifo should be rejected.",
+			tr:     webhookTopic("orders", "", &yes, nil),
+			want:   invalidTopic("orders", field.Invalid(name, "orders", "the name of a FIFO topic must end with .fifo")),
+		},
+		"SuffixWithoutFIFO": {
+			reason: "A standard topic whose name ends with .fifo should be rejected.",
+			tr:     webhookTopic("orders.fifo", "", nil, &yes),
+			want: invalidTopic("orders.fifo",
+				field.Invalid(dedup, true, "can only be enabled for FIFO topics"),
+				field.Invalid(name, "orders.fifo", "only FIFO topics can have a name ending with .fifo"),
+			),
+		},
+		"ExternalNameTakesPrecedence": {
+			reason: "The external name, rather than the object name, should be validated if it's set.",
+			tr:     webhookTopic("orders-fifo", "orders", &yes, nil),
+			want:   invalidTopic("orders-fifo", field.Invalid(externalName, "orders", "the name of a FIFO topic must end with .fifo")),
+		},
+		"ExternalNameARN": {
+			reason: "The topic name should be taken from the ARN set as the external name.",
+			tr:     webhookTopic("orders", "arn:aws:sns:us-east-1:123456789012:orders.fifo", &yes, nil),
+		},
+	}
+	for n, tc := range cases {
+		t.Run(n, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.tr.ValidateCreate(), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateCreate(): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.tr.ValidateUpdate(tc.tr), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateUpdate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTopicValidateUpdateDeleted(t *testing.T) {
+	yes := true
+	tr := webhookTopic("orders", "", &yes, nil)
+	now := metav1.Now()
+	tr.SetDeletionTimestamp(&now)
+	if err := tr.ValidateUpdate(tr); err != nil {
+		t.Errorf("ValidateUpdate(...): a Topic that is being deleted should be accepted, got %v", err)
+	}
+}
@@ -22,9 +22,376 @@ limitations under the License.
 package v1alpha1
 
 import (
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostTagsObservation) DeepCopyInto(out *CostTagsObservation) {
+	*out = *in
+	if in.Present != nil {
+		in, out := &in.Present, &out.Present
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Missing != nil {
+		in, out := &in.Missing, &out.Missing
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostTagsObservation.
+func (in *CostTagsObservation) DeepCopy() *CostTagsObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(CostTagsObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeliveryPolicyObservation) DeepCopyInto(out *DeliveryPolicyObservation) {
+	*out = *in
+	if in.HealthyRetryPolicy != nil {
+		in, out := &in.HealthyRetryPolicy, &out.HealthyRetryPolicy
+		*out = new(RetryPolicyObservation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisableSubscriptionOverrides != nil {
+		in, out := &in.DisableSubscriptionOverrides, &out.DisableSubscriptionOverrides
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxReceivesPerSecond != nil {
+		in, out := &in.MaxReceivesPerSecond, &out.MaxReceivesPerSecond
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeliveryPolicyObservation.
+func (in *DeliveryPolicyObservation) DeepCopy() *DeliveryPolicyObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(DeliveryPolicyObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeliveryStatusLogging) DeepCopyInto(out *DeliveryStatusLogging) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(DeliveryStatusLoggingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SQS != nil {
+		in, out := &in.SQS, &out.SQS
+		*out = new(DeliveryStatusLoggingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Lambda != nil {
+		in, out := &in.Lambda, &out.Lambda
+		*out = new(DeliveryStatusLoggingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Application != nil {
+		in, out := &in.Application, &out.Application
+		*out = new(DeliveryStatusLoggingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Firehose != nil {
+		in, out := &in.Firehose, &out.Firehose
+		*out = new(DeliveryStatusLoggingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeliveryStatusLogging.
+func (in *DeliveryStatusLogging) DeepCopy() *DeliveryStatusLogging {
+	if in == nil {
+		return nil
+	}
+	out := new(DeliveryStatusLogging)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeliveryStatusLoggingConfig) DeepCopyInto(out *DeliveryStatusLoggingConfig) {
+	*out = *in
+	if in.SuccessFeedbackRoleARN != nil {
+		in, out := &in.SuccessFeedbackRoleARN, &out.SuccessFeedbackRoleARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.SuccessFeedbackSampleRate != nil {
+		in, out := &in.SuccessFeedbackSampleRate, &out.SuccessFeedbackSampleRate
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureFeedbackRoleARN != nil {
+		in, out := &in.FailureFeedbackRoleARN, &out.FailureFeedbackRoleARN
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeliveryStatusLoggingConfig.
+func (in *DeliveryStatusLoggingConfig) DeepCopy() *DeliveryStatusLoggingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DeliveryStatusLoggingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]MaintenanceWindowDay, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicyObservation) DeepCopyInto(out *RetryPolicyObservation) {
+	*out = *in
+	if in.NumRetries != nil {
+		in, out := &in.NumRetries, &out.NumRetries
+		*out = new(int)
+		**out = **in
+	}
+	if in.NumNoDelayRetries != nil {
+		in, out := &in.NumNoDelayRetries, &out.NumNoDelayRetries
+		*out = new(int)
+		**out = **in
+	}
+	if in.NumMinDelayRetries != nil {
+		in, out := &in.NumMinDelayRetries, &out.NumMinDelayRetries
+		*out = new(int)
+		**out = **in
+	}
+	if in.NumMaxDelayRetries != nil {
+		in, out := &in.NumMaxDelayRetries, &out.NumMaxDelayRetries
+		*out = new(int)
+		**out = **in
+	}
+	if in.MinDelayTarget != nil {
+		in, out := &in.MinDelayTarget, &out.MinDelayTarget
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxDelayTarget != nil {
+		in, out := &in.MaxDelayTarget, &out.MaxDelayTarget
+		*out = new(int)
+		**out = **in
+	}
+	if in.BackoffFunction != nil {
+		in, out := &in.BackoffFunction, &out.BackoffFunction
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicyObservation.
+func (in *RetryPolicyObservation) DeepCopy() *RetryPolicyObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicyObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Subscription) DeepCopyInto(out *Subscription) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subscription.
+func (in *Subscription) DeepCopy() *Subscription {
+	if in == nil {
+		return nil
+	}
+	out := new(Subscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Subscription) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionList) DeepCopyInto(out *SubscriptionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Subscription, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionList.
+func (in *SubscriptionList) DeepCopy() *SubscriptionList {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubscriptionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionObservation) DeepCopyInto(out *SubscriptionObservation) {
+	*out = *in
+	if in.SubscriptionArn != nil {
+		in, out := &in.SubscriptionArn, &out.SubscriptionArn
+		*out = new(string)
+		**out = **in
+	}
+	if in.Owner != nil {
+		in, out := &in.Owner, &out.Owner
+		*out = new(string)
+		**out = **in
+	}
+	if in.PendingConfirmation != nil {
+		in, out := &in.PendingConfirmation, &out.PendingConfirmation
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ConfirmationWasAuthenticated != nil {
+		in, out := &in.ConfirmationWasAuthenticated, &out.ConfirmationWasAuthenticated
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionObservation.
+func (in *SubscriptionObservation) DeepCopy() *SubscriptionObservation {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionObservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionParameters) DeepCopyInto(out *SubscriptionParameters) {
+	*out = *in
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(string)
+		**out = **in
+	}
+	if in.RawMessageDelivery != nil {
+		in, out := &in.RawMessageDelivery, &out.RawMessageDelivery
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FilterPolicy != nil {
+		in, out := &in.FilterPolicy, &out.FilterPolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObserveOnly != nil {
+		in, out := &in.ObserveOnly, &out.ObserveOnly
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionParameters.
+func (in *SubscriptionParameters) DeepCopy() *SubscriptionParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionSpec) DeepCopyInto(out *SubscriptionSpec) {
+	*out = *in
+	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionSpec.
+func (in *SubscriptionSpec) DeepCopy() *SubscriptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionStatus) DeepCopyInto(out *SubscriptionStatus) {
+	*out = *in
+	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	in.AtProvider.DeepCopyInto(&out.AtProvider)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionStatus.
+func (in *SubscriptionStatus) DeepCopy() *SubscriptionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Topic) DeepCopyInto(out *Topic) {
 	*out = *in
@@ -92,6 +459,21 @@ func (in *TopicObservation) DeepCopyInto(out *TopicObservation) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.BaseName != nil {
+		in, out := &in.BaseName, &out.BaseName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Owner != nil {
+		in, out := &in.Owner, &out.Owner
+		*out = new(string)
+		**out = **in
+	}
 	if in.SubscriptionsConfirmed != nil {
 		in, out := &in.SubscriptionsConfirmed, &out.SubscriptionsConfirmed
 		*out = new(int)
@@ -112,6 +494,49 @@ func (in *TopicObservation) DeepCopyInto(out *TopicObservation) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.EffectiveHTTPDeliveryPolicy != nil {
+		in, out := &in.EffectiveHTTPDeliveryPolicy, &out.EffectiveHTTPDeliveryPolicy
+		*out = new(DeliveryPolicyObservation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UsesCustomerManagedKey != nil {
+		in, out := &in.UsesCustomerManagedKey, &out.UsesCustomerManagedKey
+		*out = new(bool)
+		**out = **in
+	}
+	if in.KMSMasterKeyID != nil {
+		in, out := &in.KMSMasterKeyID, &out.KMSMasterKeyID
+		*out = new(string)
+		**out = **in
+	}
+	if in.BeginningArchiveTime != nil {
+		in, out := &in.BeginningArchiveTime, &out.BeginningArchiveTime
+		*out = new(string)
+		**out = **in
+	}
+	if in.SubscriptionsByProtocol != nil {
+		in, out := &in.SubscriptionsByProtocol, &out.SubscriptionsByProtocol
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TagsObservedAt != nil {
+		in, out := &in.TagsObservedAt, &out.TagsObservedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CostTags != nil {
+		in, out := &in.CostTags, &out.CostTags
+		*out = new(CostTagsObservation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopicObservation.
@@ -157,6 +582,16 @@ func (in *TopicParameters) DeepCopyInto(out *TopicParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.KMSMasterKeyIDRef != nil {
+		in, out := &in.KMSMasterKeyIDRef, &out.KMSMasterKeyIDRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.KMSMasterKeyIDSelector != nil {
+		in, out := &in.KMSMasterKeyIDSelector, &out.KMSMasterKeyIDSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Tags != nil {
 		in, out := &in.Tags, &out.Tags
 		*out = make(map[string]string, len(*in))
@@ -164,6 +599,46 @@ func (in *TopicParameters) DeepCopyInto(out *TopicParameters) {
 			(*out)[key] = val
 		}
 	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObserveOnly != nil {
+		in, out := &in.ObserveOnly, &out.ObserveOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DeliveryStatusLogging != nil {
+		in, out := &in.DeliveryStatusLogging, &out.DeliveryStatusLogging
+		*out = new(DeliveryStatusLogging)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SignatureVersion != nil {
+		in, out := &in.SignatureVersion, &out.SignatureVersion
+		*out = new(string)
+		**out = **in
+	}
+	if in.TracingConfig != nil {
+		in, out := &in.TracingConfig, &out.TracingConfig
+		*out = new(string)
+		**out = **in
+	}
+	if in.ArchivePolicy != nil {
+		in, out := &in.ArchivePolicy, &out.ArchivePolicy
+		*out = new(string)
+		**out = **in
+	}
+	if in.ARNChangePolicy != nil {
+		in, out := &in.ARNChangePolicy, &out.ARNChangePolicy
+		*out = new(ARNChangePolicy)
+		**out = **in
+	}
+	if in.ExistingResourcePolicy != nil {
+		in, out := &in.ExistingResourcePolicy, &out.ExistingResourcePolicy
+		*out = new(ExistingResourcePolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopicParameters.
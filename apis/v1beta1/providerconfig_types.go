@@ -34,25 +34,235 @@ type ProviderConfigSpec struct {
 	// +optional
 	AssumeRoleARN *string `json:"assumeRoleARN,omitempty"`
 
+	// AssumeRoleARNChain are roles assumed in order with the provider
+	// credentials, each with the credentials of the role before it, e.g. an
+	// intermediate role in a hub account before a role in a spoke account.
+	// AssumeRoleARN, if set, is assumed last. AWS limits the sessions of
+	// chained roles to one hour.
+	// +optional
+	AssumeRoleARNChain []string `json:"assumeRoleARNChain,omitempty"`
+
+	// AssumeRoleExternalID is the external ID passed when assuming
+	// AssumeRoleARN. It is required by roles whose trust policy has an
+	// sts:ExternalId condition.
+	// +optional
+	AssumeRoleExternalID *string `json:"assumeRoleExternalID,omitempty"`
+
+	// AssumeRoleSessionName is the session name used when assuming
+	// AssumeRoleARN. It is recorded in CloudTrail, so a deterministic name
+	// makes it easy to audit calls made by the provider.
+	// +optional
+	AssumeRoleSessionName *string `json:"assumeRoleSessionName,omitempty"`
+
+	// AssumeRoleDurationSeconds is the duration of the assumed role session.
+	// It must be between 900 and 43200 seconds and not exceed the maximum
+	// session duration of the role.
+	// +optional
+	// +kubebuilder:validation:Minimum=900
+	// +kubebuilder:validation:Maximum=43200
+	AssumeRoleDurationSeconds *int32 `json:"assumeRoleDurationSeconds,omitempty"`
+
+	// STSRegionalEndpoint sends the STS calls made to assume roles to the
+	// STS endpoint of the region of the first resource that uses the
+	// ProviderConfig in a partition, e.g. sts.us-gov-west-1.amazonaws.com,
+	// rather than to the global endpoint sts.amazonaws.com. Only the aws
+	// partition has a global endpoint, so the regional endpoints of other
+	// partitions are always used.
+	// +optional
+	// +kubebuilder:default=true
+	STSRegionalEndpoint *bool `json:"stsRegionalEndpoint,omitempty"`
+
+	// Partition is the AWS partition of the resources using this
+	// ProviderConfig, e.g. aws-us-gov. Resources whose region isn't in the
+	// partition aren't connected to AWS, so that e.g. a GovCloud region isn't
+	// used with the endpoints of commercial regions by mistake. The partition
+	// of the region of each resource is used if it's not set.
+	// +optional
+	// +kubebuilder:validation:Enum=aws;aws-cn;aws-us-gov;aws-iso;aws-iso-b
+	Partition *string `json:"partition,omitempty"`
+
 	// Endpoint is where you can override the default endpoint configuration
 	// of AWS calls made by the provider.
 	// +optional
 	Endpoint *EndpointConfig `json:"endpoint,omitempty"`
+
+	// DefaultTags are applied to every resource created with this
+	// ProviderConfig. Tags set on a resource take precedence over default
+	// tags with the same key.
+	// +optional
+	DefaultTags map[string]string `json:"defaultTags,omitempty"`
+
+	// RequireEncryption denies resources that would store data unencrypted.
+	// Topics without a KMS master key are neither created nor observed.
+	// +optional
+	RequireEncryption bool `json:"requireEncryption,omitempty"`
+
+	// RequireCustomerManagedKey denies resources that aren't encrypted with
+	// a customer managed KMS key. Topics encrypted with the AWS managed key
+	// of SNS, alias/aws/sns, or not encrypted at all are neither created nor
+	// observed.
+	// +optional
+	RequireCustomerManagedKey bool `json:"requireCustomerManagedKey,omitempty"`
+
+	// NamePattern is a regular expression the names of new resources must
+	// match, e.g. ^team-[a-z]+-.*. Resources whose name doesn't match it
+	// aren't created.
+	// +optional
+	NamePattern *string `json:"namePattern,omitempty"`
+
+	// CostTags are the keys of the cost allocation tags every resource is
+	// recommended to have. Resources report which of them they have and
+	// which are missing. Nothing is reported if none are given.
+	// +optional
+	CostTags []string `json:"costTags,omitempty"`
+
+	// MaxRetries is the number of times a failed AWS request is retried. The
+	// SDK default is used if it's not set.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries *int32 `json:"maxRetries,omitempty"`
+
+	// RequestTimeout bounds every request made to AWS, and every observe,
+	// create, update or delete of a resource. Requests aren't bounded if it's
+	// not set.
+	// +optional
+	RequestTimeout *metav1.Duration `json:"requestTimeout,omitempty"`
+
+	// PrewarmCredentials obtains the credentials of a resource before it's
+	// reconciled, and fails to connect to AWS with a clear error if they
+	// can't be obtained. Otherwise credentials, e.g. of an assumed role, are
+	// obtained by the first AWS request, whose error may not make clear that
+	// they're at fault.
+	// +optional
+	PrewarmCredentials bool `json:"prewarmCredentials,omitempty"`
+
+	// AuditWebhookURL is a URL that the kind, name and ARN of a resource, and
+	// which of its attributes and tags changed, are posted to as JSON whenever
+	// they're changed, e.g. to feed an audit or SIEM system. Their values are
+	// not posted. Changes are still applied if the webhook can't be reached.
+	// +optional
+	AuditWebhookURL *string `json:"auditWebhookURL,omitempty"`
+
+	// HTTPSProxy is the URL of the proxy AWS requests are sent through, e.g.
+	// http://proxy.example.com:3128. Requests are sent directly if it's not
+	// set.
+	// +optional
+	HTTPSProxy *string `json:"httpsProxy,omitempty"`
+
+	// CABundle contains PEM encoded certificates of the certificate
+	// authorities AWS endpoints and the HTTPS proxy are trusted to be signed
+	// by, in addition to the system certificate authorities.
+	// +optional
+	CABundle *CABundleSource `json:"caBundle,omitempty"`
+
+	// IMDS configures how the EC2 instance metadata service is used to look
+	// up credentials and the region. It's used as the AWS SDK defaults to if
+	// not set.
+	// +optional
+	IMDS *IMDSConfig `json:"imds,omitempty"`
+}
+
+// IMDSConfig configures how the EC2 instance metadata service is used.
+type IMDSConfig struct {
+	// Disabled stops the provider from calling the instance metadata
+	// service, which hangs for seconds when it's unreachable, e.g. outside
+	// of EC2 and EKS. Credentials must then come from an explicit source,
+	// such as a Secret or a web identity.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// EndpointMode selects whether the IPv4 or IPv6 endpoint of the instance
+	// metadata service is called.
+	// +optional
+	// +kubebuilder:validation:Enum=IPv4;IPv6
+	EndpointMode *string `json:"endpointMode,omitempty"`
 }
 
+// A CABundleSource is the Secret or ConfigMap key a CA bundle is read from.
+// Exactly one of them should be set.
+type CABundleSource struct {
+	// SecretRef selects the key of a Secret the CA bundle is read from.
+	// +optional
+	SecretRef *xpv1.SecretKeySelector `json:"secretRef,omitempty"`
+
+	// ConfigMapRef selects the key of a ConfigMap the CA bundle is read
+	// from.
+	// +optional
+	ConfigMapRef *ConfigMapKeySelector `json:"configMapRef,omitempty"`
+}
+
+// A ConfigMapKeySelector is a reference to a ConfigMap key in an arbitrary
+// namespace.
+type ConfigMapKeySelector struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+
+	// Namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
+
+	// The key to select.
+	Key string `json:"key"`
+}
+
+// CredentialsSourceWebIdentity indicates that the provider should exchange a
+// web identity token, such as the projected service account token of IRSA,
+// for credentials of an IAM role.
+const CredentialsSourceWebIdentity xpv1.CredentialsSource = "WebIdentity"
+
 // ProviderCredentials required to authenticate.
 type ProviderCredentials struct {
 	// Source of the provider credentials.
-	// +kubebuilder:validation:Enum=None;Secret;InjectedIdentity;Environment;Filesystem
+	// +kubebuilder:validation:Enum=None;Secret;InjectedIdentity;Environment;Filesystem;WebIdentity
 	Source xpv1.CredentialsSource `json:"source"`
 
 	xpv1.CommonCredentialSelectors `json:",inline"`
+
+	// Profile of the credentials file to read the credentials from. The
+	// default profile is used if none is given.
+	// +optional
+	Profile *string `json:"profile,omitempty"`
+
+	// WebIdentity configures the WebIdentity credentials source.
+	// +optional
+	WebIdentity *WebIdentityConfig `json:"webIdentity,omitempty"`
+}
+
+// WebIdentityConfig configures how a web identity token is exchanged for
+// credentials. Fields that aren't set are read from the AWS_WEB_IDENTITY_TOKEN_FILE,
+// AWS_ROLE_ARN and AWS_ROLE_SESSION_NAME environment variables.
+type WebIdentityConfig struct {
+	// TokenFile is the path of the file the web identity token is read from.
+	// +optional
+	TokenFile *string `json:"tokenFile,omitempty"`
+
+	// RoleARN of the IAM role to assume with the web identity token.
+	// +optional
+	RoleARN *string `json:"roleARN,omitempty"`
+
+	// RoleSessionName is the session name used when assuming the role.
+	// +optional
+	RoleSessionName *string `json:"roleSessionName,omitempty"`
 }
 
 // EndpointConfig is used to configure the AWS client for a custom endpoint.
 type EndpointConfig struct {
-	// URL lets you configure the endpoint URL to be used in SDK calls.
-	URL URLConfig `json:"url"`
+	// URL lets you configure the endpoint URL to be used in SDK calls. The
+	// SDK resolves the endpoints if it's not given.
+	// +optional
+	URL URLConfig `json:"url,omitempty"`
+
+	// ServiceOverrides configure the endpoint URL of individual services,
+	// keyed by their lower case service ID, e.g. sns, sts or cloudcontrol.
+	// They take precedence over URL. Services without an override use URL,
+	// or the endpoints the SDK resolves if URL isn't given.
+	// +optional
+	ServiceOverrides map[string]URLConfig `json:"serviceOverrides,omitempty"`
+
+	// UseFIPSEndpoint makes calls use the FIPS endpoints of services, e.g.
+	// sns-fips.us-east-1.amazonaws.com. Dynamic URLs get a -fips suffix
+	// appended to the service name.
+	// +optional
+	UseFIPSEndpoint *bool `json:"useFIPSEndpoint,omitempty"`
 
 	// Specifies if the endpoint's hostname can be modified by the SDK's API
 	// client.
@@ -82,7 +292,8 @@ type EndpointConfig struct {
 	// +optional
 	HostnameImmutable *bool `json:"hostnameImmutable,omitempty"`
 
-	// The AWS partition the endpoint belongs to.
+	// The AWS partition the endpoint belongs to. Defaults to the partition
+	// of the ProviderConfig.
 	// +optional
 	PartitionID *string `json:"partitionId,omitempty"`
 
@@ -93,7 +304,9 @@ type EndpointConfig struct {
 
 	// The region that should be used for signing the request to the endpoint.
 	// For IAM, which doesn't have any region, us-east-1 is used to sign the
-	// requests, which is the only signing region of IAM.
+	// requests, which is the only signing region of IAM. Requests to a static
+	// URL for resources without a region are signed for the region in the
+	// host name of the URL, or us-east-1 if it has none, e.g. for LocalStack.
 	// +optional
 	SigningRegion *string `json:"signingRegion,omitempty"`
 
@@ -151,6 +364,37 @@ type DynamicURLConfig struct {
 // A ProviderConfigStatus represents the status of a ProviderConfig.
 type ProviderConfigStatus struct {
 	xpv1.ProviderConfigStatus `json:",inline"`
+
+	// Endpoints are the endpoints the endpoint configuration resolved to for
+	// each service and region a client has been built for.
+	// +optional
+	Endpoints []ServiceEndpoint `json:"endpoints,omitempty"`
+
+	// CredentialsObtainedAt is the last time credentials described by this
+	// ProviderConfig were obtained.
+	// +optional
+	CredentialsObtainedAt *metav1.Time `json:"credentialsObtainedAt,omitempty"`
+
+	// CredentialsSource is the credentials provider of the AWS SDK that
+	// obtained the credentials, e.g. AssumeRoleProvider.
+	// +optional
+	CredentialsSource string `json:"credentialsSource,omitempty"`
+
+	// AccountID is the ID of the AWS account the credentials act in.
+	// +optional
+	AccountID string `json:"accountId,omitempty"`
+}
+
+// A ServiceEndpoint is the endpoint resolved for calls to an AWS service.
+type ServiceEndpoint struct {
+	// Service is the ID of the AWS service, e.g. SNS.
+	Service string `json:"service"`
+
+	// Region the endpoint was resolved for.
+	Region string `json:"region"`
+
+	// URL of the endpoint.
+	URL string `json:"url"`
 }
 
 
@@ -159,6 +403,9 @@ type ProviderConfigStatus struct {
 // A ProviderConfig configures how AWS controllers will connect to AWS API.
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="SECRET-NAME",type="string",JSONPath=".spec.credentialsSecretRef.name",priority=1
+// +kubebuilder:printcolumn:name="ACCOUNT",type="string",JSONPath=".status.accountId"
+// +kubebuilder:printcolumn:name="CREDENTIALS-OBTAINED",type="date",JSONPath=".status.credentialsObtainedAt"
+// +kubebuilder:printcolumn:name="CREDENTIALS-SOURCE",type="string",JSONPath=".status.credentialsSource",priority=1
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,provider,aws}
 // +kubebuilder:subresource:status
 type ProviderConfig struct {
@@ -22,9 +22,51 @@ limitations under the License.
 package v1beta1
 
 import (
+	commonv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CABundleSource) DeepCopyInto(out *CABundleSource) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(commonv1.SecretKeySelector)
+		**out = **in
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(ConfigMapKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CABundleSource.
+func (in *CABundleSource) DeepCopy() *CABundleSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CABundleSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeySelector) DeepCopyInto(out *ConfigMapKeySelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeySelector.
+func (in *ConfigMapKeySelector) DeepCopy() *ConfigMapKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DynamicURLConfig) DeepCopyInto(out *DynamicURLConfig) {
 	*out = *in
@@ -44,6 +86,18 @@ func (in *DynamicURLConfig) DeepCopy() *DynamicURLConfig {
 func (in *EndpointConfig) DeepCopyInto(out *EndpointConfig) {
 	*out = *in
 	in.URL.DeepCopyInto(&out.URL)
+	if in.ServiceOverrides != nil {
+		in, out := &in.ServiceOverrides, &out.ServiceOverrides
+		*out = make(map[string]URLConfig, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.UseFIPSEndpoint != nil {
+		in, out := &in.UseFIPSEndpoint, &out.UseFIPSEndpoint
+		*out = new(bool)
+		**out = **in
+	}
 	if in.HostnameImmutable != nil {
 		in, out := &in.HostnameImmutable, &out.HostnameImmutable
 		*out = new(bool)
@@ -86,6 +140,26 @@ func (in *EndpointConfig) DeepCopy() *EndpointConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IMDSConfig) DeepCopyInto(out *IMDSConfig) {
+	*out = *in
+	if in.EndpointMode != nil {
+		in, out := &in.EndpointMode, &out.EndpointMode
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IMDSConfig.
+func (in *IMDSConfig) DeepCopy() *IMDSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(IMDSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProviderConfig) DeepCopyInto(out *ProviderConfig) {
 	*out = *in
@@ -154,11 +228,88 @@ func (in *ProviderConfigSpec) DeepCopyInto(out *ProviderConfigSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.AssumeRoleARNChain != nil {
+		in, out := &in.AssumeRoleARNChain, &out.AssumeRoleARNChain
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AssumeRoleExternalID != nil {
+		in, out := &in.AssumeRoleExternalID, &out.AssumeRoleExternalID
+		*out = new(string)
+		**out = **in
+	}
+	if in.AssumeRoleSessionName != nil {
+		in, out := &in.AssumeRoleSessionName, &out.AssumeRoleSessionName
+		*out = new(string)
+		**out = **in
+	}
+	if in.AssumeRoleDurationSeconds != nil {
+		in, out := &in.AssumeRoleDurationSeconds, &out.AssumeRoleDurationSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.STSRegionalEndpoint != nil {
+		in, out := &in.STSRegionalEndpoint, &out.STSRegionalEndpoint
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Partition != nil {
+		in, out := &in.Partition, &out.Partition
+		*out = new(string)
+		**out = **in
+	}
 	if in.Endpoint != nil {
 		in, out := &in.Endpoint, &out.Endpoint
 		*out = new(EndpointConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DefaultTags != nil {
+		in, out := &in.DefaultTags, &out.DefaultTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NamePattern != nil {
+		in, out := &in.NamePattern, &out.NamePattern
+		*out = new(string)
+		**out = **in
+	}
+	if in.CostTags != nil {
+		in, out := &in.CostTags, &out.CostTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RequestTimeout != nil {
+		in, out := &in.RequestTimeout, &out.RequestTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AuditWebhookURL != nil {
+		in, out := &in.AuditWebhookURL, &out.AuditWebhookURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.HTTPSProxy != nil {
+		in, out := &in.HTTPSProxy, &out.HTTPSProxy
+		*out = new(string)
+		**out = **in
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = new(CABundleSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IMDS != nil {
+		in, out := &in.IMDS, &out.IMDS
+		*out = new(IMDSConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigSpec.
@@ -175,6 +326,15 @@ func (in *ProviderConfigSpec) DeepCopy() *ProviderConfigSpec {
 func (in *ProviderConfigStatus) DeepCopyInto(out *ProviderConfigStatus) {
 	*out = *in
 	in.ProviderConfigStatus.DeepCopyInto(&out.ProviderConfigStatus)
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]ServiceEndpoint, len(*in))
+		copy(*out, *in)
+	}
+	if in.CredentialsObtainedAt != nil {
+		in, out := &in.CredentialsObtainedAt, &out.CredentialsObtainedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderConfigStatus.
@@ -249,6 +409,16 @@ func (in *ProviderConfigUsageList) DeepCopyObject() runtime.Object {
 func (in *ProviderCredentials) DeepCopyInto(out *ProviderCredentials) {
 	*out = *in
 	in.CommonCredentialSelectors.DeepCopyInto(&out.CommonCredentialSelectors)
+	if in.Profile != nil {
+		in, out := &in.Profile, &out.Profile
+		*out = new(string)
+		**out = **in
+	}
+	if in.WebIdentity != nil {
+		in, out := &in.WebIdentity, &out.WebIdentity
+		*out = new(WebIdentityConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderCredentials.
@@ -261,6 +431,21 @@ func (in *ProviderCredentials) DeepCopy() *ProviderCredentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceEndpoint) DeepCopyInto(out *ServiceEndpoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceEndpoint.
+func (in *ServiceEndpoint) DeepCopy() *ServiceEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *URLConfig) DeepCopyInto(out *URLConfig) {
 	*out = *in
@@ -285,3 +470,33 @@ func (in *URLConfig) DeepCopy() *URLConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebIdentityConfig) DeepCopyInto(out *WebIdentityConfig) {
+	*out = *in
+	if in.TokenFile != nil {
+		in, out := &in.TokenFile, &out.TokenFile
+		*out = new(string)
+		**out = **in
+	}
+	if in.RoleARN != nil {
+		in, out := &in.RoleARN, &out.RoleARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.RoleSessionName != nil {
+		in, out := &in.RoleSessionName, &out.RoleSessionName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebIdentityConfig.
+func (in *WebIdentityConfig) DeepCopy() *WebIdentityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WebIdentityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -17,8 +17,11 @@ limitations under the License.
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"provider-aws-controlapi/internal/controller"
 
 	"gopkg.in/alecthomas/kingpin.v2"
@@ -29,6 +32,13 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 
 	"provider-aws-controlapi/apis"
+	snsv1alpha1 "provider-aws-controlapi/apis/sns/v1alpha1"
+	awsclient "provider-aws-controlapi/internal/clients"
+	"provider-aws-controlapi/internal/controller/options"
+	"provider-aws-controlapi/internal/export"
+	"provider-aws-controlapi/internal/pprof"
+	"provider-aws-controlapi/internal/validate"
+	"provider-aws-controlapi/internal/version"
 )
 
 func main() {
@@ -37,9 +47,33 @@ func main() {
 		debug          = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
 		syncInterval   = app.Flag("sync", "Sync interval controls how often all resources will be double checked for drift.").Short('s').Default("1h").Duration()
 		pollInterval   = app.Flag("poll", "Poll interval controls how often an individual resource should be checked for drift.").Default("1m").Duration()
+		gracePeriod    = app.Flag("creation-grace-period", "How long a newly created resource may be reported as not existing before it is assumed lost and created again. SNS is eventually consistent, so a topic may not be found for a while after it was created.").Default("1m").Duration()
 		leaderElection = app.Flag("leader-election", "Use leader election for the conroller manager.").Short('l').Default("false").OverrideDefaultFromEnvar("LEADER_ELECTION").Bool()
+		maxObjectSize  = app.Flag("max-object-size", "Size in bytes a managed resource may grow to before optional status fields are dropped. Set to 0 to disable.").Default(strconv.Itoa(awsclient.DefaultMaxObjectSize)).Int()
+		reconcileRate  = app.Flag("max-reconcile-rate", "Number of reconciles per second each resource kind is allowed.").Default(strconv.Itoa(ratelimiter.DefaultProviderRPS)).Int()
+		kindRates      = app.Flag("kind-reconcile-rate", "Number of reconciles per second a resource kind is allowed, overriding --max-reconcile-rate. For example Topic=5.").StringMap()
+		maxReconciles  = app.Flag("max-concurrent-reconciles", "Number of resources each controller reconciles at once.").Default("1").Int()
+		pcReconciles   = app.Flag("max-concurrent-reconciles-per-provider-config", "Number of resources that use the same ProviderConfig, and therefore AWS account, reconciled at once regardless of their kind. Set to 0 to disable.").Default("0").Int()
+		subProtocols   = app.Flag("observe-subscription-protocols", "List the subscriptions of each Topic to report how many there are for each protocol. Adds an API call per page of subscriptions to every observation.").Default("false").Bool()
+		verifyAttrs    = app.Flag("verify-topic-attributes", "Read the attributes of a Topic back after updating them to verify SNS applied them. Adds an API call to every update.").Default("false").Bool()
+		verifyTags     = app.Flag("verify-topic-tags", "List the tags of a Topic again after updating them to verify SNS applied them. Adds an API call to every update that changes tags.").Default("false").Bool()
+		inventory      = app.Flag("inventory-interval", "How often the managed resources are counted by kind, region and sync state in the controlapi_managed_resources metric. Set to 0 to disable.").Default("1m").Duration()
+		enablePprof    = app.Flag("enable-pprof", "Serve the pprof profiling endpoints on the metrics address.").Default("false").Bool()
+		enableWebhooks = app.Flag("enable-webhooks", "Serve the conversion and validating webhooks. Requires serving certificates to be mounted.").Default("false").Bool()
+
+		_             = app.Command("start", "Start the provider controllers.").Default()
+		validateCmd   = app.Command("validate", "Validate managed resource manifests offline, without calling AWS.")
+		validateFiles = validateCmd.Arg("files", "Manifest files to validate.").Required().ExistingFiles()
+		exportCmd     = app.Command("export", "Print a CloudFormation template with the resolved configuration of the Topics in managed resource manifests.")
+		exportFiles   = exportCmd.Arg("files", "Manifest files to export.").Required().ExistingFiles()
 	)
-	kingpin.MustParse(app.Parse(os.Args[1:]))
+	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	case validateCmd.FullCommand():
+		os.Exit(runValidate(*validateFiles))
+	case exportCmd.FullCommand():
+		runExport(*exportFiles)
+		return
+	}
 
 	zl := zap.New(zap.UseDevMode(*debug))
 	log := logging.NewLogrLogger(zl.WithName("provider-template"))
@@ -62,8 +96,75 @@ func main() {
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 
-	rl := ratelimiter.NewDefaultProviderRateLimiter(ratelimiter.DefaultProviderRPS)
+	rl := controller.RateLimiters{RPS: *reconcileRate, KindRPS: map[string]int{}}
+	for kind, rate := range *kindRates {
+		rps, err := strconv.Atoi(rate)
+		kingpin.FatalIfError(err, "Cannot parse reconcile rate of %s", kind)
+		rl.KindRPS[kind] = rps
+	}
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Template APIs to scheme")
-	kingpin.FatalIfError(controller.Setup(mgr, log, rl, *pollInterval), "Cannot setup Template controllers")
+	o := options.Options{
+		Logger:                  log,
+		PollInterval:            *pollInterval,
+		CreationGracePeriod:     *gracePeriod,
+		MaxConcurrentReconciles: *maxReconciles,
+		Topic: options.TopicOptions{
+			MaxObjectSize:                *maxObjectSize,
+			ObserveSubscriptionProtocols: *subProtocols,
+			VerifyAttributes:             *verifyAttrs,
+			VerifyTags:                   *verifyTags,
+		},
+	}
+	kingpin.FatalIfError(controller.Setup(mgr, o, rl, *pcReconciles), "Cannot setup Template controllers")
+	kingpin.FatalIfError(controller.SetupInventory(mgr, log, *inventory), "Cannot setup managed resource inventory")
+	kingpin.FatalIfError(pprof.Setup(mgr, *enablePprof), "Cannot setup pprof endpoints")
+	report := version.NewReport(controller.Kinds(), map[string]bool{
+		"requestMetrics":               true,
+		"inventoryMetrics":             *inventory > 0,
+		"pprof":                        *enablePprof,
+		"webhooks":                     *enableWebhooks,
+		"observeSubscriptionProtocols": *subProtocols,
+		"verifyTopicAttributes":        *verifyAttrs,
+		"verifyTopicTags":              *verifyTags,
+	})
+	log.Info("Starting provider", "version", report.Version, "kinds", report.Kinds, "features", report.Features)
+	kingpin.FatalIfError(version.Setup(mgr, report), "Cannot setup version endpoint")
+	if *enableWebhooks {
+		kingpin.FatalIfError((&snsv1alpha1.Topic{}).SetupWebhookWithManager(mgr), "Cannot setup Topic webhooks")
+	}
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
+
+// runValidate validates the supplied manifest files, prints every problem it
+// finds and returns the exit code of the validate command.
+func runValidate(files []string) int {
+	code := 0
+	for _, file := range files {
+		f, err := os.Open(filepath.Clean(file))
+		kingpin.FatalIfError(err, "Cannot open %s", file)
+		findings, err := validate.Manifests(f)
+		_ = f.Close()
+		kingpin.FatalIfError(err, "Cannot validate %s", file)
+		for _, finding := range findings {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", file, finding)
+			code = 1
+		}
+	}
+	return code
+}
+
+// runExport prints a CloudFormation template with the Topics of the supplied
+// manifest files.
+func runExport(files []string) {
+	t := export.NewTemplate()
+	for _, file := range files {
+		f, err := os.Open(filepath.Clean(file))
+		kingpin.FatalIfError(err, "Cannot open %s", file)
+		err = t.AddManifests(f)
+		_ = f.Close()
+		kingpin.FatalIfError(err, "Cannot export %s", file)
+	}
+	out, err := json.MarshalIndent(t, "", "  ")
+	kingpin.FatalIfError(err, "Cannot marshal template")
+	fmt.Println(string(out))
+}
@@ -0,0 +1,100 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/pkg/errors"
+
+	"provider-aws-controlapi/apis/v1beta1"
+)
+
+// auditWebhookTimeout bounds each request to an audit webhook, so that a slow
+// webhook doesn't hold up reconciles.
+const auditWebhookTimeout = 10 * time.Second
+
+// An AuditEvent records the changes applied to an external resource. It names
+// the attributes and tags that changed, but not their values, which may be
+// sensitive.
+type AuditEvent struct {
+	// Kind of the managed resource, e.g. Topic.
+	Kind string `json:"kind"`
+
+	// Name of the managed resource.
+	Name string `json:"name"`
+
+	// ARN of the external resource.
+	ARN string `json:"arn"`
+
+	// Attributes that were set.
+	Attributes []string `json:"attributes,omitempty"`
+
+	// TagsAdded are the keys of the tags that were added or changed.
+	TagsAdded []string `json:"tagsAdded,omitempty"`
+
+	// TagsRemoved are the keys of the tags that were removed.
+	TagsRemoved []string `json:"tagsRemoved,omitempty"`
+}
+
+// An Auditor records the changes applied to external resources, e.g. for an
+// audit or SIEM system. Failing to record a change doesn't fail the change.
+type Auditor interface {
+	Audit(ctx context.Context, e AuditEvent)
+}
+
+// An AuditorFn is a function that satisfies the Auditor interface.
+type AuditorFn func(ctx context.Context, e AuditEvent)
+
+// Audit records the supplied event.
+func (fn AuditorFn) Audit(ctx context.Context, e AuditEvent) {
+	fn(ctx, e)
+}
+
+// NewAuditor returns an Auditor that posts events to the audit webhook of the
+// supplied ProviderConfig, or one that drops them if it has none.
+func NewAuditor(pc *v1beta1.ProviderConfig, l logging.Logger) Auditor {
+	if pc.Spec.AuditWebhookURL == nil || *pc.Spec.AuditWebhookURL == "" {
+		return AuditorFn(func(_ context.Context, _ AuditEvent) {})
+	}
+	return &webhookAuditor{url: *pc.Spec.AuditWebhookURL, client: &http.Client{Timeout: auditWebhookTimeout}, log: l}
+}
+
+// A webhookAuditor posts events as JSON to a webhook.
+type webhookAuditor struct {
+	url    string
+	client *http.Client
+	log    logging.Logger
+}
+
+// Audit posts the supplied event to the webhook. Errors are logged rather
+// than returned.
+func (a *webhookAuditor) Audit(ctx context.Context, e AuditEvent) {
+	if err := a.post(ctx, e); err != nil {
+		a.log.Info("Cannot send audit event", "kind", e.Kind, "name", e.Name, "error", err.Error())
+	}
+}
+
+func (a *webhookAuditor) post(ctx context.Context, e AuditEvent) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "cannot marshal audit event")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(b))
+	if err != nil {
+		return errors.Wrap(err, "cannot build audit webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "cannot post audit event")
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("audit webhook responded with status %s", resp.Status)
+	}
+	return nil
+}
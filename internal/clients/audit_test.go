@@ -0,0 +1,64 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+
+	"provider-aws-controlapi/apis/v1beta1"
+)
+
+func TestWebhookAuditor(t *testing.T) {
+	e := AuditEvent{
+		Kind:        "Topic",
+		Name:        "orders",
+		ARN:         "arn:aws:sns:us-west-2:123456789012:orders",
+		Attributes:  []string{"DisplayName", "Policy"},
+		TagsAdded:   []string{"team"},
+		TagsRemoved: []string{"owner"},
+	}
+
+	cases := map[string]struct {
+		reason string
+		status int
+	}{
+		"Accepted": {
+			reason: "The event should be posted to the webhook as JSON.",
+			status: http.StatusNoContent,
+		},
+		"Rejected": {
+			reason: "A webhook that rejects the event should not fail the change.",
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []AuditEvent
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("\n%s\nAudit(...): want a JSON POST, got %s with content type %q\n", tc.reason, r.Method, r.Header.Get("Content-Type"))
+				}
+				posted := AuditEvent{}
+				if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+					t.Errorf("\n%s\nAudit(...): cannot decode event: %v\n", tc.reason, err)
+				}
+				got = append(got, posted)
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			pc := &v1beta1.ProviderConfig{Spec: v1beta1.ProviderConfigSpec{AuditWebhookURL: aws.String(srv.URL)}}
+			NewAuditor(pc, logging.NewNopLogger()).Audit(context.Background(), e)
+			if diff := cmp.Diff([]AuditEvent{e}, got); diff != "" {
+				t.Errorf("\n%s\nAudit(...): -want events, +got events:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
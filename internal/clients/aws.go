@@ -10,15 +10,20 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/aws/smithy-go"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
 	"gopkg.in/ini.v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
+	"net/url"
+	"os"
 	"provider-aws-controlapi/apis/v1beta1"
+	"regexp"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // DefaultSection for INI files.
@@ -28,46 +33,155 @@ const DefaultSection = ini.DefaultSection
 // of region.
 const GlobalRegion = "aws-global"
 
+// DefaultSigningRegion is the region requests to a static endpoint URL are
+// signed for if neither the resource nor the URL has a region. Local
+// emulators of AWS, such as LocalStack, default to it.
+const DefaultSigningRegion = "us-east-1"
+
+// regionRegexp matches the name of an AWS region, e.g. eu-west-1 or
+// us-gov-west-1.
+var regionRegexp = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-[0-9]+$`)
+
+// Bounds of the assumed role session duration accepted by STS, in seconds.
+const (
+	minAssumeRoleDuration = 900
+	maxAssumeRoleDuration = 43200
+)
+
+// Environment variables the AWS SDK reads the web identity configuration from.
+const (
+	envWebIdentityTokenFile = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	envRoleARN              = "AWS_ROLE_ARN"
+	envRoleSessionName      = "AWS_ROLE_SESSION_NAME"
+)
+
 // Endpoint URL configuration types.
 const (
 	URLConfigTypeStatic  = "Static"
 	URLConfigTypeDynamic = "Dynamic"
 )
 
+const (
+	errNoProviderConfigRef  = "neither providerConfigRef nor providerRef is given for %q"
+	errSetProviderConfigRef = "cannot set providerConfigRef to providerRef"
+)
+
 
 // GetConfig constructs an *aws.Config that can be used to authenticate to AWS
-// API by the AWS clients.
-func GetConfig(ctx context.Context, c client.Client, mg resource.Managed, region string) (*aws.Config, error) {
+// API by the AWS clients. The ProviderConfig the config was built from is
+// returned too, so callers don't need to get it again.
+func GetConfig(ctx context.Context, c client.Client, mg resource.Managed, region string, l logging.Logger) (*aws.Config, *v1beta1.ProviderConfig, error) {
 	switch {
-	case mg.GetProviderConfigReference() != nil:
-		return UseProviderConfig(ctx, c, mg, region)
+	case mg.GetProviderConfigReference() != nil, mg.GetProviderReference() != nil:
+		return UseProviderConfig(ctx, c, mg, region, l)
 	default:
-		return nil, errors.New("neither providerConfigRef nor providerRef is given")
+		return nil, nil, errors.Errorf(errNoProviderConfigRef, mg.GetName())
 	}
 }
 
 // UseProviderConfig to produce a config that can be used to authenticate to AWS.
-func UseProviderConfig(ctx context.Context, c client.Client, mg resource.Managed, region string) (*aws.Config, error) { // nolint:gocyclo
+// The credentials the config obtained are recorded in the status of the
+// ProviderConfig, which is returned along with the config. Recording them is
+// only bookkeeping, so errors doing so, e.g. because STS can't be reached or
+// the status can't be written, are logged rather than returned.
+func UseProviderConfig(ctx context.Context, c client.Client, mg resource.Managed, region string, l logging.Logger) (*aws.Config, *v1beta1.ProviderConfig, error) { // nolint:gocyclo
+	pc, err := GetProviderConfig(ctx, c, mg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := CheckPartition(pc, region); err != nil {
+		return nil, nil, err
+	}
+	cfg, err := defaultConfigCache.Get(ctx, c, pc, region)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := defaultConfigCache.RecordCredentials(ctx, c, pc, cfg); err != nil {
+		l.Info("Cannot record credentials in ProviderConfig status", "name", pc.GetName(), "error", err.Error())
+	}
+	return cfg, pc, nil
+}
+
+// GetProviderConfig returns the ProviderConfig referenced by the supplied
+// managed resource. The deprecated providerRef is used if the resource has no
+// providerConfigRef.
+func GetProviderConfig(ctx context.Context, c client.Client, mg resource.Managed) (*v1beta1.ProviderConfig, error) {
+	ref := mg.GetProviderConfigReference()
+	if ref == nil {
+		ref = mg.GetProviderReference()
+	}
+	if ref == nil {
+		return nil, errors.Errorf(errNoProviderConfigRef, mg.GetName())
+	}
 	pc := &v1beta1.ProviderConfig{}
-	if err := c.Get(ctx, types.NamespacedName{Name: mg.GetProviderConfigReference().Name}, pc); err != nil {
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, pc); err != nil {
 		return nil, errors.Wrap(err, "cannot get referenced Provider")
 	}
+	return pc, nil
+}
+
+// A ProviderRefInitializer sets the providerConfigRef of managed resources
+// that only reference their ProviderConfig with the deprecated providerRef.
+// ProviderConfig usage can only be tracked through a providerConfigRef.
+type ProviderRefInitializer struct {
+	kube client.Client
+}
+
+// NewProviderRefInitializer returns a new ProviderRefInitializer.
+func NewProviderRefInitializer(c client.Client) *ProviderRefInitializer {
+	return &ProviderRefInitializer{kube: c}
+}
+
+// Initialize sets the providerConfigRef of the supplied managed resource to
+// its providerRef, if it has one but no providerConfigRef.
+func (i *ProviderRefInitializer) Initialize(ctx context.Context, mg resource.Managed) error {
+	if mg.GetProviderConfigReference() != nil || mg.GetProviderReference() == nil {
+		return nil
+	}
+	mg.SetProviderConfigReference(&xpv1.Reference{Name: mg.GetProviderReference().Name})
+	return errors.Wrap(i.kube.Update(ctx, mg), errSetProviderConfigRef)
+}
 
-	t := resource.NewProviderConfigUsageTracker(c, &v1beta1.ProviderConfigUsage{})
-	if err := t.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, "cannot track ProviderConfig usage")
+// newConfig builds the *aws.Config described by the supplied ProviderConfig.
+func newConfig(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig, region string) (*aws.Config, error) {
+	hc, err := newHTTPClient(ctx, c, pc)
+	if err != nil {
+		return nil, err
 	}
+	optFns := imdsOptions(pc)
+	if hc != nil {
+		optFns = append(optFns, config.WithHTTPClient(hc))
+	}
+	cfg, err := newCredentialsConfig(ctx, c, pc, region, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	cfg.APIOptions = append(cfg.APIOptions, WithRequestMetrics, WithErrorMetrics)
+	return SetRequestOptions(pc, cfg), nil
+}
 
+// newCredentialsConfig builds an *aws.Config using the credentials and
+// endpoint described by the supplied ProviderConfig. The supplied options are
+// used to load every config, including those of the STS clients that assume
+// roles.
+func newCredentialsConfig(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig, region string, optFns ...func(*config.LoadOptions) error) (*aws.Config, error) {
 	switch s := pc.Spec.Credentials.Source; s { //nolint:exhaustive
+	case v1beta1.CredentialsSourceWebIdentity:
+		cfg, err := UseWebIdentity(ctx, region, pc, os.Getenv, optFns...)
+		if err != nil {
+			return nil, err
+		}
+		return SetResolver(pc, cfg), nil
 	case xpv1.CredentialsSourceInjectedIdentity:
-		if pc.Spec.AssumeRoleARN != nil {
-			cfg, err := UsePodServiceAccountAssumeRole(ctx, []byte{}, DefaultSection, region, pc)
+		if len(assumeRoleARNs(pc)) > 0 {
+			cfg, err := UsePodServiceAccountAssumeRole(ctx, []byte{}, DefaultSection, region, pc, optFns...)
 			if err != nil {
 				return nil, err
 			}
 			return SetResolver(pc, cfg), nil
 		}
-		cfg, err := UsePodServiceAccount(ctx, []byte{}, DefaultSection, region)
+		cfg, err := UsePodServiceAccount(ctx, []byte{}, DefaultSection, region, optFns...)
 		if err != nil {
 			return nil, err
 		}
@@ -77,14 +191,14 @@ func UseProviderConfig(ctx context.Context, c client.Client, mg resource.Managed
 		if err != nil {
 			return nil, errors.Wrap(err, "cannot get credentials")
 		}
-		if pc.Spec.AssumeRoleARN != nil {
-			cfg, err := UseProviderSecretAssumeRole(ctx, data, DefaultSection, region, pc)
+		if len(assumeRoleARNs(pc)) > 0 {
+			cfg, err := UseProviderSecretAssumeRole(ctx, data, credentialsProfile(pc), region, pc, optFns...)
 			if err != nil {
 				return nil, err
 			}
 			return SetResolver(pc, cfg), nil
 		}
-		cfg, err := UseProviderSecret(ctx, data, DefaultSection, region)
+		cfg, err := UseProviderSecret(ctx, data, credentialsProfile(pc), region, optFns...)
 		if err != nil {
 			return nil, err
 		}
@@ -95,21 +209,21 @@ func UseProviderConfig(ctx context.Context, c client.Client, mg resource.Managed
 // UsePodServiceAccountAssumeRole assumes an IAM role configured via a ServiceAccount
 // assume Cross account IAM roles
 // https://aws.amazon.com/blogs/containers/cross-account-iam-roles-for-kubernetes-service-accounts/
-func UsePodServiceAccountAssumeRole(ctx context.Context, _ []byte, _, region string, pc *v1beta1.ProviderConfig) (*aws.Config, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+func UsePodServiceAccountAssumeRole(ctx context.Context, _ []byte, _, region string, pc *v1beta1.ProviderConfig, optFns ...func(*config.LoadOptions) error) (*aws.Config, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load default AWS config")
 	}
-	stsclient := sts.NewFromConfig(cfg)
+	opts, err := assumeRoleOptions(pc)
+	if err != nil {
+		return nil, err
+	}
 	cnf, err := config.LoadDefaultConfig(
 		ctx,
-		config.WithRegion(region),
-		config.WithCredentialsProvider(aws.NewCredentialsCache(
-			stscreds.NewAssumeRoleProvider(
-				stsclient,
-				StringValue(pc.Spec.AssumeRoleARN),
-			)),
-		),
+		append([]func(*config.LoadOptions) error{
+			config.WithRegion(region),
+			config.WithCredentialsProvider(assumeRoleChain(cfg, assumeRoleARNs(pc), opts, stsClientFor(region, pc))),
+		}, optFns...)...,
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load assumed role AWS config")
@@ -119,10 +233,10 @@ func UsePodServiceAccountAssumeRole(ctx context.Context, _ []byte, _, region str
 
 // UsePodServiceAccount assumes an IAM role configured via a ServiceAccount.
 // https://docs.aws.amazon.com/eks/latest/userguide/iam-roles-for-service-accounts.html
-func UsePodServiceAccount(ctx context.Context, _ []byte, _, region string) (*aws.Config, error) {
+func UsePodServiceAccount(ctx context.Context, _ []byte, _, region string, optFns ...func(*config.LoadOptions) error) (*aws.Config, error) {
 	cfg, err := config.LoadDefaultConfig(
 		ctx,
-		config.WithRegion(region),
+		append([]func(*config.LoadOptions) error{config.WithRegion(region)}, optFns...)...,
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to load default AWS config")
@@ -131,38 +245,212 @@ func UsePodServiceAccount(ctx context.Context, _ []byte, _, region string) (*aws
 }
 
 
+// UseWebIdentity exchanges a web identity token for credentials of an IAM
+// role, optionally assuming the AssumeRoleARN of the ProviderConfig on top.
+// The token file, role and session name are read from the ProviderConfig or,
+// if it doesn't set them, from the environment variables of the AWS SDK. The
+// injected identity is used if neither configures a token file and role.
+func UseWebIdentity(ctx context.Context, region string, pc *v1beta1.ProviderConfig, getenv func(string) string, optFns ...func(*config.LoadOptions) error) (*aws.Config, error) {
+	wi, ok := webIdentity(pc, getenv)
+	if !ok {
+		if len(assumeRoleARNs(pc)) > 0 {
+			return UsePodServiceAccountAssumeRole(ctx, []byte{}, DefaultSection, region, pc, optFns...)
+		}
+		return UsePodServiceAccount(ctx, []byte{}, DefaultSection, region, optFns...)
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, append([]func(*config.LoadOptions) error{config.WithRegion(region)}, optFns...)...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load default AWS config")
+	}
+	cfg.Credentials = aws.NewCredentialsCache(stscreds.NewWebIdentityRoleProvider(
+		newSTSClient(cfg, region, pc),
+		StringValue(wi.RoleARN),
+		stscreds.IdentityTokenFile(StringValue(wi.TokenFile)),
+		func(o *stscreds.WebIdentityRoleOptions) { o.RoleSessionName = StringValue(wi.RoleSessionName) },
+	))
+	if len(assumeRoleARNs(pc)) == 0 {
+		return &cfg, nil
+	}
+
+	opts, err := assumeRoleOptions(pc)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Credentials = assumeRoleChain(cfg, assumeRoleARNs(pc), opts, stsClientFor(region, pc))
+	return &cfg, nil
+}
+
+// webIdentity returns the web identity configuration of the supplied
+// ProviderConfig, completed from the environment. It reports false if no
+// token file and role are configured.
+func webIdentity(pc *v1beta1.ProviderConfig, getenv func(string) string) (v1beta1.WebIdentityConfig, bool) {
+	wi := v1beta1.WebIdentityConfig{}
+	if pc.Spec.Credentials.WebIdentity != nil {
+		wi = *pc.Spec.Credentials.WebIdentity.DeepCopy()
+	}
+	fromEnv := func(v *string, key string) *string {
+		if v == nil && getenv(key) != "" {
+			return aws.String(getenv(key))
+		}
+		return v
+	}
+	wi.TokenFile = fromEnv(wi.TokenFile, envWebIdentityTokenFile)
+	wi.RoleARN = fromEnv(wi.RoleARN, envRoleARN)
+	wi.RoleSessionName = fromEnv(wi.RoleSessionName, envRoleSessionName)
+	return wi, wi.TokenFile != nil && wi.RoleARN != nil
+}
+
 // UseProviderSecretAssumeRole - AWS configuration which can be used to issue requests against AWS API
 // assume Cross account IAM roles
-func UseProviderSecretAssumeRole(ctx context.Context, data []byte, profile, region string, pc *v1beta1.ProviderConfig) (*aws.Config, error) {
+func UseProviderSecretAssumeRole(ctx context.Context, data []byte, profile, region string, pc *v1beta1.ProviderConfig, optFns ...func(*config.LoadOptions) error) (*aws.Config, error) {
 	creds, err := CredentialsIDSecret(data, profile)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot parse credentials secret")
 	}
+	opts, err := assumeRoleOptions(pc)
+	if err != nil {
+		return nil, err
+	}
 
-	config, err := config.LoadDefaultConfig(ctx, config.WithRegion(region), config.WithCredentialsProvider(credentials.StaticCredentialsProvider{
+	config, err := config.LoadDefaultConfig(ctx, append([]func(*config.LoadOptions) error{config.WithRegion(region), config.WithCredentialsProvider(credentials.StaticCredentialsProvider{
 		Value: creds,
-	}))
+	})}, optFns...)...)
 
-	stsSvc := sts.NewFromConfig(config)
-	stsAssume := stscreds.NewAssumeRoleProvider(stsSvc, StringValue(pc.Spec.AssumeRoleARN))
-	config.Credentials = aws.NewCredentialsCache(stsAssume)
+	config.Credentials = assumeRoleChain(config, assumeRoleARNs(pc), opts, stsClientFor(region, pc))
 
 	return &config, err
 }
 
 // UseProviderSecret - AWS configuration which can be used to issue requests against AWS API
-func UseProviderSecret(ctx context.Context, data []byte, profile, region string) (*aws.Config, error) {
+func UseProviderSecret(ctx context.Context, data []byte, profile, region string, optFns ...func(*config.LoadOptions) error) (*aws.Config, error) {
 	creds, err := CredentialsIDSecret(data, profile)
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot parse credentials secret")
 	}
 
-	config, err := config.LoadDefaultConfig(ctx, config.WithRegion(region), config.WithCredentialsProvider(credentials.StaticCredentialsProvider{
+	config, err := config.LoadDefaultConfig(ctx, append([]func(*config.LoadOptions) error{config.WithRegion(region), config.WithCredentialsProvider(credentials.StaticCredentialsProvider{
 		Value: creds,
-	}))
+	})}, optFns...)...)
 	return &config, err
 }
 
+// newSTSClient returns the STS client used to assume roles on behalf of the
+// supplied ProviderConfig in the supplied region. The client calls the
+// regional STS endpoint unless the ProviderConfig opts into the global one.
+func newSTSClient(cfg aws.Config, region string, pc *v1beta1.ProviderConfig) *sts.Client {
+	r := stsRegion(region, pc)
+	return sts.NewFromConfig(cfg, func(o *sts.Options) {
+		if r != "" {
+			o.Region = r
+		}
+	})
+}
+
+// stsClientFor returns a function that returns the STS client used to assume
+// roles on behalf of the supplied ProviderConfig with a config.
+func stsClientFor(region string, pc *v1beta1.ProviderConfig) func(aws.Config) stscreds.AssumeRoleAPIClient {
+	return func(cfg aws.Config) stscreds.AssumeRoleAPIClient {
+		return newSTSClient(cfg, region, pc)
+	}
+}
+
+// assumeRoleARNs returns the roles the supplied ProviderConfig assumes in
+// order; those of its chain followed by its AssumeRoleARN.
+func assumeRoleARNs(pc *v1beta1.ProviderConfig) []string {
+	roles := append([]string{}, pc.Spec.AssumeRoleARNChain...)
+	if pc.Spec.AssumeRoleARN != nil {
+		roles = append(roles, StringValue(pc.Spec.AssumeRoleARN))
+	}
+	return roles
+}
+
+// assumeRoleChain returns the credentials of the last of the supplied roles.
+// Each role is assumed with the credentials of the role before it, and the
+// first with the credentials of the supplied config, by the STS client
+// newClient returns for the config with those credentials.
+func assumeRoleChain(cfg aws.Config, roles []string, opts func(*stscreds.AssumeRoleOptions), newClient func(aws.Config) stscreds.AssumeRoleAPIClient) aws.CredentialsProvider {
+	for _, role := range roles {
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(newClient(cfg), role, opts))
+	}
+	return cfg.Credentials
+}
+
+// stsRegion returns the region STS clients of the supplied ProviderConfig
+// resolve their endpoint for. The aws-global pseudo region resolves the
+// global endpoint, which only the aws partition has.
+func stsRegion(region string, pc *v1beta1.ProviderConfig) string {
+	if region == "" || pc.Spec.STSRegionalEndpoint == nil || *pc.Spec.STSRegionalEndpoint || partition(pc, region) != "aws" {
+		return region
+	}
+	return GlobalRegion
+}
+
+// Partition returns the AWS partition the supplied region is in.
+func Partition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	case strings.HasPrefix(region, "us-isob-"):
+		return "aws-iso-b"
+	case strings.HasPrefix(region, "us-iso-"):
+		return "aws-iso"
+	}
+	return "aws"
+}
+
+// partition returns the AWS partition of the supplied ProviderConfig, or that
+// of the supplied region if the ProviderConfig doesn't specify one.
+func partition(pc *v1beta1.ProviderConfig, region string) string {
+	if pc.Spec.Partition != nil {
+		return *pc.Spec.Partition
+	}
+	return Partition(region)
+}
+
+// CheckPartition returns an error if the supplied region isn't in the
+// partition of the supplied ProviderConfig, e.g. cn-north-1 under the aws
+// partition.
+func CheckPartition(pc *v1beta1.ProviderConfig, region string) error {
+	if pc.Spec.Partition == nil || region == "" {
+		return nil
+	}
+	if p := Partition(region); p != *pc.Spec.Partition {
+		return errors.Errorf("region %q is in partition %q rather than partition %q of ProviderConfig %q", region, p, *pc.Spec.Partition, pc.GetName())
+	}
+	return nil
+}
+
+// credentialsProfile returns the profile of the credentials file the supplied
+// ProviderConfig reads its credentials from.
+func credentialsProfile(pc *v1beta1.ProviderConfig) string {
+	if pc.Spec.Credentials.Profile != nil {
+		return *pc.Spec.Credentials.Profile
+	}
+	return DefaultSection
+}
+
+// assumeRoleOptions configures the AssumeRole calls made on behalf of the
+// supplied ProviderConfig.
+func assumeRoleOptions(pc *v1beta1.ProviderConfig) (func(*stscreds.AssumeRoleOptions), error) {
+	if d := pc.Spec.AssumeRoleDurationSeconds; d != nil && (*d < minAssumeRoleDuration || *d > maxAssumeRoleDuration) {
+		return nil, errors.Errorf("assumeRoleDurationSeconds must be between %d and %d seconds, got %d", minAssumeRoleDuration, maxAssumeRoleDuration, *d)
+	}
+	return func(o *stscreds.AssumeRoleOptions) {
+		if pc.Spec.AssumeRoleExternalID != nil {
+			o.ExternalID = pc.Spec.AssumeRoleExternalID
+		}
+		if pc.Spec.AssumeRoleSessionName != nil {
+			o.RoleSessionName = StringValue(pc.Spec.AssumeRoleSessionName)
+		}
+		if pc.Spec.AssumeRoleDurationSeconds != nil {
+			o.Duration = time.Duration(*pc.Spec.AssumeRoleDurationSeconds) * time.Second
+		}
+	}, nil
+}
+
 // CredentialsIDSecret retrieves AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY from the data which contains
 // aws credentials under given profile
 // Example:
@@ -210,40 +498,49 @@ func SetResolver(pc *v1beta1.ProviderConfig, cfg *aws.Config) *aws.Config { // n
 	if pc.Spec.Endpoint == nil {
 		return cfg
 	}
+	if pc.Spec.Endpoint.UseFIPSEndpoint != nil {
+		// Clients read the FIPS endpoint state from the first config source
+		// that has one, so this takes precedence over the environment.
+		state := aws.FIPSEndpointStateDisabled
+		if *pc.Spec.Endpoint.UseFIPSEndpoint {
+			state = aws.FIPSEndpointStateEnabled
+		}
+		cfg.ConfigSources = append([]interface{}{fipsEndpointSource(state)}, cfg.ConfigSources...)
+	}
+	if pc.Spec.Endpoint.URL.Type == "" && len(pc.Spec.Endpoint.ServiceOverrides) == 0 {
+		return cfg
+	}
 	cfg.EndpointResolverWithOptions = awsEndpointResolverAdaptorWithOptions(func(service, region string, options interface{}) (aws.Endpoint, error) {
-		fullURL := ""
-		switch pc.Spec.Endpoint.URL.Type {
-		case URLConfigTypeStatic:
-			if pc.Spec.Endpoint.URL.Static == nil {
-				return aws.Endpoint{}, errors.New("static type is chosen but static field does not have a value")
-			}
-			fullURL = StringValue(pc.Spec.Endpoint.URL.Static)
-		case URLConfigTypeDynamic:
-			if pc.Spec.Endpoint.URL.Dynamic == nil {
-				return aws.Endpoint{}, errors.New("dynamic type is chosen but dynamic configuration is not given")
-			}
-			// NOTE(muvaf): IAM does not have any region.
-			if service == "IAM" {
-				fullURL = fmt.Sprintf("%s://%s.%s", pc.Spec.Endpoint.URL.Dynamic.Protocol, strings.ToLower(service), pc.Spec.Endpoint.URL.Dynamic.Host)
-			} else {
-				fullURL = fmt.Sprintf("%s://%s.%s.%s", pc.Spec.Endpoint.URL.Dynamic.Protocol, strings.ToLower(service), region, pc.Spec.Endpoint.URL.Dynamic.Host)
-			}
-		default:
-			return aws.Endpoint{}, errors.New("unsupported url config type is chosen")
+		u, ok := pc.Spec.Endpoint.ServiceOverrides[strings.ToLower(service)]
+		if !ok {
+			u = pc.Spec.Endpoint.URL
+		}
+		if u.Type == "" {
+			// Services without a URL use the endpoints the SDK resolves.
+			return aws.Endpoint{}, &aws.EndpointNotFoundError{}
+		}
+		fullURL, err := endpointURL(u, BoolValue(pc.Spec.Endpoint.UseFIPSEndpoint), service, region)
+		if err != nil {
+			return aws.Endpoint{}, err
 		}
 		e := aws.Endpoint{
 			URL:               fullURL,
 			HostnameImmutable: BoolValue(pc.Spec.Endpoint.HostnameImmutable),
-			PartitionID:       StringValue(pc.Spec.Endpoint.PartitionID),
+			PartitionID:       StringValue(LateInitializeStringPtr(pc.Spec.Endpoint.PartitionID, pc.Spec.Partition)),
 			SigningName:       StringValue(pc.Spec.Endpoint.SigningName),
 			SigningRegion:     StringValue(LateInitializeStringPtr(pc.Spec.Endpoint.SigningRegion, &region)),
 			SigningMethod:     StringValue(pc.Spec.Endpoint.SigningMethod),
 		}
+		if region == "" && pc.Spec.Endpoint.SigningRegion == nil && u.Type == URLConfigTypeStatic {
+			// Requests have to be signed for a region, even if the static
+			// URL, e.g. of LocalStack, doesn't need one.
+			e.SigningRegion = staticSigningRegion(fullURL)
+		}
 		// Only IAM does not have a region parameter and "aws-global" is used in
 		// SDK setup. However, signing region has to be us-east-1 and it needs
 		// to be set.
 		if region == "aws-global" {
-			switch StringValue(pc.Spec.Endpoint.PartitionID) {
+			switch e.PartitionID {
 			case "aws-us-gov", "aws-cn":
 				e.SigningRegion = StringValue(LateInitializeStringPtr(pc.Spec.Endpoint.SigningRegion, &region))
 			default:
@@ -263,7 +560,58 @@ func SetResolver(pc *v1beta1.ProviderConfig, cfg *aws.Config) *aws.Config { // n
 	return cfg
 }
 
+// staticSigningRegion returns the region requests to the supplied static
+// endpoint URL are signed for if the resource has no region: the region in
+// the host name of the URL, e.g. eu-west-1 for sns.eu-west-1.amazonaws.com, or
+// the default signing region if it has none.
+func staticSigningRegion(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return DefaultSigningRegion
+	}
+	for _, l := range strings.Split(u.Hostname(), ".") {
+		if regionRegexp.MatchString(l) {
+			return l
+		}
+	}
+	return DefaultSigningRegion
+}
+
+// endpointURL returns the URL the supplied URL configuration resolves to for
+// the supplied service and region.
+func endpointURL(u v1beta1.URLConfig, fips bool, service, region string) (string, error) {
+	switch u.Type {
+	case URLConfigTypeStatic:
+		if u.Static == nil {
+			return "", errors.New("static type is chosen but static field does not have a value")
+		}
+		return StringValue(u.Static), nil
+	case URLConfigTypeDynamic:
+		if u.Dynamic == nil {
+			return "", errors.New("dynamic type is chosen but dynamic configuration is not given")
+		}
+		name := strings.ToLower(service)
+		if fips {
+			name += "-fips"
+		}
+		// NOTE(muvaf): IAM does not have any region.
+		if service == "IAM" {
+			return fmt.Sprintf("%s://%s.%s", u.Dynamic.Protocol, name, u.Dynamic.Host), nil
+		}
+		return fmt.Sprintf("%s://%s.%s.%s", u.Dynamic.Protocol, name, region, u.Dynamic.Host), nil
+	default:
+		return "", errors.New("unsupported url config type is chosen")
+	}
+}
+
+// A fipsEndpointSource is a config source that sets whether clients use the
+// FIPS endpoints of services.
+type fipsEndpointSource aws.FIPSEndpointState
 
+// GetUseFIPSEndpoint returns the FIPS endpoint state of the source.
+func (s fipsEndpointSource) GetUseFIPSEndpoint(_ context.Context) (aws.FIPSEndpointState, bool, error) {
+	return aws.FIPSEndpointState(s), true, nil
+}
 
 // StringValue converts the supplied string pointer to a string, returning the
 // empty string if the pointer is nil.
@@ -314,7 +662,8 @@ func LateInitializeInt64Ptr(in *int64, from *int64) *int64 {
 }
 
 // Wrap will remove the request-specific information from the error and only then
-// wrap it.
+// wrap it. The ID AWS assigned to the failed request is kept, since AWS support
+// needs it to investigate the failure.
 func Wrap(err error, msg string) error {
 	// NOTE(muvaf): nil check is done for performance, otherwise errors.As makes
 	// a few reflection calls before returning false, letting awsErr be nil.
@@ -323,11 +672,21 @@ func Wrap(err error, msg string) error {
 	}
 	var awsErr smithy.APIError
 	if errors.As(err, &awsErr) {
+		var re requestIDError
+		if errors.As(err, &re) && re.ServiceRequestID() != "" {
+			return errors.Wrapf(awsErr, "%s (request ID: %s)", msg, re.ServiceRequestID())
+		}
 		return errors.Wrap(awsErr, msg)
 	}
 	return errors.Wrap(err, msg)
 }
 
+// A requestIDError is an error that carries the ID AWS assigned to the
+// request that failed, such as the response errors of the SDK.
+type requestIDError interface {
+	ServiceRequestID() string
+}
+
 // StrToBool convert string to boolean value
 func StrToBoolPtr(s string) *bool{
 	b,e := strconv.ParseBool(s)
@@ -0,0 +1,810 @@
+package aws
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	snsv1alpha1 "provider-aws-controlapi/apis/sns/v1alpha1"
+	"provider-aws-controlapi/apis/v1beta1"
+)
+
+func TestAssumeRoleOptions(t *testing.T) {
+	type want struct {
+		o   stscreds.AssumeRoleOptions
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		spec   v1beta1.ProviderConfigSpec
+		want   want
+	}{
+		"NoOptions": {
+			reason: "The SDK defaults should be used when no options are set.",
+			spec:   v1beta1.ProviderConfigSpec{},
+			want:   want{o: stscreds.AssumeRoleOptions{}},
+		},
+		"AllOptions": {
+			reason: "The external ID, session name and duration should be passed to the provider.",
+			spec: v1beta1.ProviderConfigSpec{
+				AssumeRoleExternalID:      aws.String("external-id"),
+				AssumeRoleSessionName:     aws.String("crossplane"),
+				AssumeRoleDurationSeconds: aws.Int32(3600),
+			},
+			want: want{o: stscreds.AssumeRoleOptions{
+				ExternalID:      aws.String("external-id"),
+				RoleSessionName: "crossplane",
+				Duration:        time.Hour,
+			}},
+		},
+		"DurationTooShort": {
+			reason: "A duration shorter than STS accepts should return an error.",
+			spec: v1beta1.ProviderConfigSpec{
+				AssumeRoleDurationSeconds: aws.Int32(60),
+			},
+			want: want{err: errors.New("assumeRoleDurationSeconds must be between 900 and 43200 seconds, got 60")},
+		},
+		"DurationTooLong": {
+			reason: "A duration longer than STS accepts should return an error.",
+			spec: v1beta1.ProviderConfigSpec{
+				AssumeRoleDurationSeconds: aws.Int32(43201),
+			},
+			want: want{err: errors.New("assumeRoleDurationSeconds must be between 900 and 43200 seconds, got 43201")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fn, err := assumeRoleOptions(&v1beta1.ProviderConfig{Spec: tc.spec})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nassumeRoleOptions(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			got := stscreds.AssumeRoleOptions{}
+			fn(&got)
+			if diff := cmp.Diff(tc.want.o, got, cmpopts.IgnoreUnexported(stscreds.AssumeRoleOptions{})); diff != "" {
+				t.Errorf("\n%s\nassumeRoleOptions(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCredentialsIDSecret(t *testing.T) {
+	const data = `[default]
+aws_access_key_id = default-id
+aws_secret_access_key = default-secret
+
+[production]
+aws_access_key_id = production-id
+aws_secret_access_key = production-secret
+`
+
+	type want struct {
+		creds aws.Credentials
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		spec   v1beta1.ProviderConfigSpec
+		want   want
+	}{
+		"DefaultProfile": {
+			reason: "The default profile should be used when no profile is set.",
+			want:   want{creds: aws.Credentials{AccessKeyID: "default-id", SecretAccessKey: "default-secret"}},
+		},
+		"NamedProfile": {
+			reason: "The profile set in the ProviderConfig should be used.",
+			spec: v1beta1.ProviderConfigSpec{
+				Credentials: v1beta1.ProviderCredentials{Profile: aws.String("production")},
+			},
+			want: want{creds: aws.Credentials{AccessKeyID: "production-id", SecretAccessKey: "production-secret"}},
+		},
+		"MissingProfile": {
+			reason: "A profile that isn't in the credentials file should return an error.",
+			spec: v1beta1.ProviderConfigSpec{
+				Credentials: v1beta1.ProviderCredentials{Profile: aws.String("staging")},
+			},
+			want: want{err: errors.Wrap(errors.New(`section "staging" does not exist`), "cannot get staging profile in credentials secret")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			creds, err := CredentialsIDSecret([]byte(data), credentialsProfile(&v1beta1.ProviderConfig{Spec: tc.spec}))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCredentialsIDSecret(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.creds, creds); diff != "" {
+				t.Errorf("\n%s\nCredentialsIDSecret(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWebIdentity(t *testing.T) {
+	type want struct {
+		wi v1beta1.WebIdentityConfig
+		ok bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		creds  v1beta1.ProviderCredentials
+		env    map[string]string
+		want   want
+	}{
+		"ProviderConfig": {
+			reason: "The token file, role and session name of the ProviderConfig should be used.",
+			creds: v1beta1.ProviderCredentials{WebIdentity: &v1beta1.WebIdentityConfig{
+				TokenFile:       aws.String("/var/run/secrets/token"),
+				RoleARN:         aws.String("arn:aws:iam::123456789012:role/provider"),
+				RoleSessionName: aws.String("crossplane"),
+			}},
+			env: map[string]string{
+				envWebIdentityTokenFile: "/env/token",
+				envRoleARN:              "arn:aws:iam::123456789012:role/env",
+			},
+			want: want{
+				wi: v1beta1.WebIdentityConfig{
+					TokenFile:       aws.String("/var/run/secrets/token"),
+					RoleARN:         aws.String("arn:aws:iam::123456789012:role/provider"),
+					RoleSessionName: aws.String("crossplane"),
+				},
+				ok: true,
+			},
+		},
+		"Environment": {
+			reason: "Fields the ProviderConfig doesn't set should be read from the environment.",
+			creds: v1beta1.ProviderCredentials{WebIdentity: &v1beta1.WebIdentityConfig{
+				TokenFile: aws.String("/var/run/secrets/token"),
+			}},
+			env: map[string]string{
+				envWebIdentityTokenFile: "/env/token",
+				envRoleARN:              "arn:aws:iam::123456789012:role/env",
+			},
+			want: want{
+				wi: v1beta1.WebIdentityConfig{
+					TokenFile: aws.String("/var/run/secrets/token"),
+					RoleARN:   aws.String("arn:aws:iam::123456789012:role/env"),
+				},
+				ok: true,
+			},
+		},
+		"NotConfigured": {
+			reason: "The injected identity should be used if no token file and role are configured.",
+			env: map[string]string{
+				envWebIdentityTokenFile: "/env/token",
+			},
+			want: want{
+				wi: v1beta1.WebIdentityConfig{TokenFile: aws.String("/env/token")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc := &v1beta1.ProviderConfig{Spec: v1beta1.ProviderConfigSpec{Credentials: tc.creds}}
+			wi, ok := webIdentity(pc, func(key string) string { return tc.env[key] })
+			if diff := cmp.Diff(tc.want.wi, wi); diff != "" {
+				t.Errorf("\n%s\nwebIdentity(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\nwebIdentity(...): -want ok, +got ok:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetProviderConfig(t *testing.T) {
+	type want struct {
+		name string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		spec   xpv1.ResourceSpec
+		want   want
+	}{
+		"ProviderConfigRef": {
+			reason: "The ProviderConfig named by the providerConfigRef should be used.",
+			spec: xpv1.ResourceSpec{
+				ProviderConfigReference: &xpv1.Reference{Name: "config"},
+				ProviderReference:       &xpv1.Reference{Name: "provider"},
+			},
+			want: want{name: "config"},
+		},
+		"ProviderRef": {
+			reason: "The ProviderConfig named by the providerRef should be used if there is no providerConfigRef.",
+			spec:   xpv1.ResourceSpec{ProviderReference: &xpv1.Reference{Name: "provider"}},
+			want:   want{name: "provider"},
+		},
+		"NoRef": {
+			reason: "A resource without either reference should return an error naming it.",
+			want:   want{err: errors.Errorf(errNoProviderConfigRef, "example")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ""
+			kube := &test.MockClient{
+				MockGet: func(_ context.Context, key client.ObjectKey, _ client.Object) error {
+					got = key.Name
+					return nil
+				},
+			}
+			cr := &snsv1alpha1.Topic{Spec: snsv1alpha1.TopicSpec{ResourceSpec: tc.spec}}
+			cr.SetName("example")
+
+			_, err := GetProviderConfig(context.Background(), kube, cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGetProviderConfig(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.name, got); diff != "" {
+				t.Errorf("\n%s\nGetProviderConfig(...): -want name, +got name:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProviderRefInitializer(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		ref     *xpv1.Reference
+		updated bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason    string
+		spec      xpv1.ResourceSpec
+		updateErr error
+		want      want
+	}{
+		"ProviderConfigRef": {
+			reason: "A resource with a providerConfigRef should be left alone.",
+			spec: xpv1.ResourceSpec{
+				ProviderConfigReference: &xpv1.Reference{Name: "config"},
+				ProviderReference:       &xpv1.Reference{Name: "provider"},
+			},
+			want: want{ref: &xpv1.Reference{Name: "config"}},
+		},
+		"ProviderRef": {
+			reason: "The providerConfigRef of a resource with only a providerRef should be set and persisted.",
+			spec:   xpv1.ResourceSpec{ProviderReference: &xpv1.Reference{Name: "provider"}},
+			want:   want{ref: &xpv1.Reference{Name: "provider"}, updated: true},
+		},
+		"UpdateFailed": {
+			reason:    "An error persisting the providerConfigRef should be returned.",
+			spec:      xpv1.ResourceSpec{ProviderReference: &xpv1.Reference{Name: "provider"}},
+			updateErr: errBoom,
+			want:      want{ref: &xpv1.Reference{Name: "provider"}, updated: true, err: errors.Wrap(errBoom, errSetProviderConfigRef)},
+		},
+		"NoRef": {
+			reason: "A resource without either reference should be left alone.",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			updated := false
+			kube := &test.MockClient{
+				MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+					updated = true
+					return tc.updateErr
+				},
+			}
+			cr := &snsv1alpha1.Topic{Spec: snsv1alpha1.TopicSpec{ResourceSpec: tc.spec}}
+
+			err := NewProviderRefInitializer(kube).Initialize(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nInitialize(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.ref, cr.GetProviderConfigReference()); diff != "" {
+				t.Errorf("\n%s\nInitialize(...): -want providerConfigRef, +got providerConfigRef:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.updated, updated); diff != "" {
+				t.Errorf("\n%s\nInitialize(...): -want updated, +got updated:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetResolverFIPS(t *testing.T) {
+	type want struct {
+		fips aws.FIPSEndpointState
+		urls map[string]string
+	}
+
+	dynamic := v1beta1.URLConfig{
+		Type:    URLConfigTypeDynamic,
+		Dynamic: &v1beta1.DynamicURLConfig{Protocol: "https", Host: "amazonaws.com"},
+	}
+
+	cases := map[string]struct {
+		reason   string
+		endpoint *v1beta1.EndpointConfig
+		want     want
+	}{
+		"NoEndpoint": {
+			reason: "The FIPS endpoint state shouldn't be set if there's no endpoint configuration.",
+		},
+		"SDKResolution": {
+			reason:   "Enabling FIPS without a URL should enable the FIPS endpoints the SDK resolves.",
+			endpoint: &v1beta1.EndpointConfig{UseFIPSEndpoint: aws.Bool(true)},
+			want:     want{fips: aws.FIPSEndpointStateEnabled},
+		},
+		"Disabled": {
+			reason:   "Disabling FIPS should disable the FIPS endpoints the SDK resolves.",
+			endpoint: &v1beta1.EndpointConfig{UseFIPSEndpoint: aws.Bool(false)},
+			want:     want{fips: aws.FIPSEndpointStateDisabled},
+		},
+		"DynamicURL": {
+			reason:   "Dynamic URLs should get the -fips suffix, including those of IAM, which has no region.",
+			endpoint: &v1beta1.EndpointConfig{UseFIPSEndpoint: aws.Bool(true), URL: dynamic},
+			want: want{
+				fips: aws.FIPSEndpointStateEnabled,
+				urls: map[string]string{
+					"SNS": "https://sns-fips.us-east-1.amazonaws.com",
+					"IAM": "https://iam-fips.amazonaws.com",
+				},
+			},
+		},
+		"DynamicURLWithoutFIPS": {
+			reason:   "Dynamic URLs shouldn't change if FIPS isn't enabled.",
+			endpoint: &v1beta1.EndpointConfig{URL: dynamic},
+			want: want{
+				urls: map[string]string{
+					"SNS": "https://sns.us-east-1.amazonaws.com",
+					"IAM": "https://iam.amazonaws.com",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := SetResolver(&v1beta1.ProviderConfig{Spec: v1beta1.ProviderConfigSpec{Endpoint: tc.endpoint}}, &aws.Config{})
+
+			got := aws.FIPSEndpointStateUnset
+			for _, s := range cfg.ConfigSources {
+				if p, ok := s.(interface {
+					GetUseFIPSEndpoint(context.Context) (aws.FIPSEndpointState, bool, error)
+				}); ok {
+					got, _, _ = p.GetUseFIPSEndpoint(context.Background())
+					break
+				}
+			}
+			if diff := cmp.Diff(tc.want.fips, got); diff != "" {
+				t.Errorf("\n%s\nSetResolver(...): -want FIPS endpoint state, +got FIPS endpoint state:\n%s\n", tc.reason, diff)
+			}
+
+			if tc.want.urls == nil {
+				if cfg.EndpointResolverWithOptions != nil {
+					t.Errorf("\n%s\nSetResolver(...): want the SDK to resolve endpoints, got a custom resolver\n", tc.reason)
+				}
+				return
+			}
+			for service, want := range tc.want.urls {
+				region := "us-east-1"
+				if service == "IAM" {
+					region = GlobalRegion
+				}
+				e, err := cfg.EndpointResolverWithOptions.ResolveEndpoint(service, region)
+				if err != nil {
+					t.Fatalf("\n%s\nResolveEndpoint(%s): %v\n", tc.reason, service, err)
+				}
+				if diff := cmp.Diff(want, e.URL); diff != "" {
+					t.Errorf("\n%s\nResolveEndpoint(%s): -want URL, +got URL:\n%s\n", tc.reason, service, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestSetResolverServiceOverrides(t *testing.T) {
+	localstack := v1beta1.URLConfig{Type: URLConfigTypeStatic, Static: aws.String("http://localstack:4566")}
+	vpc := v1beta1.URLConfig{
+		Type:    URLConfigTypeDynamic,
+		Dynamic: &v1beta1.DynamicURLConfig{Protocol: "https", Host: "vpce.amazonaws.com"},
+	}
+	dynamic := v1beta1.URLConfig{
+		Type:    URLConfigTypeDynamic,
+		Dynamic: &v1beta1.DynamicURLConfig{Protocol: "https", Host: "example.org"},
+	}
+
+	cases := map[string]struct {
+		reason   string
+		endpoint *v1beta1.EndpointConfig
+		want     map[string]string
+	}{
+		"OverridesOnly": {
+			reason: "Services with an override should use it, others the endpoints the SDK resolves.",
+			endpoint: &v1beta1.EndpointConfig{ServiceOverrides: map[string]v1beta1.URLConfig{
+				"sns": localstack,
+				"sts": vpc,
+			}},
+			want: map[string]string{
+				"SNS":          "http://localstack:4566",
+				"STS":          "https://sts.us-east-1.vpce.amazonaws.com",
+				"CloudControl": "",
+			},
+		},
+		"OverridesAndURL": {
+			reason: "Services without an override should fall back to the URL of the endpoint configuration.",
+			endpoint: &v1beta1.EndpointConfig{
+				URL:              dynamic,
+				ServiceOverrides: map[string]v1beta1.URLConfig{"sns": localstack},
+			},
+			want: map[string]string{
+				"SNS":          "http://localstack:4566",
+				"CloudControl": "https://cloudcontrol.us-east-1.example.org",
+				"IAM":          "https://iam.example.org",
+			},
+		},
+		"IAMOverride": {
+			reason: "A dynamic override of IAM should have no region in its URL.",
+			endpoint: &v1beta1.EndpointConfig{ServiceOverrides: map[string]v1beta1.URLConfig{
+				"iam": vpc,
+			}},
+			want: map[string]string{
+				"IAM": "https://iam.vpce.amazonaws.com",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := SetResolver(&v1beta1.ProviderConfig{Spec: v1beta1.ProviderConfigSpec{Endpoint: tc.endpoint}}, &aws.Config{})
+			got := map[string]string{}
+			for service := range tc.want {
+				region := "us-east-1"
+				if service == "IAM" {
+					region = GlobalRegion
+				}
+				e, err := cfg.EndpointResolverWithOptions.ResolveEndpoint(service, region)
+				var nf *aws.EndpointNotFoundError
+				if err != nil && !errors.As(err, &nf) {
+					t.Fatalf("\n%s\nResolveEndpoint(%s): %v\n", tc.reason, service, err)
+				}
+				got[service] = e.URL
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nResolveEndpoint(...): -want URLs, +got URLs:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetResolverSigningRegion(t *testing.T) {
+	localstack := v1beta1.URLConfig{Type: URLConfigTypeStatic, Static: aws.String("http://localstack:4566")}
+
+	cases := map[string]struct {
+		reason   string
+		endpoint *v1beta1.EndpointConfig
+		region   string
+		want     string
+	}{
+		"Region": {
+			reason:   "Requests should be signed for the region of the resource.",
+			endpoint: &v1beta1.EndpointConfig{URL: localstack},
+			region:   "eu-west-1",
+			want:     "eu-west-1",
+		},
+		"SigningRegion": {
+			reason:   "Requests should be signed for the signing region of the endpoint configuration.",
+			endpoint: &v1beta1.EndpointConfig{URL: localstack, SigningRegion: aws.String("eu-central-1")},
+			want:     "eu-central-1",
+		},
+		"RegionInURL": {
+			reason:   "Requests for resources without a region should be signed for the region in the host name of a static URL.",
+			endpoint: &v1beta1.EndpointConfig{URL: v1beta1.URLConfig{Type: URLConfigTypeStatic, Static: aws.String("https://sns.ap-southeast-2.amazonaws.com")}},
+			want:     "ap-southeast-2",
+		},
+		"NoRegion": {
+			reason:   "Requests for resources without a region should be signed for the default signing region if a static URL has none.",
+			endpoint: &v1beta1.EndpointConfig{URL: localstack},
+			want:     DefaultSigningRegion,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := SetResolver(&v1beta1.ProviderConfig{Spec: v1beta1.ProviderConfigSpec{Endpoint: tc.endpoint}}, &aws.Config{})
+			e, err := cfg.EndpointResolverWithOptions.ResolveEndpoint("SNS", tc.region)
+			if err != nil {
+				t.Fatalf("\n%s\nResolveEndpoint(...): %v\n", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, e.SigningRegion); diff != "" {
+				t.Errorf("\n%s\nResolveEndpoint(...): -want signing region, +got signing region:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCheckPartition(t *testing.T) {
+	cases := map[string]struct {
+		reason    string
+		partition *string
+		region    string
+		want      error
+	}{
+		"NoPartition": {
+			reason: "Any region should be accepted if the ProviderConfig has no partition.",
+			region: "cn-north-1",
+		},
+		"NoRegion": {
+			reason:    "Resources without a region should be accepted.",
+			partition: aws.String("aws-us-gov"),
+		},
+		"Commercial": {
+			reason:    "Commercial regions should be accepted under the aws partition.",
+			partition: aws.String("aws"),
+			region:    "eu-west-1",
+		},
+		"China": {
+			reason:    "China regions should be accepted under the aws-cn partition.",
+			partition: aws.String("aws-cn"),
+			region:    "cn-northwest-1",
+		},
+		"GovCloud": {
+			reason:    "GovCloud regions should be accepted under the aws-us-gov partition.",
+			partition: aws.String("aws-us-gov"),
+			region:    "us-gov-west-1",
+		},
+		"ISO": {
+			reason:    "ISO regions should be accepted under the aws-iso partition.",
+			partition: aws.String("aws-iso"),
+			region:    "us-iso-east-1",
+		},
+		"ISOB": {
+			reason:    "ISOB regions should be accepted under the aws-iso-b partition.",
+			partition: aws.String("aws-iso-b"),
+			region:    "us-isob-east-1",
+		},
+		"ChinaUnderCommercial": {
+			reason:    "China regions should be rejected under the aws partition.",
+			partition: aws.String("aws"),
+			region:    "cn-north-1",
+			want:      errors.New(`region "cn-north-1" is in partition "aws-cn" rather than partition "aws" of ProviderConfig "default"`),
+		},
+		"GovCloudUnderCommercial": {
+			reason:    "GovCloud regions should be rejected under the aws partition.",
+			partition: aws.String("aws"),
+			region:    "us-gov-east-1",
+			want:      errors.New(`region "us-gov-east-1" is in partition "aws-us-gov" rather than partition "aws" of ProviderConfig "default"`),
+		},
+		"CommercialUnderGovCloud": {
+			reason:    "Commercial regions should be rejected under the aws-us-gov partition.",
+			partition: aws.String("aws-us-gov"),
+			region:    "us-east-1",
+			want:      errors.New(`region "us-east-1" is in partition "aws" rather than partition "aws-us-gov" of ProviderConfig "default"`),
+		},
+		"ISOUnderISOB": {
+			reason:    "ISO regions should be rejected under the aws-iso-b partition.",
+			partition: aws.String("aws-iso-b"),
+			region:    "us-iso-east-1",
+			want:      errors.New(`region "us-iso-east-1" is in partition "aws-iso" rather than partition "aws-iso-b" of ProviderConfig "default"`),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc := &v1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: "default"}, Spec: v1beta1.ProviderConfigSpec{Partition: tc.partition}}
+			err := CheckPartition(pc, tc.region)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCheckPartition(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// hostRecorder records the host of every request and fails it.
+type hostRecorder struct {
+	hosts []string
+}
+
+func (r *hostRecorder) Do(req *http.Request) (*http.Response, error) {
+	r.hosts = append(r.hosts, req.URL.Host)
+	return nil, errors.New("boom")
+}
+
+func TestSTSEndpoint(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		region string
+		spec   v1beta1.ProviderConfigSpec
+		want   string
+	}{
+		"RegionalByDefault": {
+			reason: "Roles should be assumed with the regional STS endpoint by default.",
+			region: "eu-west-1",
+			want:   "sts.eu-west-1.amazonaws.com",
+		},
+		"Global": {
+			reason: "Roles should be assumed with the global STS endpoint if the ProviderConfig opts into it.",
+			region: "eu-west-1",
+			spec:   v1beta1.ProviderConfigSpec{STSRegionalEndpoint: aws.Bool(false)},
+			want:   "sts.amazonaws.com",
+		},
+		"GovCloud": {
+			reason: "Roles in GovCloud should be assumed with the regional STS endpoint.",
+			region: "us-gov-west-1",
+			spec:   v1beta1.ProviderConfigSpec{STSRegionalEndpoint: aws.Bool(true)},
+			want:   "sts.us-gov-west-1.amazonaws.com",
+		},
+		"GovCloudGlobal": {
+			reason: "GovCloud has no global STS endpoint, so the regional one should be used regardless.",
+			region: "us-gov-west-1",
+			spec:   v1beta1.ProviderConfigSpec{STSRegionalEndpoint: aws.Bool(false)},
+			want:   "sts.us-gov-west-1.amazonaws.com",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &hostRecorder{}
+			cfg := aws.Config{
+				Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+				HTTPClient:  r,
+				Retryer:     func() aws.Retryer { return aws.NopRetryer{} },
+			}
+			pc := &v1beta1.ProviderConfig{Spec: tc.spec}
+			p := stscreds.NewAssumeRoleProvider(newSTSClient(cfg, tc.region, pc), "arn:aws:iam::123456789012:role/example")
+			if _, err := p.Retrieve(context.Background()); err == nil {
+				t.Fatalf("\n%s\nRetrieve(...): expected an error", tc.reason)
+			}
+			if diff := cmp.Diff([]string{tc.want}, r.hosts); diff != "" {
+				t.Errorf("\n%s\nAssumeRole(...): -want host, +got host:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// assumeRoleRecorder is an STS client that records the credentials each role
+// is assumed with, and returns credentials named after the assumed role.
+type assumeRoleRecorder struct {
+	creds aws.CredentialsProvider
+	calls *[]string
+}
+
+func (r *assumeRoleRecorder) AssumeRole(ctx context.Context, in *sts.AssumeRoleInput, _ ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	c, err := r.creds.Retrieve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	*r.calls = append(*r.calls, c.AccessKeyID+" -> "+aws.ToString(in.RoleArn))
+	return &sts.AssumeRoleOutput{Credentials: &ststypes.Credentials{
+		AccessKeyId:     aws.String(aws.ToString(in.RoleArn)),
+		SecretAccessKey: aws.String("SECRET"),
+		SessionToken:    aws.String("TOKEN"),
+		Expiration:      aws.Time(time.Now().Add(time.Hour)),
+	}}, nil
+}
+
+func TestAssumeRoleChain(t *testing.T) {
+	const (
+		hub   = "arn:aws:iam::111111111111:role/hub"
+		spoke = "arn:aws:iam::222222222222:role/spoke"
+	)
+
+	cases := map[string]struct {
+		reason string
+		spec   v1beta1.ProviderConfigSpec
+		want   []string
+	}{
+		"AssumeRoleARN": {
+			reason: "A single AssumeRoleARN should be assumed like a chain of one role.",
+			spec:   v1beta1.ProviderConfigSpec{AssumeRoleARN: aws.String(spoke)},
+			want:   []string{"AKID -> " + spoke},
+		},
+		"Chain": {
+			reason: "Each role of the chain should be assumed with the credentials of the role before it.",
+			spec:   v1beta1.ProviderConfigSpec{AssumeRoleARNChain: []string{hub, spoke}},
+			want:   []string{"AKID -> " + hub, hub + " -> " + spoke},
+		},
+		"ChainAndAssumeRoleARN": {
+			reason: "The AssumeRoleARN should be assumed after the roles of the chain.",
+			spec:   v1beta1.ProviderConfigSpec{AssumeRoleARNChain: []string{hub}, AssumeRoleARN: aws.String(spoke)},
+			want:   []string{"AKID -> " + hub, hub + " -> " + spoke},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := []string{}
+			newClient := func(cfg aws.Config) stscreds.AssumeRoleAPIClient {
+				return &assumeRoleRecorder{creds: cfg.Credentials, calls: &calls}
+			}
+			cfg := aws.Config{Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", "")}
+			pc := &v1beta1.ProviderConfig{Spec: tc.spec}
+
+			creds, err := assumeRoleChain(cfg, assumeRoleARNs(pc), func(*stscreds.AssumeRoleOptions) {}, newClient).Retrieve(context.Background())
+			if err != nil {
+				t.Fatalf("\n%s\nRetrieve(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, calls); diff != "" {
+				t.Errorf("\n%s\nAssumeRole(...): -want calls, +got calls:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(spoke, creds.AccessKeyID); diff != "" {
+				t.Errorf("\n%s\nRetrieve(...): the credentials of the last role should be returned: -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	apiErr := &smithy.GenericAPIError{Code: "NotFound", Message: "Topic does not exist"}
+	responseErr := func(requestID string) error {
+		return &smithy.OperationError{
+			ServiceID:     "SNS",
+			OperationName: "GetTopicAttributes",
+			Err: &awshttp.ResponseError{
+				ResponseError: &smithyhttp.ResponseError{
+					Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusNotFound}},
+					Err:      apiErr,
+				},
+				RequestID: requestID,
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   string
+	}{
+		"NotAWSError": {
+			reason: "Errors that didn't come from AWS should be wrapped as they are.",
+			err:    errors.New("boom"),
+			want:   "cannot get Topic attributes: boom",
+		},
+		"APIError": {
+			reason: "Only the API error of an AWS error should be wrapped.",
+			err:    apiErr,
+			want:   "cannot get Topic attributes: api error NotFound: Topic does not exist",
+		},
+		"RequestID": {
+			reason: "The request ID of a response error should be kept, and the HTTP details dropped.",
+			err:    responseErr("f2b3a8b1-8f6c-5c6c-9a0e-7d7b3c2e1a44"),
+			want:   "cannot get Topic attributes (request ID: f2b3a8b1-8f6c-5c6c-9a0e-7d7b3c2e1a44): api error NotFound: Topic does not exist",
+		},
+		"NoRequestID": {
+			reason: "A response error without a request ID should be wrapped like any other API error.",
+			err:    responseErr(""),
+			want:   "cannot get Topic attributes: api error NotFound: Topic does not exist",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Wrap(tc.err, "cannot get Topic attributes")
+			if diff := cmp.Diff(tc.want, got.Error()); diff != "" {
+				t.Errorf("\n%s\nWrap(...): -want message, +got message:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package cloudcontrol
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+	"github.com/pkg/errors"
+)
+
+// DefaultRetriesPerMinute is the number of Cloud Control request retries
+// allowed per minute across all clients returned by GetClient.
+const DefaultRetriesPerMinute = 60
+
+var errRetryBudgetExhausted = errors.New("retry budget of Cloud Control requests is exhausted")
+
+// defaultRetryBudget is shared by all clients returned by GetClient, so that a
+// reconcile storm can't exhaust the Cloud Control request quota of the
+// account with retries.
+var defaultRetryBudget = NewRetryBudget(DefaultRetriesPerMinute)
+
+// A RetryBudget is a token bucket that caps the number of retries per minute.
+// It starts full and refills continuously.
+type RetryBudget struct {
+	mu     sync.Mutex
+	max    float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewRetryBudget returns a RetryBudget that allows the supplied number of
+// retries per minute.
+func NewRetryBudget(perMinute int) *RetryBudget {
+	return &RetryBudget{max: float64(perMinute), tokens: float64(perMinute), last: time.Now(), now: time.Now}
+}
+
+// Allow spends a token of the budget and reports whether there was one to
+// spend.
+func (b *RetryBudget) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if now := b.now(); now.After(b.last) {
+		b.tokens += now.Sub(b.last).Minutes() * b.max
+		if b.tokens > b.max {
+			b.tokens = b.max
+		}
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// WithRetryBudget makes the Cloud Control client spend a token of the
+// supplied budget for every retry. A request that would be retried once the
+// budget is spent fails instead, with an error for which
+// IsRetryBudgetExhausted returns true.
+func WithRetryBudget(b *RetryBudget) func(*cloudcontrol.Options) {
+	return func(o *cloudcontrol.Options) {
+		o.Retryer = budgetRetryer{Retryer: o.Retryer, budget: b}
+	}
+}
+
+// IsRetryBudgetExhausted returns true if the supplied error was returned
+// because the retry budget was spent. Callers should shed load by requeueing
+// rather than retrying.
+func IsRetryBudgetExhausted(err error) bool {
+	return errors.Is(err, errRetryBudgetExhausted)
+}
+
+type budgetRetryer struct {
+	aws.Retryer
+	budget *RetryBudget
+}
+
+func (r budgetRetryer) GetRetryToken(ctx context.Context, opErr error) (func(error) error, error) {
+	if !r.budget.Allow() {
+		return nil, errRetryBudgetExhausted
+	}
+	return r.Retryer.GetRetryToken(ctx, opErr)
+}
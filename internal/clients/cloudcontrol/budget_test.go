@@ -0,0 +1,90 @@
+package cloudcontrol
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRetryBudgetAllow(t *testing.T) {
+	b := NewRetryBudget(2)
+	now := b.last
+	b.now = func() time.Time { return now }
+
+	got := []bool{b.Allow(), b.Allow(), b.Allow()}
+	if diff := cmp.Diff([]bool{true, true, false}, got); diff != "" {
+		t.Errorf("\nRetries should be shed once the budget is spent.\nAllow(): -want, +got:\n%s\n", diff)
+	}
+
+	now = now.Add(30 * time.Second)
+	got = []bool{b.Allow(), b.Allow()}
+	if diff := cmp.Diff([]bool{true, false}, got); diff != "" {
+		t.Errorf("\nThe budget should refill over time.\nAllow(): -want, +got:\n%s\n", diff)
+	}
+
+	now = now.Add(time.Hour)
+	got = []bool{b.Allow(), b.Allow(), b.Allow()}
+	if diff := cmp.Diff([]bool{true, true, false}, got); diff != "" {
+		t.Errorf("\nThe budget should not refill past its size.\nAllow(): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestWithRetryBudget(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"__type": "ThrottlingException", "message": "slow down"}`))
+	}))
+	defer srv.Close()
+
+	b := NewRetryBudget(2)
+	c := cloudcontrol.New(cloudcontrol.Options{
+		Region:           "us-east-1",
+		Credentials:      aws.AnonymousCredentials{},
+		EndpointResolver: cloudcontrol.EndpointResolverFromURL(srv.URL),
+		Retryer: retry.NewStandard(func(o *retry.StandardOptions) {
+			o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) { return 0, nil })
+			o.RateLimiter = noRateLimit{}
+		}),
+	}, WithRetryBudget(b))
+	in := &cloudcontrol.GetResourceInput{TypeName: aws.String("AWS::Logs::LogGroup"), Identifier: aws.String("example")}
+
+	// The first request is retried until it runs out of attempts, spending
+	// the budget.
+	_, err := c.GetResource(context.Background(), in)
+	if IsRetryBudgetExhausted(err) {
+		t.Errorf("\nRetries within the budget should be attempted.\nGetResource(...): got %v", err)
+	}
+	if diff := cmp.Diff(3, requests); diff != "" {
+		t.Errorf("\nRetries within the budget should be attempted.\nGetResource(...): -want requests, +got requests:\n%s\n", diff)
+	}
+
+	// The second request isn't retried, because the budget is spent.
+	requests = 0
+	_, err = c.GetResource(context.Background(), in)
+	if !IsRetryBudgetExhausted(err) {
+		t.Errorf("\nRetries should be shed once the budget is spent.\nGetResource(...): got %v", err)
+	}
+	if diff := cmp.Diff(1, requests); diff != "" {
+		t.Errorf("\nRetries should be shed once the budget is spent.\nGetResource(...): -want requests, +got requests:\n%s\n", diff)
+	}
+}
+
+// noRateLimit disables the client side retry quota of the SDK, so that only
+// the retry budget limits retries.
+type noRateLimit struct{}
+
+func (noRateLimit) GetToken(context.Context, uint) (func() error, error) {
+	return func() error { return nil }, nil
+}
+
+func (noRateLimit) AddTokens(uint) error { return nil }
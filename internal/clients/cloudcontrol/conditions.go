@@ -0,0 +1,116 @@
+package cloudcontrol
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol/types"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TypeResourceRequest resources report the outcome of their latest Cloud
+// Control resource request.
+const TypeResourceRequest xpv1.ConditionType = "ResourceRequest"
+
+// Condition reasons, one for each status of a resource request.
+const (
+	ReasonRequestPending          xpv1.ConditionReason = "RequestPending"
+	ReasonRequestInProgress       xpv1.ConditionReason = "RequestInProgress"
+	ReasonRequestSucceeded        xpv1.ConditionReason = "RequestSucceeded"
+	ReasonRequestFailed           xpv1.ConditionReason = "RequestFailed"
+	ReasonRequestCancelInProgress xpv1.ConditionReason = "RequestCancelInProgress"
+	ReasonRequestCancelled        xpv1.ConditionReason = "RequestCancelled"
+	ReasonRequestUnknown          xpv1.ConditionReason = "RequestStatusUnknown"
+)
+
+// RequestCondition returns a condition describing the resource request of the
+// supplied ProgressEvent. The reason is derived from the status of the
+// request. The message lists the operation, status, error code, event time
+// and status message of the event as space separated key=value pairs, in that
+// order, omitting those the event doesn't set, so that it can be parsed by
+// monitoring. The condition is true once the request succeeded, false if it
+// failed or was cancelled, and unknown while it's in progress.
+func RequestCondition(pe *types.ProgressEvent) xpv1.Condition {
+	c := xpv1.Condition{
+		Type:               TypeResourceRequest,
+		Status:             corev1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRequestUnknown,
+	}
+	if pe == nil {
+		return c
+	}
+
+	switch pe.OperationStatus {
+	case types.OperationStatusPending:
+		c.Reason = ReasonRequestPending
+	case types.OperationStatusInProgress:
+		c.Reason = ReasonRequestInProgress
+	case types.OperationStatusSuccess:
+		c.Status, c.Reason = corev1.ConditionTrue, ReasonRequestSucceeded
+	case types.OperationStatusFailed:
+		c.Status, c.Reason = corev1.ConditionFalse, ReasonRequestFailed
+	case types.OperationStatusCancelInProgress:
+		c.Status, c.Reason = corev1.ConditionFalse, ReasonRequestCancelInProgress
+	case types.OperationStatusCancelComplete:
+		c.Status, c.Reason = corev1.ConditionFalse, ReasonRequestCancelled
+	}
+	c.Message = requestMessage(pe)
+	return c
+}
+
+func requestMessage(pe *types.ProgressEvent) string {
+	fields := make([]string, 0, 5)
+	add := func(k, v string) {
+		if v != "" {
+			fields = append(fields, fmt.Sprintf("%s=%q", k, v))
+		}
+	}
+	add("operation", string(pe.Operation))
+	add("status", string(pe.OperationStatus))
+	add("errorCode", string(pe.ErrorCode))
+	if pe.EventTime != nil {
+		add("eventTime", pe.EventTime.UTC().Format(time.RFC3339))
+	}
+	add("statusMessage", aws.ToString(pe.StatusMessage))
+	return strings.Join(fields, " ")
+}
+
+// SetRequestConditions sets the Ready and Synced conditions of the supplied
+// resource from the Operation and OperationStatus of the supplied
+// ProgressEvent, so that a resource with a request in progress reports what
+// the request is doing rather than flapping between Ready and not Ready. A
+// failed request sets a ReconcileError carrying its status message. Nothing
+// is set if there is no ProgressEvent.
+func SetRequestConditions(o resource.Conditioned, pe *types.ProgressEvent) {
+	if pe == nil {
+		return
+	}
+	switch pe.OperationStatus { //nolint:exhaustive
+	case types.OperationStatusFailed, types.OperationStatusCancelComplete:
+		o.SetConditions(xpv1.ReconcileError(RequestError(pe)))
+		return
+	case types.OperationStatusSuccess:
+		if pe.Operation == types.OperationDelete {
+			o.SetConditions(xpv1.Deleting())
+			return
+		}
+		o.SetConditions(xpv1.Available())
+		return
+	}
+
+	// The request is pending, in progress or being cancelled.
+	switch pe.Operation { //nolint:exhaustive
+	case types.OperationCreate:
+		o.SetConditions(xpv1.Creating())
+	case types.OperationDelete:
+		o.SetConditions(xpv1.Deleting())
+	default:
+		o.SetConditions(xpv1.Unavailable())
+	}
+}
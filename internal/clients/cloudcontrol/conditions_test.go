@@ -0,0 +1,182 @@
+package cloudcontrol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol/types"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestRequestCondition(t *testing.T) {
+	eventTime := time.Date(2021, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		reason string
+		pe     *types.ProgressEvent
+		want   xpv1.Condition
+	}{
+		"NoEvent": {
+			reason: "The status of a request without a ProgressEvent is unknown.",
+			want: xpv1.Condition{
+				Type:   TypeResourceRequest,
+				Status: corev1.ConditionUnknown,
+				Reason: ReasonRequestUnknown,
+			},
+		},
+		"Pending": {
+			reason: "A pending request should report an unknown condition.",
+			pe:     &types.ProgressEvent{Operation: types.OperationCreate, OperationStatus: types.OperationStatusPending},
+			want: xpv1.Condition{
+				Type:    TypeResourceRequest,
+				Status:  corev1.ConditionUnknown,
+				Reason:  ReasonRequestPending,
+				Message: `operation="CREATE" status="PENDING"`,
+			},
+		},
+		"InProgress": {
+			reason: "A request in progress should report an unknown condition.",
+			pe:     &types.ProgressEvent{Operation: types.OperationUpdate, OperationStatus: types.OperationStatusInProgress, EventTime: &eventTime},
+			want: xpv1.Condition{
+				Type:    TypeResourceRequest,
+				Status:  corev1.ConditionUnknown,
+				Reason:  ReasonRequestInProgress,
+				Message: `operation="UPDATE" status="IN_PROGRESS" eventTime="2021-10-01T12:00:00Z"`,
+			},
+		},
+		"Success": {
+			reason: "A successful request should report a true condition.",
+			pe:     &types.ProgressEvent{Operation: types.OperationCreate, OperationStatus: types.OperationStatusSuccess},
+			want: xpv1.Condition{
+				Type:    TypeResourceRequest,
+				Status:  corev1.ConditionTrue,
+				Reason:  ReasonRequestSucceeded,
+				Message: `operation="CREATE" status="SUCCESS"`,
+			},
+		},
+		"Failed": {
+			reason: "A failed request should report a false condition with its error code and status message.",
+			pe: &types.ProgressEvent{
+				Operation:       types.OperationCreate,
+				OperationStatus: types.OperationStatusFailed,
+				ErrorCode:       types.HandlerErrorCodeAlreadyExists,
+				EventTime:       &eventTime,
+				StatusMessage:   aws.String("resource already exists"),
+			},
+			want: xpv1.Condition{
+				Type:    TypeResourceRequest,
+				Status:  corev1.ConditionFalse,
+				Reason:  ReasonRequestFailed,
+				Message: `operation="CREATE" status="FAILED" errorCode="AlreadyExists" eventTime="2021-10-01T12:00:00Z" statusMessage="resource already exists"`,
+			},
+		},
+		"CancelInProgress": {
+			reason: "A request being cancelled should report a false condition.",
+			pe:     &types.ProgressEvent{Operation: types.OperationDelete, OperationStatus: types.OperationStatusCancelInProgress},
+			want: xpv1.Condition{
+				Type:    TypeResourceRequest,
+				Status:  corev1.ConditionFalse,
+				Reason:  ReasonRequestCancelInProgress,
+				Message: `operation="DELETE" status="CANCEL_IN_PROGRESS"`,
+			},
+		},
+		"CancelComplete": {
+			reason: "A cancelled request should report a false condition.",
+			pe:     &types.ProgressEvent{Operation: types.OperationDelete, OperationStatus: types.OperationStatusCancelComplete},
+			want: xpv1.Condition{
+				Type:    TypeResourceRequest,
+				Status:  corev1.ConditionFalse,
+				Reason:  ReasonRequestCancelled,
+				Message: `operation="DELETE" status="CANCEL_COMPLETE"`,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := RequestCondition(tc.pe)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\nRequestCondition(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSetRequestConditions(t *testing.T) {
+	type want struct {
+		ready  xpv1.Condition
+		synced xpv1.Condition
+	}
+
+	none := func(ct xpv1.ConditionType) xpv1.Condition {
+		return xpv1.Condition{Type: ct, Status: corev1.ConditionUnknown}
+	}
+
+	cases := map[string]struct {
+		reason string
+		pe     *types.ProgressEvent
+		want   want
+	}{
+		"NoEvent": {
+			reason: "No conditions should be set without a ProgressEvent.",
+			want:   want{ready: none(xpv1.TypeReady), synced: none(xpv1.TypeSynced)},
+		},
+		"CreateInProgress": {
+			reason: "A resource being created should be Creating.",
+			pe:     &types.ProgressEvent{Operation: types.OperationCreate, OperationStatus: types.OperationStatusInProgress},
+			want:   want{ready: xpv1.Creating(), synced: none(xpv1.TypeSynced)},
+		},
+		"CreatePending": {
+			reason: "A resource whose creation is pending should be Creating.",
+			pe:     &types.ProgressEvent{Operation: types.OperationCreate, OperationStatus: types.OperationStatusPending},
+			want:   want{ready: xpv1.Creating(), synced: none(xpv1.TypeSynced)},
+		},
+		"UpdateInProgress": {
+			reason: "A resource being updated should be Unavailable.",
+			pe:     &types.ProgressEvent{Operation: types.OperationUpdate, OperationStatus: types.OperationStatusInProgress},
+			want:   want{ready: xpv1.Unavailable(), synced: none(xpv1.TypeSynced)},
+		},
+		"DeleteInProgress": {
+			reason: "A resource being deleted should be Deleting.",
+			pe:     &types.ProgressEvent{Operation: types.OperationDelete, OperationStatus: types.OperationStatusInProgress},
+			want:   want{ready: xpv1.Deleting(), synced: none(xpv1.TypeSynced)},
+		},
+		"CreateSucceeded": {
+			reason: "A resource whose creation succeeded should be Available.",
+			pe:     &types.ProgressEvent{Operation: types.OperationCreate, OperationStatus: types.OperationStatusSuccess},
+			want:   want{ready: xpv1.Available(), synced: none(xpv1.TypeSynced)},
+		},
+		"Failed": {
+			reason: "A failed request should set a ReconcileError carrying its status message.",
+			pe: &types.ProgressEvent{
+				Operation:       types.OperationUpdate,
+				OperationStatus: types.OperationStatusFailed,
+				ErrorCode:       types.HandlerErrorCodeInvalidRequest,
+				StatusMessage:   aws.String("invalid property"),
+			},
+			want: want{
+				ready:  none(xpv1.TypeReady),
+				synced: xpv1.ReconcileError(errors.New("resource request failed: InvalidRequest: invalid property")),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &xpv1.ConditionedStatus{}
+			SetRequestConditions(s, tc.pe)
+			ignore := cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")
+			if diff := cmp.Diff(tc.want.ready, s.GetCondition(xpv1.TypeReady), ignore); diff != "" {
+				t.Errorf("\n%s\nSetRequestConditions(...): -want ready, +got ready:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.synced, s.GetCondition(xpv1.TypeSynced), ignore); diff != "" {
+				t.Errorf("\n%s\nSetRequestConditions(...): -want synced, +got synced:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -16,7 +16,9 @@ type Client interface {
 	CancelResourceRequest(ctx context.Context, params *cloudcontrol.CancelResourceRequestInput, optFns ...func(*cloudcontrol.Options)) (*cloudcontrol.CancelResourceRequestOutput, error)
 }
 
+// GetClient returns a Cloud Control client whose retries are limited by a
+// retry budget shared with every other client it returns.
 func GetClient(c aws.Config) Client {
-	client := cloudcontrol.NewFromConfig(c)
+	client := cloudcontrol.NewFromConfig(c, WithRetryBudget(defaultRetryBudget))
 	return client
 }
\ No newline at end of file
@@ -0,0 +1,93 @@
+package cloudcontrol
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const errDriftMarshal = "cannot compare properties"
+
+// resourceUpToDate compares the supplied desired state with the supplied
+// observed properties, as returned by GetResource, and returns the JSON
+// pointers of the properties that drifted. The resource is up to date if
+// none did. Both sides are decoded, so formatting and property order don't
+// matter. Properties at the supplied read-only JSON pointers, e.g. /Arn, are
+// removed from both sides first, since they're set by AWS rather than the
+// desired state. Pointers may only address properties of objects. Only the
+// properties of the desired state are compared, since GetResource also
+// returns the properties AWS defaulted.
+func resourceUpToDate(desired, observed string, readOnlyPaths []string) ([]string, error) {
+	des := map[string]interface{}{}
+	if desired != "" {
+		if err := json.Unmarshal([]byte(desired), &des); err != nil {
+			return nil, errors.Wrap(err, errDesiredNotObject)
+		}
+	}
+	obs := map[string]interface{}{}
+	if observed != "" {
+		if err := json.Unmarshal([]byte(observed), &obs); err != nil {
+			return nil, errors.Wrap(err, errCurrentNotObject)
+		}
+	}
+	for _, p := range readOnlyPaths {
+		removePointer(des, p)
+		removePointer(obs, p)
+	}
+	removeUndesired(obs, des)
+
+	ops, err := diffObjects("", obs, des)
+	if err != nil {
+		return nil, errors.Wrap(err, errDriftMarshal)
+	}
+	drifted := make([]string, 0, len(ops))
+	for _, op := range ops {
+		drifted = append(drifted, op.Path)
+	}
+	return drifted, nil
+}
+
+// removeUndesired removes the properties the supplied desired object doesn't
+// have from the supplied observed object, recursing into the objects both
+// have.
+func removeUndesired(obs, des map[string]interface{}) {
+	for k, ov := range obs {
+		dv, ok := des[k]
+		if !ok {
+			delete(obs, k)
+			continue
+		}
+		oo, ook := ov.(map[string]interface{})
+		do, dok := dv.(map[string]interface{})
+		if ook && dok {
+			removeUndesired(oo, do)
+		}
+	}
+}
+
+// removePointer removes the property the supplied JSON pointer (RFC 6901)
+// addresses from the supplied object, if it has one.
+func removePointer(o map[string]interface{}, pointer string) {
+	if !strings.HasPrefix(pointer, "/") {
+		return
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		k := unescapePointer(t)
+		if i == len(tokens)-1 {
+			delete(o, k)
+			return
+		}
+		next, ok := o[k].(map[string]interface{})
+		if !ok {
+			return
+		}
+		o = next
+	}
+}
+
+// unescapePointer unescapes a reference token of a JSON pointer (RFC 6901).
+func unescapePointer(s string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+}
@@ -0,0 +1,105 @@
+package cloudcontrol
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestResourceUpToDate(t *testing.T) {
+	type args struct {
+		desired       string
+		observed      string
+		readOnlyPaths []string
+	}
+	type want struct {
+		drifted []string
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UpToDate": {
+			reason: "Properties that only differ in formatting and order should not drift.",
+			args: args{
+				desired:  `{"LogGroupName": "example", "RetentionInDays": 7}`,
+				observed: `{"RetentionInDays":7,"LogGroupName":"example"}`,
+			},
+			want: want{drifted: []string{}},
+		},
+		"ReadOnly": {
+			reason: "Read-only properties that only the observed properties have should not drift.",
+			args: args{
+				desired:       `{"LogGroupName": "example", "RetentionInDays": 7}`,
+				observed:      `{"Arn": "arn:aws:logs:us-west-2:123456789012:log-group:example", "LogGroupName": "example", "RetentionInDays": 7}`,
+				readOnlyPaths: []string{"/Arn"},
+			},
+			want: want{drifted: []string{}},
+		},
+		"NestedReadOnly": {
+			reason: "Nested read-only properties should not drift, but their writable siblings should.",
+			args: args{
+				desired:       `{"Config": {"Name": "new"}}`,
+				observed:      `{"Config": {"Id": "abc", "Name": "old"}}`,
+				readOnlyPaths: []string{"/Config/Id", "/Missing/Id"},
+			},
+			want: want{drifted: []string{"/Config/Name"}},
+		},
+		"EscapedReadOnly": {
+			reason: "Read-only pointers should be unescaped.",
+			args: args{
+				desired:       `{}`,
+				observed:      `{"a/b": 1, "c~d": 2}`,
+				readOnlyPaths: []string{"/a~1b", "/c~0d"},
+			},
+			want: want{drifted: []string{}},
+		},
+		"Defaulted": {
+			reason: "Properties that only the observed properties have, e.g. because AWS defaulted them, should not drift.",
+			args: args{
+				desired:  `{"LogGroupName": "example", "Config": {"Name": "example"}}`,
+				observed: `{"LogGroupName": "example", "LogGroupClass": "STANDARD", "Config": {"Name": "example", "Mode": "default"}}`,
+			},
+			want: want{drifted: []string{}},
+		},
+		"Drifted": {
+			reason: "Desired properties that changed or are missing should drift, but those only observed should not.",
+			args: args{
+				desired:  `{"LogGroupName": "example", "RetentionInDays": 14, "KmsKeyId": "alias/logs"}`,
+				observed: `{"LogGroupName": "example", "RetentionInDays": 7, "DataProtectionPolicy": {}}`,
+			},
+			want: want{drifted: []string{"/KmsKeyId", "/RetentionInDays"}},
+		},
+		"InvalidDesired": {
+			reason: "A desired state that isn't a JSON object should be an error.",
+			args: args{
+				desired: `[]`,
+			},
+			want: want{err: errors.Wrap(errors.New("json: cannot unmarshal array into Go value of type map[string]interface {}"), errDesiredNotObject)},
+		},
+		"InvalidObserved": {
+			reason: "Observed properties that aren't a JSON object should be an error.",
+			args: args{
+				observed: `"example"`,
+			},
+			want: want{err: errors.Wrap(errors.New("json: cannot unmarshal string into Go value of type map[string]interface {}"), errCurrentNotObject)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			drifted, err := resourceUpToDate(tc.args.desired, tc.args.observed, tc.args.readOnlyPaths)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nresourceUpToDate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.drifted, drifted); diff != "" {
+				t.Errorf("\n%s\nresourceUpToDate(...): -want drifted, +got drifted:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
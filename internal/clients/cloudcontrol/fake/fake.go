@@ -0,0 +1,92 @@
+package fake
+
+import (
+	"context"
+
+	awscloudcontrol "github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol/types"
+	"github.com/aws/smithy-go"
+
+	"provider-aws-controlapi/internal/clients/cloudcontrol"
+)
+
+// this ensures that the mock implements the client interface
+var _ cloudcontrol.Client = (*MockClient)(nil)
+
+// MockClient is a type that implements all the methods for Client interface
+type MockClient struct {
+	MockCreateResource           func(ctx context.Context, input *awscloudcontrol.CreateResourceInput, opts []func(*awscloudcontrol.Options)) (*awscloudcontrol.CreateResourceOutput, error)
+	MockGetResource              func(ctx context.Context, input *awscloudcontrol.GetResourceInput, opts []func(*awscloudcontrol.Options)) (*awscloudcontrol.GetResourceOutput, error)
+	MockUpdateResource           func(ctx context.Context, input *awscloudcontrol.UpdateResourceInput, opts []func(*awscloudcontrol.Options)) (*awscloudcontrol.UpdateResourceOutput, error)
+	MockListResources            func(ctx context.Context, input *awscloudcontrol.ListResourcesInput, opts []func(*awscloudcontrol.Options)) (*awscloudcontrol.ListResourcesOutput, error)
+	MockDeleteResource           func(ctx context.Context, input *awscloudcontrol.DeleteResourceInput, opts []func(*awscloudcontrol.Options)) (*awscloudcontrol.DeleteResourceOutput, error)
+	MockGetResourceRequestStatus func(ctx context.Context, input *awscloudcontrol.GetResourceRequestStatusInput, opts []func(*awscloudcontrol.Options)) (*awscloudcontrol.GetResourceRequestStatusOutput, error)
+	MockListResourceRequests     func(ctx context.Context, input *awscloudcontrol.ListResourceRequestsInput, opts []func(*awscloudcontrol.Options)) (*awscloudcontrol.ListResourceRequestsOutput, error)
+	MockCancelResourceRequest    func(ctx context.Context, input *awscloudcontrol.CancelResourceRequestInput, opts []func(*awscloudcontrol.Options)) (*awscloudcontrol.CancelResourceRequestOutput, error)
+}
+
+// CreateResource mocks CreateResource method
+func (m *MockClient) CreateResource(ctx context.Context, input *awscloudcontrol.CreateResourceInput, opts ...func(*awscloudcontrol.Options)) (*awscloudcontrol.CreateResourceOutput, error) {
+	return m.MockCreateResource(ctx, input, opts)
+}
+
+// GetResource mocks GetResource method
+func (m *MockClient) GetResource(ctx context.Context, input *awscloudcontrol.GetResourceInput, opts ...func(*awscloudcontrol.Options)) (*awscloudcontrol.GetResourceOutput, error) {
+	return m.MockGetResource(ctx, input, opts)
+}
+
+// UpdateResource mocks UpdateResource method
+func (m *MockClient) UpdateResource(ctx context.Context, input *awscloudcontrol.UpdateResourceInput, opts ...func(*awscloudcontrol.Options)) (*awscloudcontrol.UpdateResourceOutput, error) {
+	return m.MockUpdateResource(ctx, input, opts)
+}
+
+// ListResources mocks ListResources method
+func (m *MockClient) ListResources(ctx context.Context, input *awscloudcontrol.ListResourcesInput, opts ...func(*awscloudcontrol.Options)) (*awscloudcontrol.ListResourcesOutput, error) {
+	return m.MockListResources(ctx, input, opts)
+}
+
+// DeleteResource mocks DeleteResource method
+func (m *MockClient) DeleteResource(ctx context.Context, input *awscloudcontrol.DeleteResourceInput, opts ...func(*awscloudcontrol.Options)) (*awscloudcontrol.DeleteResourceOutput, error) {
+	return m.MockDeleteResource(ctx, input, opts)
+}
+
+// GetResourceRequestStatus mocks GetResourceRequestStatus method
+func (m *MockClient) GetResourceRequestStatus(ctx context.Context, input *awscloudcontrol.GetResourceRequestStatusInput, opts ...func(*awscloudcontrol.Options)) (*awscloudcontrol.GetResourceRequestStatusOutput, error) {
+	return m.MockGetResourceRequestStatus(ctx, input, opts)
+}
+
+// ListResourceRequests mocks ListResourceRequests method
+func (m *MockClient) ListResourceRequests(ctx context.Context, input *awscloudcontrol.ListResourceRequestsInput, opts ...func(*awscloudcontrol.Options)) (*awscloudcontrol.ListResourceRequestsOutput, error) {
+	return m.MockListResourceRequests(ctx, input, opts)
+}
+
+// CancelResourceRequest mocks CancelResourceRequest method
+func (m *MockClient) CancelResourceRequest(ctx context.Context, input *awscloudcontrol.CancelResourceRequestInput, opts ...func(*awscloudcontrol.Options)) (*awscloudcontrol.CancelResourceRequestOutput, error) {
+	return m.MockCancelResourceRequest(ctx, input, opts)
+}
+
+// ProgressEvent returns a ProgressEvent of a request with the supplied token,
+// operation and status, as returned by the mutating methods and by
+// GetResourceRequestStatus.
+func ProgressEvent(token string, op types.Operation, status types.OperationStatus) *types.ProgressEvent {
+	return &types.ProgressEvent{
+		RequestToken:    &token,
+		Operation:       op,
+		OperationStatus: status,
+	}
+}
+
+// FailedProgressEvent returns a ProgressEvent of a request with the supplied
+// token and operation that failed with the supplied error code and message.
+func FailedProgressEvent(token string, op types.Operation, code types.HandlerErrorCode, msg string) *types.ProgressEvent {
+	pe := ProgressEvent(token, op, types.OperationStatusFailed)
+	pe.ErrorCode = code
+	pe.StatusMessage = &msg
+	return pe
+}
+
+// APIError returns an error as returned by the Cloud Control API with the
+// supplied error code, e.g. ResourceNotFoundException.
+func APIError(code string) error {
+	return &smithy.GenericAPIError{Code: code}
+}
@@ -0,0 +1,41 @@
+package cloudcontrol
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol/types"
+	"github.com/pkg/errors"
+)
+
+const errListResources = "cannot list resources of type %s"
+
+// ListAllResources returns every resource of the supplied type, listing all
+// pages. The resources are listed with the IAM role of the supplied ARN, or
+// with the credentials of the client if it's empty.
+func ListAllResources(ctx context.Context, c Client, typeName, roleARN string) ([]types.ResourceDescription, error) {
+	var (
+		all   []types.ResourceDescription
+		token *string
+		role  *string
+	)
+	if roleARN != "" {
+		role = aws.String(roleARN)
+	}
+	for {
+		resp, err := c.ListResources(ctx, &cloudcontrol.ListResourcesInput{
+			TypeName:  aws.String(typeName),
+			RoleArn:   role,
+			NextToken: token,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, errListResources, typeName)
+		}
+		all = append(all, resp.ResourceDescriptions...)
+		if aws.ToString(resp.NextToken) == "" {
+			return all, nil
+		}
+		token = resp.NextToken
+	}
+}
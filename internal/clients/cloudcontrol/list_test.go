@@ -0,0 +1,106 @@
+package cloudcontrol
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol/types"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+)
+
+// pageClient returns the supplied pages of resources in order. If an error is
+// supplied, every page has a NextToken and the error is returned once the
+// pages are exhausted.
+type pageClient struct {
+	Client
+	pages [][]types.ResourceDescription
+	err   error
+	calls []*cloudcontrol.ListResourcesInput
+}
+
+func (c *pageClient) ListResources(_ context.Context, in *cloudcontrol.ListResourcesInput, _ ...func(*cloudcontrol.Options)) (*cloudcontrol.ListResourcesOutput, error) {
+	c.calls = append(c.calls, in)
+	i := len(c.calls) - 1
+	if i >= len(c.pages) {
+		return nil, c.err
+	}
+	out := &cloudcontrol.ListResourcesOutput{ResourceDescriptions: c.pages[i]}
+	if i < len(c.pages)-1 || c.err != nil {
+		out.NextToken = aws.String(string(rune('a' + i)))
+	}
+	return out, nil
+}
+
+func description(id string) types.ResourceDescription {
+	return types.ResourceDescription{Identifier: aws.String(id), Properties: aws.String(`{"LogGroupName":"` + id + `"}`)}
+}
+
+func TestListAllResources(t *testing.T) {
+	errBoom := errors.New("boom")
+	typeName := "AWS::Logs::LogGroup"
+	role := "arn:aws:iam::123456789012:role/example"
+
+	type want struct {
+		resources []types.ResourceDescription
+		calls     []*cloudcontrol.ListResourcesInput
+		err       error
+	}
+	cases := map[string]struct {
+		reason  string
+		client  *pageClient
+		roleARN string
+		want    want
+	}{
+		"TwoPages": {
+			reason:  "Resources of every page should be returned, following the NextToken of each page.",
+			client:  &pageClient{pages: [][]types.ResourceDescription{{description("a"), description("b")}, {description("c")}}},
+			roleARN: role,
+			want: want{
+				resources: []types.ResourceDescription{description("a"), description("b"), description("c")},
+				calls: []*cloudcontrol.ListResourcesInput{
+					{TypeName: aws.String(typeName), RoleArn: aws.String(role)},
+					{TypeName: aws.String(typeName), RoleArn: aws.String(role), NextToken: aws.String("a")},
+				},
+			},
+		},
+		"NoRole": {
+			reason: "Resources should be listed without a role if no role ARN is given.",
+			client: &pageClient{pages: [][]types.ResourceDescription{{description("a")}}},
+			want: want{
+				resources: []types.ResourceDescription{description("a")},
+				calls:     []*cloudcontrol.ListResourcesInput{{TypeName: aws.String(typeName)}},
+			},
+		},
+		"ListFailed": {
+			reason: "An error listing a page should be returned.",
+			client: &pageClient{pages: [][]types.ResourceDescription{{description("a")}}, err: errBoom},
+			want: want{
+				err: errors.Wrapf(errBoom, errListResources, typeName),
+				calls: []*cloudcontrol.ListResourcesInput{
+					{TypeName: aws.String(typeName)},
+					{TypeName: aws.String(typeName), NextToken: aws.String("a")},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ListAllResources(context.Background(), tc.client, typeName, tc.roleARN)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nListAllResources(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.resources, got, cmpopts.IgnoreUnexported(types.ResourceDescription{})); diff != "" {
+				t.Errorf("\n%s\nListAllResources(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.calls, tc.client.calls, cmpopts.IgnoreUnexported(cloudcontrol.ListResourcesInput{})); diff != "" {
+				t.Errorf("\n%s\nListResources(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
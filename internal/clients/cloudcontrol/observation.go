@@ -0,0 +1,66 @@
+package cloudcontrol
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errPropertiesNotObject = "observed properties must be a JSON object"
+	errObservationMarshal  = "cannot marshal observed properties"
+)
+
+// TruncatedMarker replaces the value of a property that is nested deeper
+// than the observation depth.
+const TruncatedMarker = "<truncated>"
+
+// DefaultObservationDepth is how many levels of nested properties are
+// recorded in the status of a resource unless its controller is configured
+// with another depth, which is passed to TruncateProperties. The top level
+// properties are the first level.
+const DefaultObservationDepth = 5
+
+// TruncateProperties returns the supplied properties, as returned by
+// GetResource, with the objects and arrays nested deeper than the supplied
+// depth replaced by TruncatedMarker. Large resources can have deeply nested
+// properties that are costly to store in their status. A depth of zero or
+// less records all properties.
+func TruncateProperties(properties string, depth int) (string, error) {
+	if properties == "" || depth <= 0 {
+		return properties, nil
+	}
+	p := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(properties), &p); err != nil {
+		return "", errors.Wrap(err, errPropertiesNotObject)
+	}
+	b, err := json.Marshal(truncate(p, depth))
+	return string(b), errors.Wrap(err, errObservationMarshal)
+}
+
+// truncate returns the supplied value, which is at the supplied remaining
+// depth, with the objects and arrays it contains beyond that depth replaced
+// by TruncatedMarker. Scalars are never truncated.
+func truncate(v interface{}, depth int) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if depth <= 0 {
+			return TruncatedMarker
+		}
+		out := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			out[k] = truncate(e, depth-1)
+		}
+		return out
+	case []interface{}:
+		if depth <= 0 {
+			return TruncatedMarker
+		}
+		out := make([]interface{}, len(t))
+		for i, e := range t {
+			out[i] = truncate(e, depth-1)
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,93 @@
+package cloudcontrol
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestTruncateProperties(t *testing.T) {
+	type args struct {
+		properties string
+		depth      int
+	}
+	type want struct {
+		properties string
+		err        error
+	}
+
+	properties := `{"BucketName": "logs", "BucketEncryption": {"ServerSideEncryptionConfiguration": [{"ServerSideEncryptionByDefault": {"SSEAlgorithm": "aws:kms"}}]}, "Tags": [{"Key": "team", "Value": "a"}]}`
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Unlimited": {
+			reason: "All properties should be recorded if the depth isn't limited.",
+			args: args{
+				properties: properties,
+			},
+			want: want{
+				properties: properties,
+			},
+		},
+		"WithinDepth": {
+			reason: "Properties nested no deeper than the depth should be recorded as they are.",
+			args: args{
+				properties: properties,
+				depth:      5,
+			},
+			want: want{
+				properties: properties,
+			},
+		},
+		"TopLevel": {
+			reason: "Nested objects and arrays should be truncated at a depth of one.",
+			args: args{
+				properties: properties,
+				depth:      1,
+			},
+			want: want{
+				properties: `{"BucketName": "logs", "BucketEncryption": "<truncated>", "Tags": "<truncated>"}`,
+			},
+		},
+		"BeyondDepth": {
+			reason: "Properties nested deeper than the depth should be truncated, and scalars within it kept.",
+			args: args{
+				properties: properties,
+				depth:      3,
+			},
+			want: want{
+				properties: `{"BucketName": "logs", "BucketEncryption": {"ServerSideEncryptionConfiguration": ["<truncated>"]}, "Tags": [{"Key": "team", "Value": "a"}]}`,
+			},
+		},
+		"InvalidProperties": {
+			reason: "Properties that are not a JSON object should return an error.",
+			args: args{
+				properties: `["a"]`,
+				depth:      1,
+			},
+			want: want{
+				err: errors.Wrap(errors.New("json: cannot unmarshal array into Go value of type map[string]interface {}"), errPropertiesNotObject),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := TruncateProperties(tc.args.properties, tc.args.depth)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nTruncateProperties(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			if diff := cmp.Diff(unmarshal(t, tc.want.properties), unmarshal(t, got)); diff != "" {
+				t.Errorf("\n%s\nTruncateProperties(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
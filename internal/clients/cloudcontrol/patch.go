@@ -0,0 +1,132 @@
+package cloudcontrol
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+	"github.com/pkg/errors"
+)
+
+const (
+	errCurrentNotObject = "current properties must be a JSON object"
+	errDesiredNotObject = "desired properties must be a JSON object"
+	errPatchMarshal     = "cannot marshal patch document"
+	errUpdateResource   = "cannot update resource"
+)
+
+// JSON Patch (RFC 6902) operations.
+const (
+	opAdd     = "add"
+	opRemove  = "remove"
+	opReplace = "replace"
+)
+
+// A patchOperation is an operation of a JSON Patch document.
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// PatchDocument returns the JSON Patch (RFC 6902) document that turns the
+// supplied current properties, as returned by GetResource, into the supplied
+// desired state, for use as the PatchDocument of UpdateResource. Objects are
+// diffed recursively, any other value that differs is replaced as a whole.
+// An empty string is returned if the resource is up to date, in which case
+// UpdateResource should not be called.
+func PatchDocument(current, desired string) (string, error) {
+	cur := map[string]interface{}{}
+	if current != "" {
+		if err := json.Unmarshal([]byte(current), &cur); err != nil {
+			return "", errors.Wrap(err, errCurrentNotObject)
+		}
+	}
+	des := map[string]interface{}{}
+	if desired != "" {
+		if err := json.Unmarshal([]byte(desired), &des); err != nil {
+			return "", errors.Wrap(err, errDesiredNotObject)
+		}
+	}
+
+	ops, err := diffObjects("", cur, des)
+	if err != nil {
+		return "", errors.Wrap(err, errPatchMarshal)
+	}
+	if len(ops) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(ops)
+	return string(b), errors.Wrap(err, errPatchMarshal)
+}
+
+// UpdateResource updates the resource of the supplied type and identifier
+// from its current properties to the desired state. It returns a nil output
+// without calling Cloud Control if the resource is up to date.
+func UpdateResource(ctx context.Context, c Client, t ResourceType, identifier, current, desired, clientToken string) (*cloudcontrol.UpdateResourceOutput, error) {
+	patch, err := PatchDocument(current, desired)
+	if err != nil || patch == "" {
+		return nil, err
+	}
+	out, err := c.UpdateResource(ctx, t.UpdateInput(identifier, patch, clientToken))
+	return out, errors.Wrap(err, errUpdateResource)
+}
+
+// diffObjects returns the operations that turn object a into object b, whose
+// paths are prefixed with the supplied JSON pointer. Operations are ordered
+// by property name so that the document is stable.
+func diffObjects(prefix string, a, b map[string]interface{}) ([]patchOperation, error) {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var ops []patchOperation
+	for _, k := range keys {
+		path := prefix + "/" + escapePointer(k)
+		av, inA := a[k]
+		bv, inB := b[k]
+		switch {
+		case !inB:
+			ops = append(ops, patchOperation{Op: opRemove, Path: path})
+			continue
+		case reflect.DeepEqual(av, bv):
+			continue
+		}
+		ao, aok := av.(map[string]interface{})
+		bo, bok := bv.(map[string]interface{})
+		if inA && aok && bok {
+			nested, err := diffObjects(path, ao, bo)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, nested...)
+			continue
+		}
+		v, err := json.Marshal(bv)
+		if err != nil {
+			return nil, err
+		}
+		op := opReplace
+		if !inA {
+			op = opAdd
+		}
+		ops = append(ops, patchOperation{Op: op, Path: path, Value: v})
+	}
+	return ops, nil
+}
+
+// escapePointer escapes a property name for use as a reference token of a
+// JSON pointer (RFC 6901).
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
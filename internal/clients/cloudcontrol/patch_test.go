@@ -0,0 +1,187 @@
+package cloudcontrol
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestPatchDocument(t *testing.T) {
+	type args struct {
+		current string
+		desired string
+	}
+	type want struct {
+		patch string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UpToDate": {
+			reason: "No patch should be returned if the current properties are the desired state.",
+			args: args{
+				current: `{"LogGroupName": "example", "RetentionInDays": 7, "Tags": [{"Key": "team", "Value": "a"}]}`,
+				desired: `{"RetentionInDays": 7, "LogGroupName": "example", "Tags": [{"Key": "team", "Value": "a"}]}`,
+			},
+		},
+		"Added": {
+			reason: "Properties that are only desired should be added.",
+			args: args{
+				current: `{"LogGroupName": "example"}`,
+				desired: `{"LogGroupName": "example", "RetentionInDays": 7}`,
+			},
+			want: want{
+				patch: `[{"op": "add", "path": "/RetentionInDays", "value": 7}]`,
+			},
+		},
+		"Removed": {
+			reason: "Properties that aren't desired anymore should be removed.",
+			args: args{
+				current: `{"LogGroupName": "example", "RetentionInDays": 7}`,
+				desired: `{"LogGroupName": "example"}`,
+			},
+			want: want{
+				patch: `[{"op": "remove", "path": "/RetentionInDays"}]`,
+			},
+		},
+		"Replaced": {
+			reason: "Properties whose value changed should be replaced, with lists replaced as a whole.",
+			args: args{
+				current: `{"RetentionInDays": 7, "Tags": [{"Key": "team", "Value": "a"}]}`,
+				desired: `{"RetentionInDays": 30, "Tags": [{"Key": "team", "Value": "b"}]}`,
+			},
+			want: want{
+				patch: `[{"op": "replace", "path": "/RetentionInDays", "value": 30}, {"op": "replace", "path": "/Tags", "value": [{"Key": "team", "Value": "b"}]}]`,
+			},
+		},
+		"Nested": {
+			reason: "Objects should be diffed recursively, and property names escaped in paths.",
+			args: args{
+				current: `{"Encryption": {"Algorithm": "AES256", "BucketKey": true}}`,
+				desired: `{"Encryption": {"Algorithm": "aws:kms", "a/b~c": "x"}}`,
+			},
+			want: want{
+				patch: `[{"op": "replace", "path": "/Encryption/Algorithm", "value": "aws:kms"}, {"op": "remove", "path": "/Encryption/BucketKey"}, {"op": "add", "path": "/Encryption/a~1b~0c", "value": "x"}]`,
+			},
+		},
+		"ObjectReplacesScalar": {
+			reason: "A value that changes type should be replaced as a whole.",
+			args: args{
+				current: `{"Policy": "{}"}`,
+				desired: `{"Policy": {"Version": "2012-10-17"}}`,
+			},
+			want: want{
+				patch: `[{"op": "replace", "path": "/Policy", "value": {"Version": "2012-10-17"}}]`,
+			},
+		},
+		"Null": {
+			reason: "A desired null value should be sent rather than dropped.",
+			args: args{
+				current: `{"KmsKeyId": "alias/example"}`,
+				desired: `{"KmsKeyId": null}`,
+			},
+			want: want{
+				patch: `[{"op": "replace", "path": "/KmsKeyId", "value": null}]`,
+			},
+		},
+		"CurrentNotObject": {
+			reason: "Current properties that aren't a JSON object should be rejected.",
+			args: args{
+				current: `[]`,
+			},
+			want: want{
+				err: errors.Wrap(errors.New("json: cannot unmarshal array into Go value of type map[string]interface {}"), errCurrentNotObject),
+			},
+		},
+		"DesiredNotObject": {
+			reason: "A desired state that isn't a JSON object should be rejected.",
+			args: args{
+				desired: `"example"`,
+			},
+			want: want{
+				err: errors.Wrap(errors.New("json: cannot unmarshal string into Go value of type map[string]interface {}"), errDesiredNotObject),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := PatchDocument(tc.args.current, tc.args.desired)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPatchDocument(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.patch == "" {
+				if got != "" {
+					t.Errorf("\n%s\nPatchDocument(...): want no patch, got %s\n", tc.reason, got)
+				}
+				return
+			}
+			var wantOps, gotOps interface{}
+			if err := json.Unmarshal([]byte(tc.want.patch), &wantOps); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(got), &gotOps); err != nil {
+				t.Fatalf("\n%s\nPatchDocument(...): invalid JSON %q: %v\n", tc.reason, got, err)
+			}
+			if diff := cmp.Diff(wantOps, gotOps); diff != "" {
+				t.Errorf("\n%s\nPatchDocument(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// An updateRecorder records the patch documents of UpdateResource requests.
+type updateRecorder struct {
+	Client
+	patches []string
+}
+
+func (r *updateRecorder) UpdateResource(_ context.Context, in *cloudcontrol.UpdateResourceInput, _ ...func(*cloudcontrol.Options)) (*cloudcontrol.UpdateResourceOutput, error) {
+	r.patches = append(r.patches, aws.ToString(in.PatchDocument))
+	return &cloudcontrol.UpdateResourceOutput{}, nil
+}
+
+func TestUpdateResource(t *testing.T) {
+	rt := ResourceType{TypeName: "AWS::Logs::LogGroup"}
+
+	cases := map[string]struct {
+		reason  string
+		current string
+		desired string
+		want    []string
+	}{
+		"UpToDate": {
+			reason:  "UpdateResource shouldn't be called if the resource is up to date.",
+			current: `{"RetentionInDays": 7}`,
+			desired: `{"RetentionInDays": 7}`,
+		},
+		"Changed": {
+			reason:  "UpdateResource should be called with the patch of a changed resource.",
+			current: `{"RetentionInDays": 7}`,
+			desired: `{"RetentionInDays": 30}`,
+			want:    []string{`[{"op":"replace","path":"/RetentionInDays","value":30}]`},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &updateRecorder{}
+			if _, err := UpdateResource(context.Background(), c, rt, "example", tc.current, tc.desired, "token"); err != nil {
+				t.Fatalf("\n%s\nUpdateResource(...): %v\n", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, c.patches); diff != "" {
+				t.Errorf("\n%s\nUpdateResource(...): -want patches, +got patches:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
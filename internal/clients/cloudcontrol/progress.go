@@ -0,0 +1,94 @@
+package cloudcontrol
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol/types"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	errGetRequestStatus = "cannot get resource request status"
+	errRequestFailed    = "resource request failed"
+	errRequestCancelled = "resource request was cancelled"
+)
+
+// errRequestInProgress is returned when a resource request didn't reach a
+// terminal status before the backoff was exhausted.
+var errRequestInProgress = errors.New("resource request is still in progress")
+
+// DefaultRequestBackoff bounds how long WaitForRequest polls a resource
+// request, about a minute in total, so that a reconcile isn't blocked by a
+// slow operation. Callers should keep the request token and resume waiting on
+// the next reconcile.
+var DefaultRequestBackoff = wait.Backoff{
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    6,
+	Cap:      30 * time.Second,
+}
+
+// IsRequestInProgress returns true if the supplied error was returned by
+// WaitForRequest for a request that is still in progress.
+func IsRequestInProgress(err error) bool {
+	return errors.Is(err, errRequestInProgress)
+}
+
+// IsTerminal returns true if a resource request with the supplied status
+// won't progress any further.
+func IsTerminal(s types.OperationStatus) bool {
+	switch s {
+	case types.OperationStatusSuccess, types.OperationStatusFailed, types.OperationStatusCancelComplete:
+		return true
+	default:
+		return false
+	}
+}
+
+// WaitForRequest polls the status of the resource request with the supplied
+// token until it reaches a terminal status or the backoff is exhausted. The
+// last ProgressEvent is always returned when the status could be read, so that
+// callers can persist its RequestToken if the request is still in progress.
+// Requests that failed or were cancelled return an error carrying the
+// ErrorCode and StatusMessage of the event.
+func WaitForRequest(ctx context.Context, c Client, token string, b wait.Backoff) (*types.ProgressEvent, error) {
+	var pe *types.ProgressEvent
+	err := wait.ExponentialBackoffWithContext(ctx, b, func() (bool, error) {
+		resp, err := c.GetResourceRequestStatus(ctx, &cloudcontrol.GetResourceRequestStatusInput{
+			RequestToken: aws.String(token),
+		})
+		if err != nil {
+			return false, errors.Wrap(err, errGetRequestStatus)
+		}
+		pe = resp.ProgressEvent
+		return pe != nil && IsTerminal(pe.OperationStatus), nil
+	})
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return pe, errRequestInProgress
+	}
+	if err != nil {
+		return pe, err
+	}
+	return pe, RequestError(pe)
+}
+
+// RequestError returns an error describing why the supplied ProgressEvent
+// didn't succeed, or nil if it did or is still in progress.
+func RequestError(pe *types.ProgressEvent) error {
+	if pe == nil {
+		return nil
+	}
+	switch pe.OperationStatus { //nolint:exhaustive
+	case types.OperationStatusFailed:
+		return errors.Errorf("%s: %s: %s", errRequestFailed, pe.ErrorCode, aws.ToString(pe.StatusMessage))
+	case types.OperationStatusCancelComplete:
+		return errors.New(errRequestCancelled)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,117 @@
+package cloudcontrol
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol/types"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// statusClient returns the supplied statuses in order, repeating the last one.
+type statusClient struct {
+	Client
+	statuses []types.OperationStatus
+	err      error
+	calls    int
+}
+
+func (c *statusClient) GetResourceRequestStatus(_ context.Context, in *cloudcontrol.GetResourceRequestStatusInput, _ ...func(*cloudcontrol.Options)) (*cloudcontrol.GetResourceRequestStatusOutput, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	s := c.statuses[len(c.statuses)-1]
+	if c.calls < len(c.statuses) {
+		s = c.statuses[c.calls]
+	}
+	c.calls++
+	pe := &types.ProgressEvent{RequestToken: in.RequestToken, OperationStatus: s}
+	if s == types.OperationStatusFailed {
+		pe.ErrorCode = types.HandlerErrorCodeAlreadyExists
+		pe.StatusMessage = aws.String("resource already exists")
+	}
+	return &cloudcontrol.GetResourceRequestStatusOutput{ProgressEvent: pe}, nil
+}
+
+func TestWaitForRequest(t *testing.T) {
+	const token = "token"
+	errBoom := errors.New("boom")
+	b := wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3}
+
+	type want struct {
+		status types.OperationStatus
+		calls  int
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      *statusClient
+		want   want
+	}{
+		"Success": {
+			reason: "A request should be polled until it succeeds.",
+			c:      &statusClient{statuses: []types.OperationStatus{types.OperationStatusPending, types.OperationStatusInProgress, types.OperationStatusSuccess}},
+			want:   want{status: types.OperationStatusSuccess, calls: 3},
+		},
+		"Failed": {
+			reason: "A failed request should return its error code and status message.",
+			c:      &statusClient{statuses: []types.OperationStatus{types.OperationStatusFailed}},
+			want: want{
+				status: types.OperationStatusFailed,
+				calls:  1,
+				err:    errors.New(errRequestFailed + ": AlreadyExists: resource already exists"),
+			},
+		},
+		"Cancelled": {
+			reason: "A cancelled request should return an error.",
+			c:      &statusClient{statuses: []types.OperationStatus{types.OperationStatusCancelInProgress, types.OperationStatusCancelComplete}},
+			want:   want{status: types.OperationStatusCancelComplete, calls: 2, err: errors.New(errRequestCancelled)},
+		},
+		"StillInProgress": {
+			reason: "A request that doesn't finish within the backoff should return the last event.",
+			c:      &statusClient{statuses: []types.OperationStatus{types.OperationStatusInProgress}},
+			want:   want{status: types.OperationStatusInProgress, calls: 3, err: errRequestInProgress},
+		},
+		"GetStatusFailed": {
+			reason: "Errors getting the request status should be returned.",
+			c:      &statusClient{err: errBoom},
+			want:   want{err: errors.Wrap(errBoom, errGetRequestStatus)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pe, err := WaitForRequest(context.Background(), tc.c, token, b)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nWaitForRequest(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.calls, tc.c.calls); diff != "" {
+				t.Errorf("\n%s\nWaitForRequest(...): -want calls, +got calls:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.status == "" {
+				return
+			}
+			want := &types.ProgressEvent{RequestToken: aws.String(token), OperationStatus: tc.want.status}
+			if diff := cmp.Diff(want, pe, cmpopts.IgnoreFields(types.ProgressEvent{}, "ErrorCode", "StatusMessage"), cmpopts.IgnoreUnexported(types.ProgressEvent{})); diff != "" {
+				t.Errorf("\n%s\nWaitForRequest(...): -want event, +got event:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsRequestInProgress(t *testing.T) {
+	if !IsRequestInProgress(errors.Wrap(errRequestInProgress, "cannot create resource")) {
+		t.Errorf("IsRequestInProgress(...): want true for a wrapped in progress error")
+	}
+	if IsRequestInProgress(errors.New(errRequestCancelled)) {
+		t.Errorf("IsRequestInProgress(...): want false for other errors")
+	}
+}
@@ -0,0 +1,61 @@
+package cloudcontrol
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+)
+
+// A ResourceType identifies the CloudFormation registry type Cloud Control
+// requests act on.
+type ResourceType struct {
+	// TypeName of the resource, e.g. AWS::Logs::LogGroup.
+	TypeName string
+
+	// TypeVersionID pins the version of the type. The default version of the
+	// type in the registry is used if it's nil.
+	TypeVersionID *string
+}
+
+// CreateInput returns the input that creates a resource of this type with
+// the supplied desired state.
+func (t ResourceType) CreateInput(desiredState, clientToken string) *cloudcontrol.CreateResourceInput {
+	return &cloudcontrol.CreateResourceInput{
+		TypeName:      aws.String(t.TypeName),
+		TypeVersionId: t.TypeVersionID,
+		DesiredState:  aws.String(desiredState),
+		ClientToken:   aws.String(clientToken),
+	}
+}
+
+// UpdateInput returns the input that applies the supplied JSON patch to the
+// resource of this type with the supplied identifier.
+func (t ResourceType) UpdateInput(identifier, patchDocument, clientToken string) *cloudcontrol.UpdateResourceInput {
+	return &cloudcontrol.UpdateResourceInput{
+		TypeName:      aws.String(t.TypeName),
+		TypeVersionId: t.TypeVersionID,
+		Identifier:    aws.String(identifier),
+		PatchDocument: aws.String(patchDocument),
+		ClientToken:   aws.String(clientToken),
+	}
+}
+
+// GetInput returns the input that reads the resource of this type with the
+// supplied identifier.
+func (t ResourceType) GetInput(identifier string) *cloudcontrol.GetResourceInput {
+	return &cloudcontrol.GetResourceInput{
+		TypeName:      aws.String(t.TypeName),
+		TypeVersionId: t.TypeVersionID,
+		Identifier:    aws.String(identifier),
+	}
+}
+
+// DeleteInput returns the input that deletes the resource of this type with
+// the supplied identifier.
+func (t ResourceType) DeleteInput(identifier, clientToken string) *cloudcontrol.DeleteResourceInput {
+	return &cloudcontrol.DeleteResourceInput{
+		TypeName:      aws.String(t.TypeName),
+		TypeVersionId: t.TypeVersionID,
+		Identifier:    aws.String(identifier),
+		ClientToken:   aws.String(clientToken),
+	}
+}
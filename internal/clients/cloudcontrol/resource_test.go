@@ -0,0 +1,54 @@
+package cloudcontrol
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestResourceTypeInputs(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		rt     ResourceType
+		want   *string
+	}{
+		"Pinned": {
+			reason: "A pinned type version should be forwarded with every request.",
+			rt:     ResourceType{TypeName: "AWS::Logs::LogGroup", TypeVersionID: aws.String("00000003")},
+			want:   aws.String("00000003"),
+		},
+		"Default": {
+			reason: "No type version should be sent if none is pinned, so that the registry default is used.",
+			rt:     ResourceType{TypeName: "AWS::Logs::LogGroup"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := []*string{
+				tc.rt.CreateInput("{}", "token").TypeVersionId,
+				tc.rt.UpdateInput("example", "[]", "token").TypeVersionId,
+				tc.rt.GetInput("example").TypeVersionId,
+				tc.rt.DeleteInput("example", "token").TypeVersionId,
+			}
+			want := []*string{tc.want, tc.want, tc.want, tc.want}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\n%s\nResourceType inputs: -want TypeVersionId, +got TypeVersionId:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+
+	rt := ResourceType{TypeName: "AWS::Logs::LogGroup", TypeVersionID: aws.String("00000003")}
+	want := &cloudcontrol.CreateResourceInput{
+		TypeName:      aws.String("AWS::Logs::LogGroup"),
+		TypeVersionId: aws.String("00000003"),
+		DesiredState:  aws.String(`{"LogGroupName": "example"}`),
+		ClientToken:   aws.String("token"),
+	}
+	if diff := cmp.Diff(want, rt.CreateInput(`{"LogGroupName": "example"}`, "token"), cmpopts.IgnoreUnexported(cloudcontrol.CreateResourceInput{})); diff != "" {
+		t.Errorf("\nThe desired state should be sent with the type.\nCreateInput(...): -want, +got:\n%s\n", diff)
+	}
+}
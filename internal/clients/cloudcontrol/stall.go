@@ -0,0 +1,120 @@
+package cloudcontrol
+
+import (
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol/types"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationKeyRequestFailures is the annotation that records the resource
+// requests of a managed resource that failed in a row with the same error
+// code.
+const AnnotationKeyRequestFailures = "controlapi.aws/request-failures"
+
+// DefaultMaxRequestFailures is how many resource requests of a managed
+// resource may fail in a row with the same error code before it stalls.
+const DefaultMaxRequestFailures = 3
+
+// TypeStalled resources report whether their creates and updates are paused
+// because their resource requests keep failing.
+const TypeStalled xpv1.ConditionType = "Stalled"
+
+// Condition reasons of the Stalled condition.
+const (
+	ReasonNotStalled             xpv1.ConditionReason = "NotStalled"
+	ReasonRepeatedRequestFailure xpv1.ConditionReason = "RepeatedRequestFailure"
+)
+
+// RequestFailures are the resource requests of a managed resource that failed
+// in a row with the same error code.
+type RequestFailures struct {
+	// ErrorCode the requests failed with.
+	ErrorCode types.HandlerErrorCode `json:"errorCode"`
+
+	// Count of the requests.
+	Count int `json:"count"`
+
+	// Generation of the managed resource whose desired state the requests
+	// applied.
+	Generation int64 `json:"generation"`
+}
+
+// GetRequestFailures returns the request failures recorded in the
+// annotations of the supplied managed resource. A resource without failures,
+// or whose annotation can't be parsed, has none.
+func GetRequestFailures(o metav1.Object) RequestFailures {
+	f := RequestFailures{}
+	v, ok := o.GetAnnotations()[AnnotationKeyRequestFailures]
+	if !ok || json.Unmarshal([]byte(v), &f) != nil {
+		return RequestFailures{}
+	}
+	return f
+}
+
+// RecordRequestResult records the outcome of the resource request of the
+// supplied ProgressEvent in the annotations of the supplied managed resource,
+// which the caller must persist. A failure with the same error code as the
+// recorded ones, for the same generation of the resource, is counted along
+// with them; any other failure replaces them. A request that succeeded clears
+// them. Requests that are in progress or were cancelled aren't recorded.
+func RecordRequestResult(o metav1.Object, pe *types.ProgressEvent) {
+	if pe == nil {
+		return
+	}
+	if pe.OperationStatus == types.OperationStatusSuccess {
+		meta.RemoveAnnotations(o, AnnotationKeyRequestFailures)
+		return
+	}
+	if pe.OperationStatus != types.OperationStatusFailed {
+		return
+	}
+	f := GetRequestFailures(o)
+	if f.ErrorCode != pe.ErrorCode || f.Generation != o.GetGeneration() {
+		f = RequestFailures{ErrorCode: pe.ErrorCode, Generation: o.GetGeneration()}
+	}
+	f.Count++
+	b, _ := json.Marshal(f)
+	meta.AddAnnotations(o, map[string]string{AnnotationKeyRequestFailures: string(b)})
+}
+
+// StalledError returns an error if the latest resource requests of the
+// supplied managed resource failed at least the supplied number of times in a
+// row with the same error code. Creating or updating the resource again would
+// likely fail the same way and exhaust the request quota of the account, so
+// it should be paused until the desired state, and thereby the generation of
+// the resource, changes. A maximum of zero or less never stalls.
+func StalledError(o metav1.Object, maxFailures int) error {
+	f := GetRequestFailures(o)
+	if maxFailures <= 0 || f.Generation != o.GetGeneration() || f.Count < maxFailures {
+		return nil
+	}
+	return errors.Errorf("resource requests failed %d times in a row with error code %s, change the desired state to retry", f.Count, f.ErrorCode)
+}
+
+// Stalled returns a condition that indicates the creates and updates of a
+// resource are paused because its resource requests keep failing.
+func Stalled(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeStalled,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRepeatedRequestFailure,
+		Message:            err.Error(),
+	}
+}
+
+// NotStalled returns a condition that indicates the creates and updates of a
+// resource aren't paused.
+func NotStalled() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeStalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNotStalled,
+	}
+}
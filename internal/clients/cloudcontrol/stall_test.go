@@ -0,0 +1,83 @@
+package cloudcontrol
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol/types"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStalledError(t *testing.T) {
+	failed := func(code types.HandlerErrorCode) *types.ProgressEvent {
+		return &types.ProgressEvent{Operation: types.OperationUpdate, OperationStatus: types.OperationStatusFailed, ErrorCode: code}
+	}
+	succeeded := &types.ProgressEvent{Operation: types.OperationUpdate, OperationStatus: types.OperationStatusSuccess}
+	inProgress := &types.ProgressEvent{Operation: types.OperationUpdate, OperationStatus: types.OperationStatusInProgress}
+	errStalled := func(count int, code types.HandlerErrorCode) error {
+		return errors.Errorf("resource requests failed %d times in a row with error code %s, change the desired state to retry", count, code)
+	}
+
+	cases := map[string]struct {
+		reason      string
+		events      []*types.ProgressEvent
+		generation  int64
+		maxFailures int
+		want        error
+	}{
+		"NoFailures": {
+			reason:      "A resource whose requests didn't fail shouldn't stall.",
+			events:      []*types.ProgressEvent{succeeded},
+			maxFailures: 3,
+		},
+		"BelowMaximum": {
+			reason:      "A resource whose requests failed fewer times than the maximum should be retried.",
+			events:      []*types.ProgressEvent{failed(types.HandlerErrorCodeServiceLimitExceeded), failed(types.HandlerErrorCodeServiceLimitExceeded)},
+			maxFailures: 3,
+		},
+		"RepeatedFailures": {
+			reason:      "A resource whose requests failed the maximum number of times with the same error code should stall.",
+			events:      []*types.ProgressEvent{failed(types.HandlerErrorCodeServiceLimitExceeded), inProgress, failed(types.HandlerErrorCodeServiceLimitExceeded), failed(types.HandlerErrorCodeServiceLimitExceeded)},
+			maxFailures: 3,
+			want:        errStalled(3, types.HandlerErrorCodeServiceLimitExceeded),
+		},
+		"DifferentErrorCodes": {
+			reason:      "Failures with another error code should start counting again.",
+			events:      []*types.ProgressEvent{failed(types.HandlerErrorCodeServiceLimitExceeded), failed(types.HandlerErrorCodeServiceLimitExceeded), failed(types.HandlerErrorCodeInvalidRequest)},
+			maxFailures: 3,
+		},
+		"Succeeded": {
+			reason:      "A request that succeeded should clear the failures before it.",
+			events:      []*types.ProgressEvent{failed(types.HandlerErrorCodeServiceLimitExceeded), failed(types.HandlerErrorCodeServiceLimitExceeded), succeeded, failed(types.HandlerErrorCodeServiceLimitExceeded)},
+			maxFailures: 3,
+		},
+		"DesiredStateChanged": {
+			reason:      "A resource whose desired state changed since its requests failed should be retried.",
+			events:      []*types.ProgressEvent{failed(types.HandlerErrorCodeServiceLimitExceeded), failed(types.HandlerErrorCodeServiceLimitExceeded), failed(types.HandlerErrorCodeServiceLimitExceeded)},
+			generation:  2,
+			maxFailures: 3,
+		},
+		"Disabled": {
+			reason: "A resource should never stall if there is no maximum.",
+			events: []*types.ProgressEvent{failed(types.HandlerErrorCodeServiceLimitExceeded), failed(types.HandlerErrorCodeServiceLimitExceeded), failed(types.HandlerErrorCodeServiceLimitExceeded)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := &metav1.ObjectMeta{Generation: 1}
+			for _, pe := range tc.events {
+				RecordRequestResult(o, pe)
+			}
+			if tc.generation != 0 {
+				o.SetGeneration(tc.generation)
+			}
+			got := StalledError(o, tc.maxFailures)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nStalledError(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
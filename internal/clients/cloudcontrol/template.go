@@ -0,0 +1,59 @@
+package cloudcontrol
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errTemplateNotObject = "template properties must be a JSON object"
+	errOverrideNotObject = "override properties must be a JSON object"
+	errMergeMarshal      = "cannot marshal merged properties"
+)
+
+// MergeDesiredState overlays the override properties of an individual
+// resource on top of the base properties defined by a shared template and
+// returns the desired state that should be sent to Cloud Control. The merge
+// follows JSON Merge Patch (RFC 7386) semantics: objects are merged
+// recursively, any other value in the override replaces the template value
+// and a null override removes the property from the template.
+func MergeDesiredState(template, override string) (string, error) {
+	base := map[string]interface{}{}
+	if template != "" {
+		if err := json.Unmarshal([]byte(template), &base); err != nil {
+			return "", errors.Wrap(err, errTemplateNotObject)
+		}
+	}
+	if override == "" {
+		override = "{}"
+	}
+	patch := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(override), &patch); err != nil {
+		return "", errors.Wrap(err, errOverrideNotObject)
+	}
+
+	b, err := json.Marshal(mergeObjects(base, patch))
+	return string(b), errors.Wrap(err, errMergeMarshal)
+}
+
+// mergeObjects merges patch into base, modifying and returning base.
+func mergeObjects(base, patch map[string]interface{}) map[string]interface{} {
+	if base == nil {
+		base = map[string]interface{}{}
+	}
+	for k, pv := range patch {
+		if pv == nil {
+			delete(base, k)
+			continue
+		}
+		po, ok := pv.(map[string]interface{})
+		if !ok {
+			base[k] = pv
+			continue
+		}
+		bo, _ := base[k].(map[string]interface{})
+		base[k] = mergeObjects(bo, po)
+	}
+	return base
+}
@@ -0,0 +1,120 @@
+package cloudcontrol
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestMergeDesiredState(t *testing.T) {
+	type args struct {
+		template string
+		override string
+	}
+	type want struct {
+		state string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"TemplateOnly": {
+			reason: "A resource without overrides should use the template properties.",
+			args: args{
+				template: `{"RetentionInDays": 7, "LogGroupClass": "STANDARD"}`,
+			},
+			want: want{
+				state: `{"LogGroupClass": "STANDARD", "RetentionInDays": 7}`,
+			},
+		},
+		"OverrideScalar": {
+			reason: "An override should replace the template value of the same property.",
+			args: args{
+				template: `{"RetentionInDays": 7, "LogGroupClass": "STANDARD"}`,
+				override: `{"RetentionInDays": 30, "LogGroupName": "fleet-a"}`,
+			},
+			want: want{
+				state: `{"LogGroupClass": "STANDARD", "LogGroupName": "fleet-a", "RetentionInDays": 30}`,
+			},
+		},
+		"OverrideNested": {
+			reason: "Nested objects should be merged rather than replaced.",
+			args: args{
+				template: `{"VersioningConfiguration": {"Status": "Enabled"}, "Encryption": {"Algorithm": "AES256", "BucketKey": true}}`,
+				override: `{"Encryption": {"Algorithm": "aws:kms"}}`,
+			},
+			want: want{
+				state: `{"Encryption": {"Algorithm": "aws:kms", "BucketKey": true}, "VersioningConfiguration": {"Status": "Enabled"}}`,
+			},
+		},
+		"OverrideList": {
+			reason: "Lists in the override should replace the template list.",
+			args: args{
+				template: `{"Tags": [{"Key": "team", "Value": "a"}, {"Key": "env", "Value": "dev"}]}`,
+				override: `{"Tags": [{"Key": "team", "Value": "b"}]}`,
+			},
+			want: want{
+				state: `{"Tags": [{"Key": "team", "Value": "b"}]}`,
+			},
+		},
+		"RemoveProperty": {
+			reason: "A null override should remove the property from the template.",
+			args: args{
+				template: `{"RetentionInDays": 7, "KmsKeyId": "alias/logs"}`,
+				override: `{"KmsKeyId": null}`,
+			},
+			want: want{
+				state: `{"RetentionInDays": 7}`,
+			},
+		},
+		"InvalidTemplate": {
+			reason: "A template that is not a JSON object should return an error.",
+			args: args{
+				template: `["a"]`,
+			},
+			want: want{
+				err: errors.Wrap(errors.New("json: cannot unmarshal array into Go value of type map[string]interface {}"), errTemplateNotObject),
+			},
+		},
+		"InvalidOverride": {
+			reason: "An override that is not a JSON object should return an error.",
+			args: args{
+				template: `{}`,
+				override: `"a"`,
+			},
+			want: want{
+				err: errors.Wrap(errors.New("json: cannot unmarshal string into Go value of type map[string]interface {}"), errOverrideNotObject),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := MergeDesiredState(tc.args.template, tc.args.override)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nMergeDesiredState(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			if diff := cmp.Diff(unmarshal(t, tc.want.state), unmarshal(t, got)); diff != "" {
+				t.Errorf("\n%s\nMergeDesiredState(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func unmarshal(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("cannot unmarshal %q: %v", s, err)
+	}
+	return v
+}
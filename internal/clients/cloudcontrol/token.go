@@ -0,0 +1,34 @@
+package cloudcontrol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClientToken returns the client token of requests made for the supplied
+// managed resource. It's derived from the UID of the resource, so Cloud
+// Control coalesces every Create retried for the same resource, e.g. after a
+// restart between the Create call and persisting its result, rather than
+// creating duplicates.
+func ClientToken(o metav1.Object) string {
+	return hashToken(string(o.GetUID()))
+}
+
+// GenerationClientToken returns a client token derived from the UID and
+// generation of the supplied managed resource. Retries of an update are
+// coalesced, while a later change of the resource's spec isn't mistaken for
+// one of them.
+func GenerationClientToken(o metav1.Object) string {
+	return hashToken(string(o.GetUID()) + "/" + strconv.FormatInt(o.GetGeneration(), 10))
+}
+
+// hashToken hashes the supplied value into a token of hex characters, which
+// satisfies the length and pattern constraints of Cloud Control client
+// tokens.
+func hashToken(v string) string {
+	h := sha256.Sum256([]byte(v))
+	return hex.EncodeToString(h[:])
+}
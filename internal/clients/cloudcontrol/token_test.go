@@ -0,0 +1,48 @@
+package cloudcontrol
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestClientToken(t *testing.T) {
+	rt := ResourceType{TypeName: "AWS::Logs::LogGroup"}
+	object := func(uid string, generation int64) metav1.Object {
+		return &metav1.ObjectMeta{UID: types.UID(uid), Generation: generation}
+	}
+
+	first := rt.CreateInput("{}", ClientToken(object("a", 1)))
+	retry := rt.CreateInput("{}", ClientToken(object("a", 2)))
+	if diff := cmp.Diff(aws.ToString(first.ClientToken), aws.ToString(retry.ClientToken)); diff != "" {
+		t.Errorf("\nCreate calls for the same resource should use the same token.\nClientToken(...): -first, +retry:\n%s\n", diff)
+	}
+
+	other := rt.CreateInput("{}", ClientToken(object("b", 1)))
+	if aws.ToString(first.ClientToken) == aws.ToString(other.ClientToken) {
+		t.Errorf("\nCreate calls for different resources should use different tokens.\nClientToken(...): got %q for both\n", aws.ToString(first.ClientToken))
+	}
+
+	if len(aws.ToString(first.ClientToken)) > 128 {
+		t.Errorf("\nTokens should not exceed the 128 characters Cloud Control accepts.\nClientToken(...): got %d characters\n", len(aws.ToString(first.ClientToken)))
+	}
+}
+
+func TestGenerationClientToken(t *testing.T) {
+	object := func(uid string, generation int64) metav1.Object {
+		return &metav1.ObjectMeta{UID: types.UID(uid), Generation: generation}
+	}
+
+	if GenerationClientToken(object("a", 1)) != GenerationClientToken(object("a", 1)) {
+		t.Errorf("\nRequests for the same generation of a resource should use the same token.\n")
+	}
+	if GenerationClientToken(object("a", 1)) == GenerationClientToken(object("a", 2)) {
+		t.Errorf("\nRequests for different generations of a resource should use different tokens.\n")
+	}
+	if GenerationClientToken(object("a", 1)) == GenerationClientToken(object("b", 1)) {
+		t.Errorf("\nRequests for different resources should use different tokens.\n")
+	}
+}
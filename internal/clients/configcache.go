@@ -0,0 +1,256 @@
+package aws
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"provider-aws-controlapi/apis/v1beta1"
+)
+
+const (
+	errGetCallerIdentity = "cannot get caller identity"
+	errRecordCredentials = "cannot record credentials in ProviderConfig status"
+)
+
+// defaultConfigCache is shared by all controllers so that resources using the
+// same ProviderConfig share its credentials.
+var defaultConfigCache = newConfigCache(newConfig, newCallerIdentityClient)
+
+// credentialsKey identifies the credentials of a version of a ProviderConfig
+// in a partition. The generation of a ProviderConfig only changes with its
+// spec, unlike its resource version, which also changes when its status is
+// written.
+type credentialsKey struct {
+	name       string
+	generation int64
+	partition  string
+}
+
+// configCacheKey identifies a version of a ProviderConfig used in a region.
+type configCacheKey struct {
+	credentialsKey
+	region string
+}
+
+type configBuilder func(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig, region string) (*aws.Config, error)
+
+// A CallerIdentityClient returns the identity AWS requests are made as.
+type CallerIdentityClient interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
+func newCallerIdentityClient(cfg aws.Config) CallerIdentityClient {
+	return sts.NewFromConfig(cfg)
+}
+
+// configCache caches the *aws.Config built for each ProviderConfig. Building a
+// config constructs a new credentials provider, which for assumed roles means
+// a new STS AssumeRole call on every reconcile. The cached config keeps its
+// aws.CredentialsCache, which refreshes the credentials when they expire, so
+// entries only need to be replaced when the ProviderConfig changes. Note that
+// a rotated credentials Secret isn't picked up until the ProviderConfig is
+// updated.
+// A config is only built once for each partition a ProviderConfig is used in.
+// The configs of the regions of the partition are copies of it that share its
+// credentials provider, so spreading resources across regions doesn't
+// multiply the credentials that are obtained.
+type configCache struct {
+	mu         sync.Mutex
+	built      map[credentialsKey]*aws.Config
+	configs    map[configCacheKey]*aws.Config
+	accountIDs map[configCacheKey]string
+	build      configBuilder
+	identity   func(aws.Config) CallerIdentityClient
+}
+
+func newConfigCache(b configBuilder, i func(aws.Config) CallerIdentityClient) *configCache {
+	return &configCache{
+		built:      map[credentialsKey]*aws.Config{},
+		configs:    map[configCacheKey]*aws.Config{},
+		accountIDs: map[configCacheKey]string{},
+		build:      b,
+		identity:   i,
+	}
+}
+
+// Get returns the cached *aws.Config for the supplied ProviderConfig and
+// region, building it if this version of the ProviderConfig hasn't been seen
+// in the partition of the region.
+func (cc *configCache) Get(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig, region string) (*aws.Config, error) {
+	ck := credentialsKey{name: pc.GetName(), generation: pc.GetGeneration(), partition: partition(pc, region)}
+	key := configCacheKey{credentialsKey: ck, region: region}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cfg, ok := cc.configs[key]; ok {
+		return cfg, nil
+	}
+	built, ok := cc.built[ck]
+	if !ok {
+		var err error
+		if built, err = cc.build(ctx, c, pc, region); err != nil {
+			return nil, err
+		}
+		if built.Credentials != nil {
+			built.Credentials = &observedCredentials{provider: built.Credentials}
+		}
+		// Drop the configs built for older versions of this ProviderConfig.
+		for k := range cc.built {
+			if k.name == ck.name && k.partition == ck.partition {
+				delete(cc.built, k)
+			}
+		}
+		for k := range cc.configs {
+			if k.name == ck.name && k.partition == ck.partition {
+				delete(cc.configs, k)
+				delete(cc.accountIDs, k)
+			}
+		}
+		cc.built[ck] = built
+	}
+	cfg := built.Copy()
+	cfg.Region = region
+	cc.configs[key] = &cfg
+	return &cfg, nil
+}
+
+// AccountID returns the ID of the AWS account requests made with the supplied
+// config act in. The identity is looked up with the credentials of the
+// config, so for a ProviderConfig that assumes a role it's the account of the
+// role rather than that of the base credentials. Lookups for configs returned
+// by Get are cached alongside the config.
+func (cc *configCache) AccountID(ctx context.Context, cfg *aws.Config) (string, error) {
+	cc.mu.Lock()
+	key, cached := configCacheKey{}, false
+	for k, v := range cc.configs {
+		if v == cfg {
+			key, cached = k, true
+			break
+		}
+	}
+	id, ok := cc.accountIDs[key]
+	cc.mu.Unlock()
+	if cached && ok {
+		return id, nil
+	}
+
+	resp, err := cc.identity(*cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", errors.Wrap(err, errGetCallerIdentity)
+	}
+	id = aws.ToString(resp.Account)
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cached && cc.configs[key] == cfg {
+		cc.accountIDs[key] = id
+	}
+	return id, nil
+}
+
+// GetAccountID returns the ID of the AWS account requests made with the
+// supplied config, as returned by GetConfig, act in.
+func GetAccountID(ctx context.Context, cfg *aws.Config) (string, error) {
+	return defaultConfigCache.AccountID(ctx, cfg)
+}
+
+// RecordCredentials records when the credentials of the supplied config, as
+// returned by Get, were last obtained, their source, and the account they act
+// in in the status of the supplied ProviderConfig. Credentials are obtained
+// lazily by the first request that needs them, so they're recorded the next
+// time the ProviderConfig is used. The account is only looked up, and the
+// status only written, once each time the credentials are refreshed.
+// Losing a write to a conflicting one is not an error, since the credentials
+// will be recorded the next time the ProviderConfig is used.
+func (cc *configCache) RecordCredentials(ctx context.Context, kube client.Client, pc *v1beta1.ProviderConfig, cfg *aws.Config) error {
+	oc, ok := cfg.Credentials.(*observedCredentials)
+	if !ok {
+		return nil
+	}
+	creds, obtainedAt, ok := oc.unrecorded()
+	if !ok {
+		return nil
+	}
+
+	// Look the account up with the refreshed credentials.
+	cc.mu.Lock()
+	for k, v := range cc.configs {
+		if v == cfg {
+			delete(cc.accountIDs, k)
+		}
+	}
+	cc.mu.Unlock()
+	id, err := cc.AccountID(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	t := metav1.NewTime(obtainedAt)
+	pc.Status.CredentialsObtainedAt = &t
+	pc.Status.CredentialsSource = creds.Source
+	pc.Status.AccountID = id
+	err = kube.Status().Update(ctx, pc)
+	if kerrors.IsConflict(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errRecordCredentials)
+	}
+	oc.recorded(obtainedAt)
+	return nil
+}
+
+// observedCredentials observes when the credentials of the provider it wraps
+// are obtained, so that they can be recorded in the status of the
+// ProviderConfig that describes them.
+type observedCredentials struct {
+	provider aws.CredentialsProvider
+
+	mu         sync.Mutex
+	creds      aws.Credentials
+	obtainedAt time.Time
+	recordedAt time.Time
+}
+
+// Retrieve returns the credentials of the wrapped provider. Credentials that
+// differ from the last ones it returned were refreshed.
+func (o *observedCredentials) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	creds, err := o.provider.Retrieve(ctx)
+	if err != nil {
+		return creds, err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.obtainedAt.IsZero() || creds != o.creds {
+		o.creds, o.obtainedAt = creds, time.Now()
+	}
+	return creds, nil
+}
+
+// unrecorded returns the last credentials that were obtained and when, if
+// they haven't been recorded.
+func (o *observedCredentials) unrecorded() (aws.Credentials, time.Time, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.obtainedAt.IsZero() || o.obtainedAt.Equal(o.recordedAt) {
+		return aws.Credentials{}, time.Time{}, false
+	}
+	return o.creds, o.obtainedAt, true
+}
+
+// recorded notes that the credentials obtained at the supplied time were
+// recorded.
+func (o *observedCredentials) recorded(obtainedAt time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.recordedAt = obtainedAt
+}
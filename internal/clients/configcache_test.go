@@ -0,0 +1,371 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"provider-aws-controlapi/apis/v1beta1"
+)
+
+func providerConfig(name string, generation int64) *v1beta1.ProviderConfig {
+	return &v1beta1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: name, Generation: generation}}
+}
+
+func TestConfigCacheGet(t *testing.T) {
+	type call struct {
+		pc     *v1beta1.ProviderConfig
+		region string
+	}
+
+	type want struct {
+		builds int
+		err    error
+	}
+
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		calls  []call
+		want   want
+	}{
+		"SameProviderConfig": {
+			reason: "A second Get for the same ProviderConfig should not rebuild the config.",
+			calls: []call{
+				{pc: providerConfig("default", 1), region: "us-west-2"},
+				{pc: providerConfig("default", 1), region: "us-west-2"},
+			},
+			want: want{builds: 1},
+		},
+		"StatusChanged": {
+			reason: "Writing the status of the ProviderConfig should not rebuild the config.",
+			calls: []call{
+				{pc: providerConfig("default", 1), region: "us-west-2"},
+				{pc: func() *v1beta1.ProviderConfig {
+					pc := providerConfig("default", 1)
+					pc.SetResourceVersion("2")
+					return pc
+				}(), region: "us-west-2"},
+			},
+			want: want{builds: 1},
+		},
+		"ProviderConfigChanged": {
+			reason: "A new version of the ProviderConfig should rebuild the config.",
+			calls: []call{
+				{pc: providerConfig("default", 1), region: "us-west-2"},
+				{pc: providerConfig("default", 2), region: "us-west-2"},
+			},
+			want: want{builds: 2},
+		},
+		"DifferentRegion": {
+			reason: "Each region should get its own copy of the config built for the ProviderConfig.",
+			calls: []call{
+				{pc: providerConfig("default", 1), region: "us-west-2"},
+				{pc: providerConfig("default", 1), region: "eu-west-1"},
+				{pc: providerConfig("default", 1), region: "us-west-2"},
+			},
+			want: want{builds: 1},
+		},
+		"DifferentPartition": {
+			reason: "Each partition should get its own config, since credentials can't be shared across partitions.",
+			calls: []call{
+				{pc: providerConfig("default", 1), region: "us-west-2"},
+				{pc: providerConfig("default", 1), region: "us-gov-west-1"},
+			},
+			want: want{builds: 2},
+		},
+		"BuildFailed": {
+			reason: "Configs that fail to build should not be cached.",
+			err:    errBoom,
+			calls: []call{
+				{pc: providerConfig("default", 1), region: "us-west-2"},
+				{pc: providerConfig("default", 1), region: "us-west-2"},
+			},
+			want: want{builds: 2, err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			builds := 0
+			cc := newConfigCache(func(_ context.Context, _ client.Client, _ *v1beta1.ProviderConfig, region string) (*aws.Config, error) {
+				builds++
+				if tc.err != nil {
+					return nil, tc.err
+				}
+				return &aws.Config{Region: region}, nil
+			}, nil)
+
+			var err error
+			for _, c := range tc.calls {
+				var cfg *aws.Config
+				cfg, err = cc.Get(context.Background(), nil, c.pc, c.region)
+				if err == nil && cfg.Region != c.region {
+					t.Errorf("\n%s\ncc.Get(...): want region %q, got %q\n", tc.reason, c.region, cfg.Region)
+				}
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncc.Get(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.builds, builds); diff != "" {
+				t.Errorf("\n%s\ncc.Get(...): -want builds, +got builds:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConfigCacheSharedCredentials(t *testing.T) {
+	cc := newConfigCache(func(_ context.Context, _ client.Client, _ *v1beta1.ProviderConfig, region string) (*aws.Config, error) {
+		return &aws.Config{Region: region, Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", "")}, nil
+	}, nil)
+
+	west, err := cc.Get(context.Background(), nil, providerConfig("default", 1), "us-west-2")
+	if err != nil {
+		t.Fatalf("cc.Get(...): %s", err)
+	}
+	eu, err := cc.Get(context.Background(), nil, providerConfig("default", 1), "eu-west-1")
+	if err != nil {
+		t.Fatalf("cc.Get(...): %s", err)
+	}
+	if west.Region != "us-west-2" || eu.Region != "eu-west-1" {
+		t.Errorf("cc.Get(...): want regions us-west-2 and eu-west-1, got %q and %q", west.Region, eu.Region)
+	}
+	if west.Credentials != eu.Credentials {
+		t.Errorf("cc.Get(...): want the configs of both regions to share a credentials provider")
+	}
+}
+
+// identityClient returns the account of the access key it was built with.
+type identityClient struct {
+	cfg      aws.Config
+	accounts map[string]string
+	calls    *int
+}
+
+func (c *identityClient) GetCallerIdentity(ctx context.Context, _ *sts.GetCallerIdentityInput, _ ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	*c.calls++
+	creds, err := c.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &sts.GetCallerIdentityOutput{Account: aws.String(c.accounts[creds.AccessKeyID])}, nil
+}
+
+func TestConfigCacheAccountID(t *testing.T) {
+	const (
+		baseAccount    = "111111111111"
+		assumedAccount = "222222222222"
+	)
+
+	// The base credentials belong to one account, the role they assume to
+	// another.
+	accounts := map[string]string{"base": baseAccount, "assumed": assumedAccount}
+	build := func(_ context.Context, _ client.Client, pc *v1beta1.ProviderConfig, region string) (*aws.Config, error) {
+		key := "base"
+		if pc.Spec.AssumeRoleARN != nil {
+			key = "assumed"
+		}
+		return &aws.Config{Region: region, Credentials: credentials.NewStaticCredentialsProvider(key, "secret", "")}, nil
+	}
+
+	withRole := func(pc *v1beta1.ProviderConfig) *v1beta1.ProviderConfig {
+		pc.Spec.AssumeRoleARN = aws.String("arn:aws:iam::" + assumedAccount + ":role/crossplane")
+		return pc
+	}
+
+	type want struct {
+		accountID string
+		lookups   int
+	}
+
+	cases := map[string]struct {
+		reason string
+		pcs    []*v1beta1.ProviderConfig
+		want   want
+	}{
+		"BaseCredentials": {
+			reason: "The account of the credentials should be used when no role is assumed.",
+			pcs:    []*v1beta1.ProviderConfig{providerConfig("default", 1)},
+			want:   want{accountID: baseAccount, lookups: 1},
+		},
+		"AssumedRole": {
+			reason: "The account of the assumed role should be used rather than that of the base credentials.",
+			pcs:    []*v1beta1.ProviderConfig{withRole(providerConfig("default", 1))},
+			want:   want{accountID: assumedAccount, lookups: 1},
+		},
+		"Cached": {
+			reason: "The account should only be looked up once per ProviderConfig version.",
+			pcs: []*v1beta1.ProviderConfig{
+				withRole(providerConfig("default", 1)),
+				withRole(providerConfig("default", 1)),
+			},
+			want: want{accountID: assumedAccount, lookups: 1},
+		},
+		"ProviderConfigChanged": {
+			reason: "The account should be looked up again when the ProviderConfig starts assuming a role.",
+			pcs: []*v1beta1.ProviderConfig{
+				providerConfig("default", 1),
+				withRole(providerConfig("default", 2)),
+			},
+			want: want{accountID: assumedAccount, lookups: 2},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			lookups := 0
+			cc := newConfigCache(build, func(cfg aws.Config) CallerIdentityClient {
+				return &identityClient{cfg: cfg, accounts: accounts, calls: &lookups}
+			})
+
+			var id string
+			for _, pc := range tc.pcs {
+				cfg, err := cc.Get(context.Background(), nil, pc, "us-west-2")
+				if err != nil {
+					t.Fatalf("\n%s\ncc.Get(...): %s\n", tc.reason, err)
+				}
+				if id, err = cc.AccountID(context.Background(), cfg); err != nil {
+					t.Fatalf("\n%s\ncc.AccountID(...): %s\n", tc.reason, err)
+				}
+			}
+			if diff := cmp.Diff(tc.want.accountID, id); diff != "" {
+				t.Errorf("\n%s\ncc.AccountID(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.lookups, lookups); diff != "" {
+				t.Errorf("\n%s\ncc.AccountID(...): -want lookups, +got lookups:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConfigCacheRecordCredentials(t *testing.T) {
+	accounts := map[string]string{"first": "111111111111", "second": "222222222222"}
+	errBoom := errors.New("boom")
+	errConflict := kerrors.NewConflict(schema.GroupResource{}, "default", errBoom)
+
+	// A step optionally obtains credentials with the supplied access key,
+	// then records them.
+	type step struct {
+		obtain string
+		update error
+	}
+
+	type want struct {
+		err     error
+		written []v1beta1.ProviderConfigStatus
+		lookups int
+	}
+
+	status := func(account string) v1beta1.ProviderConfigStatus {
+		return v1beta1.ProviderConfigStatus{CredentialsSource: "TestProvider", AccountID: account}
+	}
+
+	cases := map[string]struct {
+		reason string
+		steps  []step
+		want   want
+	}{
+		"NotObtained": {
+			reason: "Nothing should be recorded before credentials are obtained.",
+			steps:  []step{{}},
+		},
+		"Obtained": {
+			reason: "Obtained credentials should be recorded with the account they act in.",
+			steps:  []step{{obtain: "first"}},
+			want:   want{written: []v1beta1.ProviderConfigStatus{status("111111111111")}, lookups: 1},
+		},
+		"AlreadyRecorded": {
+			reason: "Credentials should only be recorded once until they're refreshed.",
+			steps:  []step{{obtain: "first"}, {obtain: "first"}, {}},
+			want:   want{written: []v1beta1.ProviderConfigStatus{status("111111111111")}, lookups: 1},
+		},
+		"Refreshed": {
+			reason: "Refreshed credentials should be recorded again.",
+			steps:  []step{{obtain: "first"}, {obtain: "second"}},
+			want: want{
+				written: []v1beta1.ProviderConfigStatus{status("111111111111"), status("222222222222")},
+				lookups: 2,
+			},
+		},
+		"Conflict": {
+			reason: "Credentials whose status write conflicted should be recorded again.",
+			steps:  []step{{obtain: "first", update: errConflict}, {}},
+			want: want{
+				written: []v1beta1.ProviderConfigStatus{status("111111111111"), status("111111111111")},
+				lookups: 2,
+			},
+		},
+		"UpdateFailed": {
+			reason: "Errors writing the status should be returned.",
+			steps:  []step{{obtain: "first", update: errBoom}},
+			want: want{
+				err:     errors.Wrap(errBoom, errRecordCredentials),
+				written: []v1beta1.ProviderConfigStatus{status("111111111111")},
+				lookups: 1,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			key := ""
+			lookups := 0
+			cc := newConfigCache(func(_ context.Context, _ client.Client, _ *v1beta1.ProviderConfig, region string) (*aws.Config, error) {
+				return &aws.Config{Region: region, Credentials: aws.CredentialsProviderFunc(func(_ context.Context) (aws.Credentials, error) {
+					return aws.Credentials{AccessKeyID: key, SecretAccessKey: "secret", Source: "TestProvider"}, nil
+				})}, nil
+			}, func(cfg aws.Config) CallerIdentityClient {
+				return &identityClient{cfg: cfg, accounts: accounts, calls: &lookups}
+			})
+
+			var written []v1beta1.ProviderConfigStatus
+			var err error
+			for _, s := range tc.steps {
+				update := s.update
+				kube := &test.MockClient{MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+					written = append(written, obj.(*v1beta1.ProviderConfig).Status)
+					return update
+				}}
+				pc := providerConfig("default", 1)
+				cfg, gerr := cc.Get(context.Background(), nil, pc, "us-west-2")
+				if gerr != nil {
+					t.Fatalf("\n%s\ncc.Get(...): %s\n", tc.reason, gerr)
+				}
+				if s.obtain != "" {
+					key = s.obtain
+					if _, err := cfg.Credentials.Retrieve(context.Background()); err != nil {
+						t.Fatalf("\n%s\ncfg.Credentials.Retrieve(...): %s\n", tc.reason, err)
+					}
+				}
+				err = cc.RecordCredentials(context.Background(), kube, pc, cfg)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ncc.RecordCredentials(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			for _, s := range written {
+				if s.CredentialsObtainedAt == nil {
+					t.Errorf("\n%s\ncc.RecordCredentials(...): want the time credentials were obtained recorded\n", tc.reason)
+				}
+			}
+			if diff := cmp.Diff(tc.want.written, written, cmpopts.IgnoreFields(v1beta1.ProviderConfigStatus{}, "CredentialsObtainedAt")); diff != "" {
+				t.Errorf("\n%s\ncc.RecordCredentials(...): -want written status, +got written status:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.lookups, lookups); diff != "" {
+				t.Errorf("\n%s\ncc.RecordCredentials(...): -want lookups, +got lookups:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package aws
+
+import (
+	"strconv"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+)
+
+// AnnotationKeyDeletionProtection is the annotation that protects the
+// external resource of a managed resource from deletion if it's "true".
+const AnnotationKeyDeletionProtection = "controlapi.crossplane.io/deletion-protection"
+
+const errDeletionProtected = "deletion protection is enabled; remove the %s annotation to delete the external resource, or set the deletion policy to Orphan to keep it"
+
+// CheckDeletionProtection returns an error if the external resource of the
+// supplied managed resource is protected from deletion.
+func CheckDeletionProtection(mg resource.Managed) error {
+	if protected, _ := strconv.ParseBool(mg.GetAnnotations()[AnnotationKeyDeletionProtection]); !protected {
+		return nil
+	}
+	return errors.Errorf(errDeletionProtected, AnnotationKeyDeletionProtection)
+}
@@ -0,0 +1,76 @@
+package aws
+
+import (
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"provider-aws-controlapi/apis/v1beta1"
+)
+
+const (
+	errResolveEndpoint = "cannot resolve endpoint"
+	errRecordEndpoint  = "cannot record endpoint in ProviderConfig status"
+)
+
+// RecordEndpoint records the endpoint the supplied config resolves to for the
+// supplied service and region in the status of the supplied ProviderConfig,
+// which the config was built from, so operators can verify where calls are
+// sent.
+// Nothing is recorded for configs or services that use the default endpoints
+// of the SDK.
+// The status is only written when the recorded endpoint changes, and losing a
+// write to a conflicting one is not an error, since the endpoint will be
+// recorded again the next time a client is built.
+func RecordEndpoint(ctx context.Context, kube client.Client, pc *v1beta1.ProviderConfig, cfg *aws.Config, service, region string) error {
+	if cfg.EndpointResolverWithOptions == nil {
+		return nil
+	}
+	e, err := cfg.EndpointResolverWithOptions.ResolveEndpoint(service, region)
+	var nf *aws.EndpointNotFoundError
+	if errors.As(err, &nf) {
+		// The service uses the endpoint the SDK resolves.
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errResolveEndpoint)
+	}
+
+	se := v1beta1.ServiceEndpoint{Service: service, Region: region, URL: e.URL}
+	endpoints, changed := setEndpoint(pc.Status.Endpoints, se)
+	if !changed {
+		return nil
+	}
+	pc.Status.Endpoints = endpoints
+	if err := kube.Status().Update(ctx, pc); err != nil && !kerrors.IsConflict(err) {
+		return errors.Wrap(err, errRecordEndpoint)
+	}
+	return nil
+}
+
+// setEndpoint adds or replaces the endpoint of the service and region of the
+// supplied endpoint, keeping the endpoints sorted. It reports whether the
+// endpoints changed.
+func setEndpoint(endpoints []v1beta1.ServiceEndpoint, se v1beta1.ServiceEndpoint) ([]v1beta1.ServiceEndpoint, bool) {
+	for i, e := range endpoints {
+		if e.Service == se.Service && e.Region == se.Region {
+			if e == se {
+				return endpoints, false
+			}
+			endpoints[i] = se
+			return endpoints, true
+		}
+	}
+	endpoints = append(endpoints, se)
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Service != endpoints[j].Service {
+			return endpoints[i].Service < endpoints[j].Service
+		}
+		return endpoints[i].Region < endpoints[j].Region
+	})
+	return endpoints, true
+}
@@ -0,0 +1,156 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"provider-aws-controlapi/apis/v1beta1"
+)
+
+func TestRecordEndpoint(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	dynamic := &v1beta1.EndpointConfig{URL: v1beta1.URLConfig{
+		Type:    URLConfigTypeDynamic,
+		Dynamic: &v1beta1.DynamicURLConfig{Protocol: "https", Host: "example.org"},
+	}}
+	snsEndpoint := v1beta1.ServiceEndpoint{Service: "SNS", Region: "us-west-2", URL: "https://sns.us-west-2.example.org"}
+	stsEndpoint := v1beta1.ServiceEndpoint{Service: "STS", Region: "us-west-2", URL: "https://sts.us-west-2.example.org"}
+
+	type args struct {
+		endpoint *v1beta1.EndpointConfig
+		recorded []v1beta1.ServiceEndpoint
+		update   error
+		service  string
+	}
+	type want struct {
+		err       error
+		endpoints []v1beta1.ServiceEndpoint
+		updated   bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"DefaultEndpoints": {
+			reason: "Nothing should be recorded when the SDK's default endpoints are used.",
+			args:   args{service: "SNS"},
+		},
+		"SDKResolvedService": {
+			reason: "Nothing should be recorded for a service that uses the endpoint the SDK resolves.",
+			args: args{
+				endpoint: &v1beta1.EndpointConfig{ServiceOverrides: map[string]v1beta1.URLConfig{"sts": dynamic.URL}},
+				service:  "SNS",
+			},
+		},
+		"ServiceOverride": {
+			reason: "The endpoint a service override resolves to should be recorded.",
+			args: args{
+				endpoint: &v1beta1.EndpointConfig{ServiceOverrides: map[string]v1beta1.URLConfig{"sns": dynamic.URL}},
+				service:  "SNS",
+			},
+			want: want{
+				endpoints: []v1beta1.ServiceEndpoint{snsEndpoint},
+				updated:   true,
+			},
+		},
+		"Recorded": {
+			reason: "The endpoint of each service should be listed in the status, sorted by service.",
+			args: args{
+				endpoint: dynamic,
+				recorded: []v1beta1.ServiceEndpoint{stsEndpoint},
+				service:  "SNS",
+			},
+			want: want{
+				endpoints: []v1beta1.ServiceEndpoint{snsEndpoint, stsEndpoint},
+				updated:   true,
+			},
+		},
+		"Unchanged": {
+			reason: "The status should not be written if the endpoint is already recorded.",
+			args: args{
+				endpoint: dynamic,
+				recorded: []v1beta1.ServiceEndpoint{snsEndpoint},
+				service:  "SNS",
+			},
+			want: want{
+				endpoints: []v1beta1.ServiceEndpoint{snsEndpoint},
+			},
+		},
+		"Changed": {
+			reason: "A changed endpoint should replace the recorded one.",
+			args: args{
+				endpoint: dynamic,
+				recorded: []v1beta1.ServiceEndpoint{{Service: "SNS", Region: "us-west-2", URL: "https://old.example.org"}},
+				service:  "SNS",
+			},
+			want: want{
+				endpoints: []v1beta1.ServiceEndpoint{snsEndpoint},
+				updated:   true,
+			},
+		},
+		"Conflict": {
+			reason: "Losing the status update to a conflicting write should not be an error.",
+			args: args{
+				endpoint: dynamic,
+				update:   kerrors.NewConflict(schema.GroupResource{}, "default", errBoom),
+				service:  "SNS",
+			},
+			want: want{
+				endpoints: []v1beta1.ServiceEndpoint{snsEndpoint},
+				updated:   true,
+			},
+		},
+		"UpdateFailed": {
+			reason: "Other errors updating the status should be returned.",
+			args: args{
+				endpoint: dynamic,
+				update:   errBoom,
+				service:  "SNS",
+			},
+			want: want{
+				err:       errors.Wrap(errBoom, errRecordEndpoint),
+				endpoints: []v1beta1.ServiceEndpoint{snsEndpoint},
+				updated:   true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc := &v1beta1.ProviderConfig{
+				Spec:   v1beta1.ProviderConfigSpec{Endpoint: tc.args.endpoint},
+				Status: v1beta1.ProviderConfigStatus{Endpoints: tc.args.recorded},
+			}
+			updated := false
+			kube := &test.MockClient{
+				MockStatusUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+					updated = true
+					return tc.args.update
+				},
+			}
+			cfg := SetResolver(pc, &aws.Config{})
+
+			err := RecordEndpoint(context.Background(), kube, pc, cfg, tc.args.service, "us-west-2")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRecordEndpoint(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.updated, updated); diff != "" {
+				t.Errorf("\n%s\nRecordEndpoint(...): -want updated, +got updated:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.endpoints, pc.Status.Endpoints); diff != "" {
+				t.Errorf("\n%s\nRecordEndpoint(...): -want endpoints, +got endpoints:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
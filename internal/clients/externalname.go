@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	errPersistExternalName = "cannot persist external name"
+	errPersistAnnotation   = "cannot persist annotation"
+)
+
+// PersistExternalName sets the external name of the supplied managed resource
+// and immediately persists it, so that an identifier returned by a create call
+// isn't lost to a conflicting write of the resource. Only the external name
+// annotation is patched, and the patch is retried against the latest version
+// of the resource on conflict.
+func PersistExternalName(ctx context.Context, kube client.Client, mg resource.Managed, name string) error {
+	return errors.Wrap(persistAnnotation(ctx, kube, mg, meta.AnnotationKeyExternalName, name), errPersistExternalName)
+}
+
+// PersistAnnotation sets the supplied annotation of the supplied managed
+// resource and immediately persists it in the same way as
+// PersistExternalName.
+func PersistAnnotation(ctx context.Context, kube client.Client, mg resource.Managed, key, value string) error {
+	return errors.Wrap(persistAnnotation(ctx, kube, mg, key, value), errPersistAnnotation)
+}
+
+func persistAnnotation(ctx context.Context, kube client.Client, mg resource.Managed, key, value string) error {
+	meta.AddAnnotations(mg, map[string]string{key: value})
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := mg.DeepCopyObject().(client.Object)
+		if err := kube.Get(ctx, types.NamespacedName{Name: mg.GetName()}, latest); err != nil {
+			return err
+		}
+		p := client.MergeFromWithOptions(latest.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+		meta.AddAnnotations(latest, map[string]string{key: value})
+		return kube.Patch(ctx, latest, p)
+	})
+}
@@ -0,0 +1,84 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	snsv1alpha1 "provider-aws-controlapi/apis/sns/v1alpha1"
+)
+
+func TestPersistExternalName(t *testing.T) {
+	const arn = "arn:aws:sns:us-west-2:123456789012:example"
+
+	errBoom := errors.New("boom")
+	errConflict := kerrors.NewConflict(schema.GroupResource{}, "example", errBoom)
+
+	type want struct {
+		err     error
+		patches int
+	}
+
+	cases := map[string]struct {
+		reason  string
+		patchFn func(patches int) error
+		want    want
+	}{
+		"Persisted": {
+			reason:  "The external name should be patched once when there is no conflict.",
+			patchFn: func(int) error { return nil },
+			want:    want{patches: 1},
+		},
+		"RetryOnConflict": {
+			reason: "The patch should be retried against the latest version on conflict.",
+			patchFn: func(patches int) error {
+				if patches == 1 {
+					return errConflict
+				}
+				return nil
+			},
+			want: want{patches: 2},
+		},
+		"PatchFailed": {
+			reason:  "Errors other than conflicts should not be retried.",
+			patchFn: func(int) error { return errBoom },
+			want:    want{err: errors.Wrap(errBoom, errPersistExternalName), patches: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			patches := 0
+			kube := &test.MockClient{
+				MockGet: test.NewMockGetFn(nil),
+				MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+					patches++
+					if got := meta.GetExternalName(obj); got != arn {
+						t.Errorf("\n%s\nPatch(...): want external name %q, got %q\n", tc.reason, arn, got)
+					}
+					return tc.patchFn(patches)
+				},
+			}
+			cr := &snsv1alpha1.Topic{}
+			cr.SetName("example")
+
+			err := PersistExternalName(context.Background(), kube, cr, arn)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPersistExternalName(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.patches, patches); diff != "" {
+				t.Errorf("\n%s\nPersistExternalName(...): -want patches, +got patches:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(arn, meta.GetExternalName(cr)); diff != "" {
+				t.Errorf("\n%s\nPersistExternalName(...): -want external name, +got external name:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
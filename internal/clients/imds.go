@@ -0,0 +1,34 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+
+	"provider-aws-controlapi/apis/v1beta1"
+)
+
+// IMDS endpoint modes.
+const (
+	IMDSEndpointModeIPv4 = "IPv4"
+	IMDSEndpointModeIPv6 = "IPv6"
+)
+
+// imdsOptions returns the options configuring how configs loaded for the
+// supplied ProviderConfig use the EC2 instance metadata service.
+func imdsOptions(pc *v1beta1.ProviderConfig) []func(*config.LoadOptions) error {
+	c := pc.Spec.IMDS
+	if c == nil {
+		return nil
+	}
+	var optFns []func(*config.LoadOptions) error
+	if c.Disabled {
+		optFns = append(optFns, config.WithEC2IMDSClientEnableState(imds.ClientDisabled))
+	}
+	switch StringValue(c.EndpointMode) {
+	case IMDSEndpointModeIPv4:
+		optFns = append(optFns, config.WithEC2IMDSEndpointMode(imds.EndpointModeStateIPv4))
+	case IMDSEndpointModeIPv6:
+		optFns = append(optFns, config.WithEC2IMDSEndpointMode(imds.EndpointModeStateIPv6))
+	}
+	return optFns
+}
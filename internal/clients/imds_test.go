@@ -0,0 +1,59 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+	"github.com/google/go-cmp/cmp"
+
+	"provider-aws-controlapi/apis/v1beta1"
+)
+
+func TestIMDSOptions(t *testing.T) {
+	type want struct {
+		enableState  imds.ClientEnableState
+		endpointMode imds.EndpointModeState
+	}
+
+	cases := map[string]struct {
+		reason string
+		imds   *v1beta1.IMDSConfig
+		want   want
+	}{
+		"NotConfigured": {
+			reason: "The instance metadata service should be used as the SDK defaults to if it's not configured.",
+		},
+		"Disabled": {
+			reason: "The instance metadata service should not be called if it's disabled.",
+			imds:   &v1beta1.IMDSConfig{Disabled: true},
+			want:   want{enableState: imds.ClientDisabled},
+		},
+		"IPv6": {
+			reason: "The IPv6 endpoint of the instance metadata service should be called if selected.",
+			imds:   &v1beta1.IMDSConfig{EndpointMode: aws.String(IMDSEndpointModeIPv6)},
+			want:   want{endpointMode: imds.EndpointModeStateIPv6},
+		},
+		"IPv4": {
+			reason: "The IPv4 endpoint of the instance metadata service should be called if selected.",
+			imds:   &v1beta1.IMDSConfig{EndpointMode: aws.String(IMDSEndpointModeIPv4)},
+			want:   want{endpointMode: imds.EndpointModeStateIPv4},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := config.LoadOptions{}
+			for _, fn := range imdsOptions(&v1beta1.ProviderConfig{Spec: v1beta1.ProviderConfigSpec{IMDS: tc.imds}}) {
+				if err := fn(&o); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := want{enableState: o.EC2IMDSClientEnableState, endpointMode: o.EC2IMDSEndpointMode}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nimdsOptions(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
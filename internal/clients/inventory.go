@@ -0,0 +1,98 @@
+package aws
+
+import (
+	"context"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const errListManaged = "cannot list %s managed resources"
+
+// managedResources counts the managed resources that exist, by kind, region
+// and the status of their Synced condition.
+var managedResources = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "controlapi_managed_resources",
+	Help: "Number of managed resources, by kind, region and whether they are synced.",
+}, []string{"kind", "region", "synced"})
+
+func init() {
+	metrics.Registry.MustRegister(managedResources)
+}
+
+// An Inventory periodically counts the managed resources of the supplied
+// kinds in the controlapi_managed_resources metric. It reads them from the
+// cache of the manager it's added to, so counting doesn't load the API
+// server.
+type Inventory struct {
+	client   client.Reader
+	log      logging.Logger
+	interval time.Duration
+
+	// kinds maps the kinds of managed resources to a function that returns
+	// an empty list of them.
+	kinds map[string]func() resource.ManagedList
+}
+
+// NewInventory returns an Inventory that counts the managed resources of the
+// supplied kinds every interval.
+func NewInventory(c client.Reader, l logging.Logger, interval time.Duration, kinds map[string]func() resource.ManagedList) *Inventory {
+	return &Inventory{client: c, log: l, interval: interval, kinds: kinds}
+}
+
+// Start counts the managed resources every interval until the supplied
+// context is done. Failures to count are logged and retried at the next
+// interval.
+func (i *Inventory) Start(ctx context.Context) error {
+	t := time.NewTicker(i.interval)
+	defer t.Stop()
+	for {
+		if err := i.Update(ctx); err != nil {
+			i.log.Info("Cannot count managed resources", "error", err.Error())
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// Update counts the managed resources once. Counts of kinds, regions and
+// sync states that no longer have any resources are dropped.
+func (i *Inventory) Update(ctx context.Context) error {
+	counts := map[[3]string]float64{}
+	for kind, newList := range i.kinds {
+		l := newList()
+		if err := i.client.List(ctx, l); err != nil {
+			return errors.Wrapf(err, errListManaged, kind)
+		}
+		for _, mg := range l.GetItems() {
+			counts[[3]string{kind, region(mg), string(mg.GetCondition(xpv1.TypeSynced).Status)}]++
+		}
+	}
+	managedResources.Reset()
+	for labels, n := range counts {
+		managedResources.WithLabelValues(labels[0], labels[1], labels[2]).Set(n)
+	}
+	return nil
+}
+
+// region returns the spec.forProvider.region of the supplied managed
+// resource, or an empty string if it has none.
+func region(mg resource.Managed) string {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(mg)
+	if err != nil {
+		return ""
+	}
+	r, _ := fieldpath.Pave(u).GetString("spec.forProvider.region")
+	return r
+}
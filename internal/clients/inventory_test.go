@@ -0,0 +1,84 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	snsv1alpha1 "provider-aws-controlapi/apis/sns/v1alpha1"
+)
+
+func TestInventoryUpdate(t *testing.T) {
+	topic := func(region string, c ...xpv1.Condition) snsv1alpha1.Topic {
+		t := snsv1alpha1.Topic{Spec: snsv1alpha1.TopicSpec{ForProvider: snsv1alpha1.TopicParameters{Region: region}}}
+		t.SetConditions(c...)
+		return t
+	}
+	subscription := func(region string, c ...xpv1.Condition) snsv1alpha1.Subscription {
+		s := snsv1alpha1.Subscription{Spec: snsv1alpha1.SubscriptionSpec{ForProvider: snsv1alpha1.SubscriptionParameters{Region: region}}}
+		s.SetConditions(c...)
+		return s
+	}
+	kube := &test.MockClient{MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		switch l := obj.(type) {
+		case *snsv1alpha1.TopicList:
+			l.Items = []snsv1alpha1.Topic{
+				topic("us-west-2", xpv1.ReconcileSuccess()),
+				topic("us-west-2", xpv1.ReconcileSuccess()),
+				topic("us-west-2", xpv1.ReconcileError(errors.New("boom"))),
+				topic("eu-west-1"),
+			}
+		case *snsv1alpha1.SubscriptionList:
+			l.Items = []snsv1alpha1.Subscription{subscription("us-west-2", xpv1.ReconcileSuccess())}
+		}
+		return nil
+	}}
+	i := NewInventory(kube, logging.NewNopLogger(), time.Minute, map[string]func() resource.ManagedList{
+		snsv1alpha1.TopicKind:        func() resource.ManagedList { return &snsv1alpha1.TopicList{} },
+		snsv1alpha1.SubscriptionKind: func() resource.ManagedList { return &snsv1alpha1.SubscriptionList{} },
+	})
+
+	// A count that no longer has resources should be dropped.
+	managedResources.WithLabelValues(snsv1alpha1.TopicKind, "us-east-1", "True").Set(3)
+
+	if err := i.Update(context.Background()); err != nil {
+		t.Fatalf("Update(...): %v", err)
+	}
+
+	want := map[[3]string]float64{
+		{snsv1alpha1.TopicKind, "us-west-2", "True"}:        2,
+		{snsv1alpha1.TopicKind, "us-west-2", "False"}:       1,
+		{snsv1alpha1.TopicKind, "eu-west-1", "Unknown"}:     1,
+		{snsv1alpha1.SubscriptionKind, "us-west-2", "True"}: 1,
+	}
+	got := map[[3]string]float64{}
+	for labels := range want {
+		got[labels] = testutil.ToFloat64(managedResources.WithLabelValues(labels[0], labels[1], labels[2]))
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nThe managed resources should be counted by kind, region and sync state.\nUpdate(...): -want, +got:\n%s\n", diff)
+	}
+	if n := testutil.CollectAndCount(managedResources); n != len(want) {
+		t.Errorf("\nCounts without resources should be dropped.\nUpdate(...): want %d series, got %d\n", len(want), n)
+	}
+}
+
+func TestInventoryUpdateListFailed(t *testing.T) {
+	errBoom := errors.New("boom")
+	i := NewInventory(&test.MockClient{MockList: test.NewMockListFn(errBoom)}, logging.NewNopLogger(), time.Minute, map[string]func() resource.ManagedList{
+		snsv1alpha1.TopicKind: func() resource.ManagedList { return &snsv1alpha1.TopicList{} },
+	})
+	want := errors.Wrapf(errBoom, errListManaged, snsv1alpha1.TopicKind)
+	if diff := cmp.Diff(want, i.Update(context.Background()), test.EquateErrors()); diff != "" {
+		t.Errorf("\nErrors listing managed resources should be returned.\nUpdate(...): -want, +got:\n%s\n", diff)
+	}
+}
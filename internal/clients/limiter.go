@@ -0,0 +1,85 @@
+package aws
+
+import (
+	"context"
+	"sync"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// defaultProviderConfigName is the ProviderConfig managed resources use if
+// they don't reference one.
+const defaultProviderConfigName = "default"
+
+// ProviderConfigName returns the name of the ProviderConfig the supplied
+// managed resource uses.
+func ProviderConfigName(mg resource.Managed) string {
+	if ref := mg.GetProviderConfigReference(); ref != nil {
+		return ref.Name
+	}
+	return defaultProviderConfigName
+}
+
+// A ProviderConfigLimiter bounds the number of concurrent reconciles of
+// managed resources that use the same ProviderConfig, and therefore the same
+// AWS account, regardless of their kind. This keeps the resources of one
+// account from collectively exceeding its AWS API quota.
+type ProviderConfigLimiter struct {
+	max int
+
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+// NewProviderConfigLimiter returns a limiter that allows up to max concurrent
+// reconciles per ProviderConfig. A max of zero or less allows any number.
+func NewProviderConfigLimiter(max int) *ProviderConfigLimiter {
+	return &ProviderConfigLimiter{max: max, slots: map[string]chan struct{}{}}
+}
+
+// Acquire blocks until a reconcile of a managed resource that uses the
+// supplied ProviderConfig may start, or the supplied context is done. The
+// returned function must be called once the reconcile is done.
+func (l *ProviderConfigLimiter) Acquire(ctx context.Context, providerConfig string) (func(), error) {
+	if l == nil || l.max <= 0 {
+		return func() {}, nil
+	}
+	l.mu.Lock()
+	s, ok := l.slots[providerConfig]
+	if !ok {
+		s = make(chan struct{}, l.max)
+		l.slots[providerConfig] = s
+	}
+	l.mu.Unlock()
+
+	select {
+	case s <- struct{}{}:
+		return func() { <-s }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Limit wraps the supplied reconciler of the managed resources newManaged
+// returns so that its reconciles are bounded by the limiter. Managed
+// resources that can't be read are reconciled without a bound, so that the
+// wrapped reconciler handles them as usual.
+func (l *ProviderConfigLimiter) Limit(r reconcile.Reconciler, c client.Reader, newManaged func() resource.Managed) reconcile.Reconciler {
+	if l == nil || l.max <= 0 {
+		return r
+	}
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		mg := newManaged()
+		if err := c.Get(ctx, req.NamespacedName, mg); err != nil {
+			return r.Reconcile(ctx, req)
+		}
+		release, err := l.Acquire(ctx, ProviderConfigName(mg))
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		defer release()
+		return r.Reconcile(ctx, req)
+	})
+}
@@ -0,0 +1,113 @@
+package aws
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestProviderConfigLimiter(t *testing.T) {
+	// Resources named after the ProviderConfig they use.
+	kube := &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		obj.(resource.Managed).SetProviderConfigReference(&xpv1.Reference{Name: key.Name})
+		return nil
+	}}
+	newManaged := func() resource.Managed { return &fake.Managed{} }
+
+	type want struct {
+		maxInFlight map[string]int
+	}
+
+	cases := map[string]struct {
+		reason string
+		max    int
+		reqs   []string
+		want   want
+	}{
+		"SameProviderConfig": {
+			reason: "Reconciles of resources that use the same ProviderConfig should be bounded by the configured concurrency.",
+			max:    2,
+			reqs:   []string{"a", "a", "a", "a", "a", "a"},
+			want:   want{maxInFlight: map[string]int{"a": 2}},
+		},
+		"Serialized": {
+			reason: "Reconciles of resources that use the same ProviderConfig should be serialized with a concurrency of one.",
+			max:    1,
+			reqs:   []string{"a", "a", "a", "b", "b", "b"},
+			want:   want{maxInFlight: map[string]int{"a": 1, "b": 1}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			inFlight := map[string]int{}
+			got := want{maxInFlight: map[string]int{}}
+
+			r := reconcile.Func(func(_ context.Context, req reconcile.Request) (reconcile.Result, error) {
+				mu.Lock()
+				inFlight[req.Name]++
+				if inFlight[req.Name] > got.maxInFlight[req.Name] {
+					got.maxInFlight[req.Name] = inFlight[req.Name]
+				}
+				mu.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mu.Lock()
+				inFlight[req.Name]--
+				mu.Unlock()
+				return reconcile.Result{}, nil
+			})
+			limited := NewProviderConfigLimiter(tc.max).Limit(r, kube, newManaged)
+
+			var wg sync.WaitGroup
+			for _, pc := range tc.reqs {
+				wg.Add(1)
+				go func(pc string) {
+					defer wg.Done()
+					if _, err := limited.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: pc}}); err != nil {
+						t.Errorf("Reconcile(...): %v", err)
+					}
+				}(pc)
+			}
+			wg.Wait()
+
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nLimit(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestProviderConfigLimiterAcquire(t *testing.T) {
+	l := NewProviderConfigLimiter(1)
+	release, err := l.Acquire(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("Acquire(...): %v", err)
+	}
+	defer release()
+
+	// Other ProviderConfigs aren't held back.
+	releaseB, err := l.Acquire(context.Background(), "b")
+	if err != nil {
+		t.Fatalf("Acquire(...): another ProviderConfig should not be held back: %v", err)
+	}
+	releaseB()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(ctx, "a"); !cmp.Equal(context.DeadlineExceeded, err, test.EquateErrors()) {
+		t.Errorf("Acquire(...): want %v while the ProviderConfig is at its concurrency, got %v", context.DeadlineExceeded, err)
+	}
+}
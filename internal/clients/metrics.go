@@ -0,0 +1,84 @@
+package aws
+
+import (
+	"context"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// errorsTotal counts the errors returned by AWS APIs. Requests are counted
+// once their retries are exhausted, so a request that eventually succeeds
+// isn't counted.
+var errorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "controlapi_aws_error_total",
+	Help: "Number of errors returned by AWS APIs, by service, operation and error code.",
+}, []string{"service", "operation", "code"})
+
+// Results of AWS requests.
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+// requestsTotal counts the requests made to AWS APIs, including their
+// retries.
+var requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "controlapi_aws_requests_total",
+	Help: "Number of requests made to AWS APIs, by service, operation and result.",
+}, []string{"service", "operation", "result"})
+
+// requestDuration observes how long requests to AWS APIs take, including
+// their retries.
+var requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "controlapi_aws_request_duration_seconds",
+	Help:    "Duration of requests made to AWS APIs, by service and operation.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"service", "operation"})
+
+func init() {
+	metrics.Registry.MustRegister(errorsTotal, requestsTotal, requestDuration)
+}
+
+// WithRequestMetrics adds a middleware to the supplied stack that counts the
+// requests made to AWS in the controlapi_aws_requests_total metric and
+// observes their duration in the controlapi_aws_request_duration_seconds
+// metric, labelled with the service and operation of the request.
+func WithRequestMetrics(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("RequestMetrics",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			start := time.Now()
+			out, md, err := next.HandleInitialize(ctx, in)
+			service, operation := awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx)
+			result := resultSuccess
+			if err != nil {
+				result = resultError
+			}
+			requestsTotal.WithLabelValues(service, operation, result).Inc()
+			requestDuration.WithLabelValues(service, operation).Observe(time.Since(start).Seconds())
+			return out, md, err
+		}), middleware.After)
+}
+
+// WithErrorMetrics adds a middleware to the supplied stack that counts the
+// errors returned by AWS in the controlapi_aws_error_total metric, labelled
+// with the service, operation and error code of the request.
+func WithErrorMetrics(stack *middleware.Stack) error {
+	// The middleware is added after the service metadata of the operation is
+	// registered, so that the service and operation can be read from the
+	// context.
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("ErrorMetrics",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			out, md, err := next.HandleInitialize(ctx, in)
+			var apiErr smithy.APIError
+			if errors.As(err, &apiErr) {
+				errorsTotal.WithLabelValues(awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx), apiErr.ErrorCode()).Inc()
+			}
+			return out, md, err
+		}), middleware.After)
+}
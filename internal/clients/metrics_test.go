@@ -0,0 +1,127 @@
+package aws
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// errorResponder responds to every request with the supplied SNS error.
+type errorResponder struct {
+	status int
+	code   string
+}
+
+func (r errorResponder) Do(req *http.Request) (*http.Response, error) {
+	body := `<ErrorResponse><Error><Type>Sender</Type><Code>` + r.code + `</Code><Message>boom</Message></Error><RequestId>example</RequestId></ErrorResponse>`
+	return &http.Response{
+		StatusCode: r.status,
+		Header:     http.Header{"Content-Type": []string{"text/xml"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestWithErrorMetrics(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		status int
+		code   string
+	}{
+		"NotFound": {
+			reason: "A NotFound error should be counted with its code.",
+			status: http.StatusNotFound,
+			code:   "NotFound",
+		},
+		"AuthorizationError": {
+			reason: "An AuthorizationError should be counted with its code.",
+			status: http.StatusForbidden,
+			code:   "AuthorizationError",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := aws.Config{
+				Region:      "us-east-1",
+				Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+				HTTPClient:  errorResponder{status: tc.status, code: tc.code},
+				Retryer:     func() aws.Retryer { return aws.NopRetryer{} },
+				APIOptions:  []func(*middleware.Stack) error{WithErrorMetrics},
+			}
+			counter := errorsTotal.WithLabelValues("SNS", "GetTopicAttributes", tc.code)
+			before := testutil.ToFloat64(counter)
+
+			_, err := sns.NewFromConfig(cfg).GetTopicAttributes(context.Background(), &sns.GetTopicAttributesInput{TopicArn: aws.String("arn:aws:sns:us-east-1:123456789012:example")})
+			if err == nil {
+				t.Fatalf("\n%s\nGetTopicAttributes(...): expected an error", tc.reason)
+			}
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("\n%s\ncontrolapi_aws_error_total: want 1 increment, got %v", tc.reason, got)
+			}
+		})
+	}
+}
+
+// successResponder responds to every request with an empty SNS response.
+type successResponder struct{}
+
+func (successResponder) Do(req *http.Request) (*http.Response, error) {
+	body := `<GetTopicAttributesResponse><GetTopicAttributesResult><Attributes></Attributes></GetTopicAttributesResult><ResponseMetadata><RequestId>example</RequestId></ResponseMetadata></GetTopicAttributesResponse>`
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/xml"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestWithRequestMetrics(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		client aws.HTTPClient
+		result string
+	}{
+		"Success": {
+			reason: "A successful request should be counted as a success.",
+			client: successResponder{},
+			result: resultSuccess,
+		},
+		"Error": {
+			reason: "A failed request should be counted as an error.",
+			client: errorResponder{status: http.StatusNotFound, code: "NotFound"},
+			result: resultError,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := aws.Config{
+				Region:      "us-east-1",
+				Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+				HTTPClient:  tc.client,
+				Retryer:     func() aws.Retryer { return aws.NopRetryer{} },
+				APIOptions:  []func(*middleware.Stack) error{WithRequestMetrics},
+			}
+			counter := requestsTotal.WithLabelValues("SNS", "GetTopicAttributes", tc.result)
+			before := testutil.ToFloat64(counter)
+
+			_, _ = sns.NewFromConfig(cfg).GetTopicAttributes(context.Background(), &sns.GetTopicAttributesInput{TopicArn: aws.String("arn:aws:sns:us-east-1:123456789012:example")})
+
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("\n%s\ncontrolapi_aws_requests_total: want 1 increment, got %v", tc.reason, got)
+			}
+			if got := testutil.CollectAndCount(requestDuration, "controlapi_aws_request_duration_seconds"); got < 1 {
+				t.Errorf("\n%s\ncontrolapi_aws_request_duration_seconds: want the request to be observed, got %d series", tc.reason, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// ObserveOnly wraps the supplied ExternalClient so that it only observes the
+// external resource. Create, Update and Delete log what they would have done
+// instead of doing it. A deleted managed resource is reported as not existing
+// so that it's removed without deleting the external resource.
+func ObserveOnly(e managed.ExternalClient, l logging.Logger) managed.ExternalClient {
+	return &observeOnlyClient{ExternalClient: e, log: l}
+}
+
+type observeOnlyClient struct {
+	managed.ExternalClient
+	log logging.Logger
+}
+
+func (c *observeOnlyClient) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	if meta.WasDeleted(mg) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	return c.ExternalClient.Observe(ctx, mg)
+}
+
+func (c *observeOnlyClient) Create(_ context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	c.log.Info("Observe only, skipping create", "name", mg.GetName())
+	return managed.ExternalCreation{}, nil
+}
+
+func (c *observeOnlyClient) Update(_ context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	c.log.Info("Observe only, skipping update", "name", mg.GetName(), "external-name", meta.GetExternalName(mg))
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *observeOnlyClient) Delete(_ context.Context, mg resource.Managed) error {
+	c.log.Info("Observe only, skipping delete", "name", mg.GetName(), "external-name", meta.GetExternalName(mg))
+	return nil
+}
@@ -0,0 +1,68 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestObserveOnly(t *testing.T) {
+	calls := []string{}
+	e := ObserveOnly(&managed.ExternalClientFns{
+		ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+			calls = append(calls, "Observe")
+			return managed.ExternalObservation{ResourceExists: true}, nil
+		},
+		CreateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+			calls = append(calls, "Create")
+			return managed.ExternalCreation{}, nil
+		},
+		UpdateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+			calls = append(calls, "Update")
+			return managed.ExternalUpdate{}, nil
+		},
+		DeleteFn: func(_ context.Context, _ resource.Managed) error {
+			calls = append(calls, "Delete")
+			return nil
+		},
+	}, logging.NewNopLogger())
+
+	mg := &fake.Managed{}
+	ctx := context.Background()
+	obs, err := e.Observe(ctx, mg)
+	if err != nil {
+		t.Fatalf("Observe(...): %v", err)
+	}
+	if !obs.ResourceExists {
+		t.Errorf("Observe(...): the observation of the wrapped client should be returned")
+	}
+	if _, err := e.Create(ctx, mg); err != nil {
+		t.Errorf("Create(...): %v", err)
+	}
+	if _, err := e.Update(ctx, mg); err != nil {
+		t.Errorf("Update(...): %v", err)
+	}
+	if err := e.Delete(ctx, mg); err != nil {
+		t.Errorf("Delete(...): %v", err)
+	}
+
+	now := metav1.Now()
+	mg.SetDeletionTimestamp(&now)
+	obs, err = e.Observe(ctx, mg)
+	if err != nil {
+		t.Fatalf("Observe(...): %v", err)
+	}
+	if obs.ResourceExists {
+		t.Errorf("Observe(...): a deleted managed resource should be reported as not existing")
+	}
+
+	if diff := cmp.Diff([]string{"Observe"}, calls); diff != "" {
+		t.Errorf("ObserveOnly(...): only Observe should be passed to the wrapped client: -want calls, +got calls:\n%s\n", diff)
+	}
+}
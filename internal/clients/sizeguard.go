@@ -0,0 +1,27 @@
+package aws
+
+import (
+	"encoding/json"
+)
+
+// DefaultMaxObjectSize is the default size, in bytes, a managed resource may
+// grow to before optional status fields are dropped. etcd rejects requests
+// larger than 1.5MiB by default, so this leaves headroom for the rest of the
+// request.
+const DefaultMaxObjectSize = 1 << 20
+
+// ObjectSize returns the size in bytes of the JSON serialization of the
+// supplied object, which approximates the size it's stored with.
+func ObjectSize(o interface{}) int {
+	b, err := json.Marshal(o)
+	if err != nil {
+		return 0
+	}
+	return len(b)
+}
+
+// ExceedsObjectSize returns true if the supplied object is larger than max
+// bytes. A max of zero or less disables the check.
+func ExceedsObjectSize(o interface{}, max int) bool {
+	return max > 0 && ObjectSize(o) > max
+}
@@ -0,0 +1,96 @@
+package fake
+
+import (
+	"context"
+
+	awssns "github.com/aws/aws-sdk-go-v2/service/sns"
+
+	"provider-aws-controlapi/internal/clients/sns"
+)
+
+// this ensures that the mock implements the client interface
+var _ sns.Client = (*MockClient)(nil)
+
+// MockClient is a type that implements all the methods for Client interface
+type MockClient struct {
+	MockCreateTopic              func(ctx context.Context, input *awssns.CreateTopicInput, opts []func(*awssns.Options)) (*awssns.CreateTopicOutput, error)
+	MockDeleteTopic              func(ctx context.Context, input *awssns.DeleteTopicInput, opts []func(*awssns.Options)) (*awssns.DeleteTopicOutput, error)
+	MockGetTopicAttributes       func(ctx context.Context, input *awssns.GetTopicAttributesInput, opts []func(*awssns.Options)) (*awssns.GetTopicAttributesOutput, error)
+	MockSetTopicAttributes       func(ctx context.Context, input *awssns.SetTopicAttributesInput, opts []func(*awssns.Options)) (*awssns.SetTopicAttributesOutput, error)
+	MockTagResource              func(ctx context.Context, input *awssns.TagResourceInput, opts []func(*awssns.Options)) (*awssns.TagResourceOutput, error)
+	MockUntagResource            func(ctx context.Context, input *awssns.UntagResourceInput, opts []func(*awssns.Options)) (*awssns.UntagResourceOutput, error)
+	MockListTagsForResource      func(ctx context.Context, input *awssns.ListTagsForResourceInput, opts []func(*awssns.Options)) (*awssns.ListTagsForResourceOutput, error)
+	MockListSubscriptionsByTopic func(ctx context.Context, input *awssns.ListSubscriptionsByTopicInput, opts []func(*awssns.Options)) (*awssns.ListSubscriptionsByTopicOutput, error)
+}
+
+// CreateTopic mocks CreateTopic method
+func (m *MockClient) CreateTopic(ctx context.Context, input *awssns.CreateTopicInput, opts ...func(*awssns.Options)) (*awssns.CreateTopicOutput, error) {
+	return m.MockCreateTopic(ctx, input, opts)
+}
+
+// DeleteTopic mocks DeleteTopic method
+func (m *MockClient) DeleteTopic(ctx context.Context, input *awssns.DeleteTopicInput, opts ...func(*awssns.Options)) (*awssns.DeleteTopicOutput, error) {
+	return m.MockDeleteTopic(ctx, input, opts)
+}
+
+// GetTopicAttributes mocks GetTopicAttributes method
+func (m *MockClient) GetTopicAttributes(ctx context.Context, input *awssns.GetTopicAttributesInput, opts ...func(*awssns.Options)) (*awssns.GetTopicAttributesOutput, error) {
+	return m.MockGetTopicAttributes(ctx, input, opts)
+}
+
+// SetTopicAttributes mocks SetTopicAttributes method
+func (m *MockClient) SetTopicAttributes(ctx context.Context, input *awssns.SetTopicAttributesInput, opts ...func(*awssns.Options)) (*awssns.SetTopicAttributesOutput, error) {
+	return m.MockSetTopicAttributes(ctx, input, opts)
+}
+
+// TagResource mocks TagResource method
+func (m *MockClient) TagResource(ctx context.Context, input *awssns.TagResourceInput, opts ...func(*awssns.Options)) (*awssns.TagResourceOutput, error) {
+	return m.MockTagResource(ctx, input, opts)
+}
+
+// UntagResource mocks UntagResource method
+func (m *MockClient) UntagResource(ctx context.Context, input *awssns.UntagResourceInput, opts ...func(*awssns.Options)) (*awssns.UntagResourceOutput, error) {
+	return m.MockUntagResource(ctx, input, opts)
+}
+
+// ListTagsForResource mocks ListTagsForResource method
+func (m *MockClient) ListTagsForResource(ctx context.Context, input *awssns.ListTagsForResourceInput, opts ...func(*awssns.Options)) (*awssns.ListTagsForResourceOutput, error) {
+	return m.MockListTagsForResource(ctx, input, opts)
+}
+
+// ListSubscriptionsByTopic mocks ListSubscriptionsByTopic method
+func (m *MockClient) ListSubscriptionsByTopic(ctx context.Context, input *awssns.ListSubscriptionsByTopicInput, opts ...func(*awssns.Options)) (*awssns.ListSubscriptionsByTopicOutput, error) {
+	return m.MockListSubscriptionsByTopic(ctx, input, opts)
+}
+
+// this ensures that the mock implements the subscription client interface
+var _ sns.SubscriptionClient = (*MockSubscriptionClient)(nil)
+
+// MockSubscriptionClient is a type that implements all the methods for
+// SubscriptionClient interface
+type MockSubscriptionClient struct {
+	MockSubscribe                 func(ctx context.Context, input *awssns.SubscribeInput, opts []func(*awssns.Options)) (*awssns.SubscribeOutput, error)
+	MockUnsubscribe               func(ctx context.Context, input *awssns.UnsubscribeInput, opts []func(*awssns.Options)) (*awssns.UnsubscribeOutput, error)
+	MockGetSubscriptionAttributes func(ctx context.Context, input *awssns.GetSubscriptionAttributesInput, opts []func(*awssns.Options)) (*awssns.GetSubscriptionAttributesOutput, error)
+	MockSetSubscriptionAttributes func(ctx context.Context, input *awssns.SetSubscriptionAttributesInput, opts []func(*awssns.Options)) (*awssns.SetSubscriptionAttributesOutput, error)
+}
+
+// Subscribe mocks Subscribe method
+func (m *MockSubscriptionClient) Subscribe(ctx context.Context, input *awssns.SubscribeInput, opts ...func(*awssns.Options)) (*awssns.SubscribeOutput, error) {
+	return m.MockSubscribe(ctx, input, opts)
+}
+
+// Unsubscribe mocks Unsubscribe method
+func (m *MockSubscriptionClient) Unsubscribe(ctx context.Context, input *awssns.UnsubscribeInput, opts ...func(*awssns.Options)) (*awssns.UnsubscribeOutput, error) {
+	return m.MockUnsubscribe(ctx, input, opts)
+}
+
+// GetSubscriptionAttributes mocks GetSubscriptionAttributes method
+func (m *MockSubscriptionClient) GetSubscriptionAttributes(ctx context.Context, input *awssns.GetSubscriptionAttributesInput, opts ...func(*awssns.Options)) (*awssns.GetSubscriptionAttributesOutput, error) {
+	return m.MockGetSubscriptionAttributes(ctx, input, opts)
+}
+
+// SetSubscriptionAttributes mocks SetSubscriptionAttributes method
+func (m *MockSubscriptionClient) SetSubscriptionAttributes(ctx context.Context, input *awssns.SetSubscriptionAttributesInput, opts ...func(*awssns.Options)) (*awssns.SetSubscriptionAttributesOutput, error) {
+	return m.MockSetSubscriptionAttributes(ctx, input, opts)
+}
@@ -0,0 +1,18 @@
+package sns
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"provider-aws-controlapi/apis/sns/v1alpha1"
+)
+
+// CheckMaintenanceWindow returns an error if the supplied time is within the
+// supplied maintenance window, during which a resource may only be observed.
+func CheckMaintenanceWindow(w *v1alpha1.MaintenanceWindow, now time.Time) error {
+	if !w.Contains(now) {
+		return nil
+	}
+	return errors.Errorf("in the maintenance window starting at %s UTC for %s, only observing", w.Start, w.Duration.Duration)
+}
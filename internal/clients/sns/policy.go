@@ -0,0 +1,90 @@
+package sns
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+const (
+	policyVersion   = "Version"
+	policyStatement = "Statement"
+)
+
+// policyEqual returns true if the supplied policy documents are semantically
+// equal. AWS canonicalizes the policies it stores: it reorders keys and
+// statements, collapses single element lists and adds a Version if none was
+// given. The Version is therefore only compared when the desired policy sets
+// it. Strings that aren't valid JSON are compared as is.
+func policyEqual(desired, observed string) bool {
+	if desired == observed {
+		return true
+	}
+	var d, o interface{}
+	if json.Unmarshal([]byte(desired), &d) != nil || json.Unmarshal([]byte(observed), &o) != nil {
+		return false
+	}
+	if dm, ok := d.(map[string]interface{}); ok {
+		if om, ok := o.(map[string]interface{}); ok {
+			if _, ok := dm[policyVersion]; !ok {
+				delete(om, policyVersion)
+			}
+		}
+	}
+	return reflect.DeepEqual(normalizePolicy(d), normalizePolicy(o))
+}
+
+// normalizePolicy returns the canonical form of a policy document element.
+// Lists are sorted, since the order of statements, actions, principals and
+// resources has no meaning, and single element lists are collapsed into their
+// element.
+func normalizePolicy(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, e := range val {
+			out[k] = normalizePolicy(e)
+		}
+		// A single statement may be given without the enclosing list.
+		if s, ok := out[policyStatement]; ok {
+			if _, ok := s.([]interface{}); !ok {
+				out[policyStatement] = []interface{}{s}
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, e := range val {
+			out[i] = normalizePolicy(e)
+		}
+		if len(out) == 1 {
+			if _, ok := out[0].(map[string]interface{}); !ok {
+				return out[0]
+			}
+		}
+		// Elements are sorted by their serialization, which json.Marshal
+		// makes deterministic by sorting map keys.
+		keys := make([]string, len(out))
+		for i, e := range out {
+			b, _ := json.Marshal(e)
+			keys[i] = string(b)
+		}
+		sort.Sort(byKey{keys: keys, values: out})
+		return out
+	default:
+		return val
+	}
+}
+
+// byKey sorts values by their corresponding keys.
+type byKey struct {
+	keys   []string
+	values []interface{}
+}
+
+func (b byKey) Len() int           { return len(b.keys) }
+func (b byKey) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKey) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.values[i], b.values[j] = b.values[j], b.values[i]
+}
@@ -0,0 +1,91 @@
+package sns
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	policy = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Sid": "Publish",
+      "Effect": "Allow",
+      "Principal": {"Service": "events.amazonaws.com"},
+      "Action": ["sns:Publish"],
+      "Resource": "arn:aws:sns:us-west-2:123456789012:example"
+    },
+    {
+      "Sid": "Subscribe",
+      "Effect": "Allow",
+      "Principal": {"AWS": ["arn:aws:iam::123456789012:root", "arn:aws:iam::210987654321:root"]},
+      "Action": ["sns:Subscribe", "sns:Receive"],
+      "Resource": "arn:aws:sns:us-west-2:123456789012:example"
+    }
+  ]
+}`
+	reorderedPolicy = `{"Statement":[{"Action":["sns:Receive","sns:Subscribe"],"Effect":"Allow","Principal":{"AWS":["arn:aws:iam::210987654321:root","arn:aws:iam::123456789012:root"]},"Resource":"arn:aws:sns:us-west-2:123456789012:example","Sid":"Subscribe"},{"Action":"sns:Publish","Effect":"Allow","Principal":{"Service":"events.amazonaws.com"},"Resource":"arn:aws:sns:us-west-2:123456789012:example","Sid":"Publish"}],"Version":"2012-10-17"}`
+)
+
+func TestPolicyEqual(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		desired  string
+		observed string
+		want     bool
+	}{
+		"Identical": {
+			reason:   "Identical policies should be equal.",
+			desired:  policy,
+			observed: policy,
+			want:     true,
+		},
+		"Reordered": {
+			reason:   "Policies with reordered keys, statements and values should be equal.",
+			desired:  policy,
+			observed: reorderedPolicy,
+			want:     true,
+		},
+		"Whitespace": {
+			reason:   "Policies that only differ in whitespace should be equal.",
+			desired:  `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "sns:Publish"}}`,
+			observed: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"sns:Publish"}]}`,
+			want:     true,
+		},
+		"VersionAdded": {
+			reason:   "A Version added by AWS should be ignored if the desired policy doesn't set one.",
+			desired:  `{"Statement":[{"Effect":"Allow","Action":"sns:Publish"}]}`,
+			observed: `{"Version":"2008-10-17","Statement":[{"Effect":"Allow","Action":"sns:Publish"}]}`,
+			want:     true,
+		},
+		"VersionChanged": {
+			reason:   "A Version set by the desired policy should be compared.",
+			desired:  `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"sns:Publish"}]}`,
+			observed: `{"Version":"2008-10-17","Statement":[{"Effect":"Allow","Action":"sns:Publish"}]}`,
+			want:     false,
+		},
+		"StatementChanged": {
+			reason:   "Policies with different statements should not be equal.",
+			desired:  policy,
+			observed: `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"sns:Publish"}]}`,
+			want:     false,
+		},
+		"NotJSON": {
+			reason:   "Strings that aren't JSON should only be equal if they're identical.",
+			desired:  "not a policy",
+			observed: "",
+			want:     false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := policyEqual(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\npolicyEqual(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package sns
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	awsclient "provider-aws-controlapi/internal/clients"
+)
+
+// A NameResolver resolves the name of a topic to its ARN. SNS has no API to
+// look a topic up by name, so the ARN is derived from the account the topic
+// is in.
+type NameResolver interface {
+	ResolveTopicARN(ctx context.Context, region, name string) (string, error)
+}
+
+// A NameResolverFn is a function that satisfies the NameResolver interface.
+type NameResolverFn func(ctx context.Context, region, name string) (string, error)
+
+// ResolveTopicARN resolves the name of a topic to its ARN.
+func (fn NameResolverFn) ResolveTopicARN(ctx context.Context, region, name string) (string, error) {
+	return fn(ctx, region, name)
+}
+
+// NewSTSNameResolver returns a NameResolver that derives the ARN of a topic
+// from the account requests made with the supplied config act in, as told by
+// STS. The account is only looked up once per config.
+func NewSTSNameResolver(cfg *aws.Config) NameResolver {
+	return NameResolverFn(func(ctx context.Context, region, name string) (string, error) {
+		id, err := awsclient.GetAccountID(ctx, cfg)
+		if err != nil {
+			return "", err
+		}
+		return TopicARN(region, id, name), nil
+	})
+}
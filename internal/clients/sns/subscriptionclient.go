@@ -0,0 +1,228 @@
+package sns
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssns "github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/pkg/errors"
+
+	"provider-aws-controlapi/apis/sns/v1alpha1"
+	awsclient "provider-aws-controlapi/internal/clients"
+)
+
+const (
+	errFilterPolicyNotJSON   = "filter policy is not valid JSON"
+	errFilterPolicyNotObject = "filter policy must be a JSON object"
+	errFilterPolicyEmpty     = "filter policy must define at least one attribute"
+)
+
+// SubscriptionClient is the subset of the SNS API used to manage
+// subscriptions.
+type SubscriptionClient interface {
+	Subscribe(ctx context.Context, params *awssns.SubscribeInput, optFns ...func(*awssns.Options)) (*awssns.SubscribeOutput, error)
+	Unsubscribe(ctx context.Context, params *awssns.UnsubscribeInput, optFns ...func(*awssns.Options)) (*awssns.UnsubscribeOutput, error)
+	GetSubscriptionAttributes(ctx context.Context, params *awssns.GetSubscriptionAttributesInput, optFns ...func(*awssns.Options)) (*awssns.GetSubscriptionAttributesOutput, error)
+	SetSubscriptionAttributes(ctx context.Context, params *awssns.SetSubscriptionAttributesInput, optFns ...func(*awssns.Options)) (*awssns.SetSubscriptionAttributesOutput, error)
+}
+
+// GetSubscriptionClient returns the aws client for calling AWS SNS
+// subscription Apis
+func GetSubscriptionClient(cfg aws.Config) SubscriptionClient {
+	return awssns.NewFromConfig(cfg)
+}
+
+// IsSubscriptionPending returns true if the supplied SubscriptionArn is the
+// placeholder SNS returns for subscriptions awaiting confirmation.
+func IsSubscriptionPending(arn string) bool {
+	return strings.EqualFold(arn, v1alpha1.SubscriptionPendingConfirmationArn)
+}
+
+// GenerateSubscriptionAttributeMap returns a map of the subscription
+// attributes set in the supplied parameters.
+func GenerateSubscriptionAttributeMap(in v1alpha1.SubscriptionParameters) map[string]string {
+	attributes := make(map[string]string)
+	if in.RawMessageDelivery != nil {
+		attributes[v1alpha1.SubscriptionRawMessageDelivery] = strconv.FormatBool(aws.ToBool(in.RawMessageDelivery))
+	}
+	if in.FilterPolicy != nil {
+		attributes[v1alpha1.SubscriptionFilterPolicy] = aws.ToString(in.FilterPolicy)
+	}
+	if len(attributes) == 0 {
+		return nil
+	}
+	return attributes
+}
+
+// LateInitializeSubscription fills the empty fields in
+// *v1alpha1.SubscriptionParameters with the values returned by
+// GetSubscriptionAttributes
+func LateInitializeSubscription(in *v1alpha1.SubscriptionParameters, attributes map[string]string) {
+	in.RawMessageDelivery = awsclient.LateInitializeBoolPtr(in.RawMessageDelivery, awsclient.StrToBoolPtr(attributes[v1alpha1.SubscriptionRawMessageDelivery]))
+	if in.FilterPolicy == nil && attributes[v1alpha1.SubscriptionFilterPolicy] != "" {
+		in.FilterPolicy = aws.String(attributes[v1alpha1.SubscriptionFilterPolicy])
+	}
+}
+
+// GenerateSubscriptionObservation generates the observation for the
+// Subscription object based on the attributes received from AWS
+func GenerateSubscriptionObservation(attributes map[string]string) v1alpha1.SubscriptionObservation {
+	return v1alpha1.SubscriptionObservation{
+		SubscriptionArn:     aws.String(attributes[v1alpha1.SubscriptionArn]),
+		Owner:               aws.String(attributes[v1alpha1.SubscriptionOwner]),
+		PendingConfirmation: awsclient.StrToBoolPtr(attributes[v1alpha1.SubscriptionPendingConfirmation]),
+
+		ConfirmationWasAuthenticated: awsclient.StrToBoolPtr(attributes[v1alpha1.SubscriptionConfirmationWasAuthenticated]),
+	}
+}
+
+// GetSubscriptionAttributeDiff returns the map of Subscription attributes
+// which are not synced with external resource
+func GetSubscriptionAttributeDiff(in v1alpha1.SubscriptionParameters, attributes map[string]string) map[string]string {
+	out := make(map[string]string)
+	if aws.ToBool(in.RawMessageDelivery) != aws.ToBool(awsclient.StrToBoolPtr(attributes[v1alpha1.SubscriptionRawMessageDelivery])) {
+		out[v1alpha1.SubscriptionRawMessageDelivery] = strconv.FormatBool(aws.ToBool(in.RawMessageDelivery))
+	}
+	if !jsonEqual(aws.ToString(in.FilterPolicy), attributes[v1alpha1.SubscriptionFilterPolicy]) {
+		out[v1alpha1.SubscriptionFilterPolicy] = aws.ToString(in.FilterPolicy)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// IsSubscriptionUpToDate returns true if the Subscription attributes in AWS
+// are same as Subscription spec, else returns false
+func IsSubscriptionUpToDate(in v1alpha1.SubscriptionParameters, attributes map[string]string) bool {
+	return GetSubscriptionAttributeDiff(in, attributes) == nil
+}
+
+// jsonEqual returns true if both strings hold the same JSON document. SNS
+// does not preserve the formatting of the documents it stores, so strings
+// that aren't valid JSON are compared as is.
+func jsonEqual(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var av, bv interface{}
+	if json.Unmarshal([]byte(a), &av) != nil || json.Unmarshal([]byte(b), &bv) != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
+// filterPolicyOperators are the keys SNS accepts inside an object that is
+// used as a filter policy value, e.g. {"prefix": "order-"}.
+var filterPolicyOperators = map[string]bool{
+	"anything-but":       true,
+	"cidr":               true,
+	"equals-ignore-case": true,
+	"exists":             true,
+	"numeric":            true,
+	"prefix":             true,
+	"suffix":             true,
+}
+
+// filterPolicyOr is the key of a filter policy object that matches messages
+// that any of the filter policy objects in its array match.
+const filterPolicyOr = "$or"
+
+// ValidateFilterPolicy checks that the supplied subscription filter policy is
+// syntactically valid. SNS accepts some malformed policies which then match
+// no messages at all, so an invalid filter silently drops everything.
+func ValidateFilterPolicy(policy string) error {
+	var p interface{}
+	if err := json.Unmarshal([]byte(policy), &p); err != nil {
+		return errors.Wrap(err, errFilterPolicyNotJSON)
+	}
+	m, ok := p.(map[string]interface{})
+	if !ok {
+		return errors.New(errFilterPolicyNotObject)
+	}
+	if len(m) == 0 {
+		return errors.New(errFilterPolicyEmpty)
+	}
+	return validateFilterPolicyObject(m, "")
+}
+
+// validateFilterPolicyObject validates every attribute of a filter policy
+// object. Nested objects are allowed so that message body policies can
+// filter on nested properties.
+func validateFilterPolicyObject(m map[string]interface{}, path string) error {
+	for k, v := range m {
+		if k == filterPolicyOr {
+			if err := validateFilterPolicyOr(v, path); err != nil {
+				return err
+			}
+			continue
+		}
+		p := strings.TrimPrefix(path+"."+k, ".")
+		switch val := v.(type) {
+		case []interface{}:
+			if err := validateFilterPolicyValues(val, p); err != nil {
+				return err
+			}
+		case map[string]interface{}:
+			if len(val) == 0 {
+				return errors.Errorf("filter policy attribute %q must not be an empty object", p)
+			}
+			if err := validateFilterPolicyObject(val, p); err != nil {
+				return err
+			}
+		default:
+			return errors.Errorf("filter policy attribute %q must be an array of values or a nested object", p)
+		}
+	}
+	return nil
+}
+
+// validateFilterPolicyOr validates the value of an $or key, which matches
+// messages that any of the filter policy objects in its array match. The
+// attributes of those objects are at the path of the object the key is in.
+func validateFilterPolicyOr(v interface{}, path string) error {
+	p := strings.TrimPrefix(path+"."+filterPolicyOr, ".")
+	objects, ok := v.([]interface{})
+	if !ok || len(objects) == 0 {
+		return errors.Errorf("filter policy attribute %q must be an array of filter policy objects", p)
+	}
+	for _, o := range objects {
+		m, ok := o.(map[string]interface{})
+		if !ok || len(m) == 0 {
+			return errors.Errorf("filter policy attribute %q must be an array of filter policy objects", p)
+		}
+		if err := validateFilterPolicyObject(m, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateFilterPolicyValues validates the list of values an attribute is
+// matched against.
+func validateFilterPolicyValues(values []interface{}, path string) error {
+	if len(values) == 0 {
+		return errors.Errorf("filter policy attribute %q must define at least one value", path)
+	}
+	for _, v := range values {
+		switch val := v.(type) {
+		case string, float64, bool, nil:
+		case map[string]interface{}:
+			if len(val) != 1 {
+				return errors.Errorf("filter policy attribute %q must use exactly one operator per object", path)
+			}
+			for op := range val {
+				if !filterPolicyOperators[op] {
+					return errors.Errorf("filter policy attribute %q uses unknown operator %q", path, op)
+				}
+			}
+		default:
+			return errors.Errorf("filter policy attribute %q contains an unsupported value", path)
+		}
+	}
+	return nil
+}
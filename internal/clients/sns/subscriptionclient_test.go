@@ -0,0 +1,218 @@
+package sns
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"provider-aws-controlapi/apis/sns/v1alpha1"
+)
+
+func TestValidateFilterPolicy(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		policy string
+		want   error
+	}{
+		"ValidAttributePolicy": {
+			reason: "A policy matching attribute values should be valid.",
+			policy: `{"store": ["example_corp"], "price_usd": [{"numeric": [">=", 100]}]}`,
+		},
+		"ValidNestedPolicy": {
+			reason: "A message body policy with nested properties should be valid.",
+			policy: `{"customer": {"interests": ["basketball", {"prefix": "foot"}]}}`,
+		},
+		"ValidExistsPolicy": {
+			reason: "Boolean and null values should be accepted.",
+			policy: `{"encrypted": [false], "region": [{"exists": true}, null]}`,
+		},
+		"ValidOrPolicy": {
+			reason: "A policy that matches any of several filter policy objects should be valid.",
+			policy: `{"source": ["aws.cloudwatch"], "$or": [{"metricName": ["CPUUtilization"]}, {"namespace": [{"prefix": "AWS/"}]}]}`,
+		},
+		"ValidNestedOrPolicy": {
+			reason: "An $or key in a nested property of a message body policy should be valid.",
+			policy: `{"customer": {"$or": [{"name": ["smith"]}, {"id": [{"numeric": [">", 0]}]}]}}`,
+		},
+				"InvalidJSON": {
+			reason: "A policy that is not JSON should be invalid.",
+			policy: `{"store": ["example_corp"]`,
+			want:   errors.Wrap(errors.New("unexpected end of JSON input"), errFilterPolicyNotJSON),
+		},
+		"NotAnObject": {
+			reason: "A policy must be a JSON object.",
+			policy: `["example_corp"]`,
+			want:   errors.New(errFilterPolicyNotObject),
+		},
+		"EmptyPolicy": {
+			reason: "A policy must filter on at least one attribute.",
+			policy: `{}`,
+			want:   errors.New(errFilterPolicyEmpty),
+		},
+		"ScalarValue": {
+			reason: "Attribute values must be wrapped in an array.",
+			policy: `{"store": "example_corp"}`,
+			want:   errors.New(`filter policy attribute "store" must be an array of values or a nested object`),
+		},
+		"EmptyValues": {
+			reason: "An attribute must match at least one value.",
+			policy: `{"store": []}`,
+			want:   errors.New(`filter policy attribute "store" must define at least one value`),
+		},
+		"UnknownOperator": {
+			reason: "Only operators supported by SNS should be accepted.",
+			policy: `{"customer": {"name": [{"contains": "smith"}]}}`,
+			want:   errors.New(`filter policy attribute "customer.name" uses unknown operator "contains"`),
+		},
+		"OrNotArray": {
+			reason: "The value of an $or key must be an array of filter policy objects.",
+			policy: `{"$or": {"store": ["example_corp"]}}`,
+			want:   errors.New(`filter policy attribute "$or" must be an array of filter policy objects`),
+		},
+		"OrValueNotObject": {
+			reason: "The values of an $or key must all be filter policy objects.",
+			policy: `{"customer": {"$or": [{"name": ["smith"]}, ["jones"]]}}`,
+			want:   errors.New(`filter policy attribute "customer.$or" must be an array of filter policy objects`),
+		},
+		"OrUnknownOperator": {
+			reason: "The filter policy objects of an $or key should be validated like any other.",
+			policy: `{"$or": [{"store": ["example_corp"]}, {"name": [{"contains": "smith"}]}]}`,
+			want:   errors.New(`filter policy attribute "name" uses unknown operator "contains"`),
+		},
+				"MultipleOperators": {
+			reason: "An operator object must contain a single operator.",
+			policy: `{"name": [{"prefix": "a", "suffix": "b"}]}`,
+			want:   errors.New(`filter policy attribute "name" must use exactly one operator per object`),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateFilterPolicy(tc.policy)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateFilterPolicy(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGetSubscriptionAttributeDiff(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		in         v1alpha1.SubscriptionParameters
+		attributes map[string]string
+		want       map[string]string
+	}{
+		"UpToDate": {
+			reason: "No attributes should be returned when the spec matches AWS.",
+			in: v1alpha1.SubscriptionParameters{
+				RawMessageDelivery: aws.Bool(true),
+				FilterPolicy:       aws.String(`{"store": ["example_corp"]}`),
+			},
+			attributes: map[string]string{
+				v1alpha1.SubscriptionRawMessageDelivery: "true",
+				v1alpha1.SubscriptionFilterPolicy:       `{"store":["example_corp"]}`,
+			},
+		},
+		"FilterPolicyChanged": {
+			reason: "A filter policy with different content should be returned.",
+			in: v1alpha1.SubscriptionParameters{
+				FilterPolicy: aws.String(`{"store": ["other_corp"]}`),
+			},
+			attributes: map[string]string{
+				v1alpha1.SubscriptionRawMessageDelivery: "false",
+				v1alpha1.SubscriptionFilterPolicy:       `{"store":["example_corp"]}`,
+			},
+			want: map[string]string{
+				v1alpha1.SubscriptionFilterPolicy: `{"store": ["other_corp"]}`,
+			},
+		},
+		"RawMessageDeliveryChanged": {
+			reason: "A changed raw message delivery setting should be returned.",
+			in: v1alpha1.SubscriptionParameters{
+				RawMessageDelivery: aws.Bool(true),
+			},
+			attributes: map[string]string{
+				v1alpha1.SubscriptionRawMessageDelivery: "false",
+			},
+			want: map[string]string{
+				v1alpha1.SubscriptionRawMessageDelivery: "true",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetSubscriptionAttributeDiff(tc.in, tc.attributes)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetSubscriptionAttributeDiff(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGenerateSubscriptionObservation(t *testing.T) {
+	subscriptionArn := "arn:aws:sns:us-west-2:123456789012:example:6b0e71bd-7e97-4d97-80ce-4a0994e55286"
+
+	cases := map[string]struct {
+		reason     string
+		attributes map[string]string
+		want       v1alpha1.SubscriptionObservation
+	}{
+		"Pending": {
+			reason: "Whether the confirmation was authenticated should not be observed until the subscription is confirmed.",
+			attributes: map[string]string{
+				v1alpha1.SubscriptionArn:                 subscriptionArn,
+				v1alpha1.SubscriptionOwner:               "123456789012",
+				v1alpha1.SubscriptionPendingConfirmation: "true",
+			},
+			want: v1alpha1.SubscriptionObservation{
+				SubscriptionArn:     aws.String(subscriptionArn),
+				Owner:               aws.String("123456789012"),
+				PendingConfirmation: aws.Bool(true),
+			},
+		},
+		"Authenticated": {
+			reason: "A subscription confirmed with an authenticated request should be observed as such.",
+			attributes: map[string]string{
+				v1alpha1.SubscriptionArn:                          subscriptionArn,
+				v1alpha1.SubscriptionOwner:                        "123456789012",
+				v1alpha1.SubscriptionPendingConfirmation:          "false",
+				v1alpha1.SubscriptionConfirmationWasAuthenticated: "true",
+			},
+			want: v1alpha1.SubscriptionObservation{
+				SubscriptionArn:              aws.String(subscriptionArn),
+				Owner:                        aws.String("123456789012"),
+				PendingConfirmation:          aws.Bool(false),
+				ConfirmationWasAuthenticated: aws.Bool(true),
+			},
+		},
+		"NotAuthenticated": {
+			reason: "A subscription confirmed with an unauthenticated request should be observed as such.",
+			attributes: map[string]string{
+				v1alpha1.SubscriptionArn:                          subscriptionArn,
+				v1alpha1.SubscriptionOwner:                        "123456789012",
+				v1alpha1.SubscriptionPendingConfirmation:          "false",
+				v1alpha1.SubscriptionConfirmationWasAuthenticated: "false",
+			},
+			want: v1alpha1.SubscriptionObservation{
+				SubscriptionArn:              aws.String(subscriptionArn),
+				Owner:                        aws.String("123456789012"),
+				PendingConfirmation:          aws.Bool(false),
+				ConfirmationWasAuthenticated: aws.Bool(false),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GenerateSubscriptionObservation(tc.attributes)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGenerateSubscriptionObservation(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -2,23 +2,73 @@ package sns
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	awssns "github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/aws/aws-sdk-go-v2/service/sns/types"
 	"github.com/aws/smithy-go"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"provider-aws-controlapi/apis/sns/v1alpha1"
 	awsclient "provider-aws-controlapi/internal/clients"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// AnnotationKeyCreatedTopic is the annotation that records the name of the
+// topic a Topic creates. It's persisted before the topic is created, so that
+// a Topic whose existing resource policy is Fail can tell the topic it
+// created from one it didn't, should it fail to persist the ARN of the topic.
+const AnnotationKeyCreatedTopic = "controlapi.aws/created-topic"
+
 const (
 	// TopicNotFound is the error code send by AWS API
 	// if topic doesn't exist
 	TopicNotFound = "NotFound"
+
+	// NotFoundException is the error code SNS returns for most operations on
+	// a topic or subscription that doesn't exist.
+	NotFoundException = "NotFoundException"
+
+	// ResourceNotFoundException is the error code SNS returns for tagging
+	// operations on a resource that doesn't exist.
+	ResourceNotFoundException = "ResourceNotFoundException"
+
+	// Throttled is the error code SNS returns if a request exceeds the API
+	// rate limit of the account.
+	Throttled = "Throttled"
+
+	// ThrottlingException is the error code AWS returns if a request is
+	// throttled, e.g. by the tagging API.
+	ThrottlingException = "ThrottlingException"
+
+	// ConcurrentAccess is the error code SNS returns if a resource is being
+	// modified by another request.
+	ConcurrentAccess = "ConcurrentAccess"
+
+	// ConnectionDetailsKeyName is the connection detail holding the name of
+	// the topic, including the .fifo suffix of FIFO topics.
+	ConnectionDetailsKeyName = "name"
+
+	// ConnectionDetailsKeyBaseName is the connection detail holding the name
+	// of the topic without the .fifo suffix of FIFO topics.
+	ConnectionDetailsKeyBaseName = "baseName"
+
+	// ConnectionDetailsKeyTopicName is the connection detail holding the
+	// name of the topic as parsed from its ARN.
+	ConnectionDetailsKeyTopicName = "topicName"
+
+	// ConnectionDetailsKeyRegion is the connection detail holding the region
+	// of the topic.
+	ConnectionDetailsKeyRegion = "region"
 )
 
 type Client interface {
@@ -29,6 +79,7 @@ type Client interface {
 	TagResource(ctx context.Context, params *awssns.TagResourceInput, optFns ...func(*awssns.Options)) (*awssns.TagResourceOutput, error)
 	UntagResource(ctx context.Context, params *awssns.UntagResourceInput, optFns ...func(*awssns.Options)) (*awssns.UntagResourceOutput, error)
 	ListTagsForResource(ctx context.Context, params *awssns.ListTagsForResourceInput, optFns ...func(*awssns.Options)) (*awssns.ListTagsForResourceOutput, error)
+	ListSubscriptionsByTopic(ctx context.Context, params *awssns.ListSubscriptionsByTopicInput, optFns ...func(*awssns.Options)) (*awssns.ListSubscriptionsByTopicOutput, error)
 }
 
 //GetClient returns the aws client for calling AWS SNS Apis
@@ -37,12 +88,184 @@ func GetClient(cfg aws.Config) Client{
 	return client
 }
 
-// IsNotFound checks if the error returned by AWS API says that the queue being probed doesn't exist
+// IsNotFound checks if the error returned by AWS API says that the topic or
+// subscription being probed doesn't exist
 func IsNotFound(err error) bool {
 	var awsErr smithy.APIError
-	return errors.As(err, &awsErr) && awsErr.ErrorCode() == TopicNotFound
+	if !errors.As(err, &awsErr) {
+		return false
+	}
+	switch awsErr.ErrorCode() {
+	case TopicNotFound, NotFoundException, ResourceNotFoundException:
+		return true
+	}
+	return false
+}
+
+// IsTransient checks if the error returned by AWS API says that the request
+// was throttled or conflicted with a concurrent request, in which case it
+// should be retried later rather than treated as a failure.
+func IsTransient(err error) bool {
+	var awsErr smithy.APIError
+	if !errors.As(err, &awsErr) {
+		return false
+	}
+	switch awsErr.ErrorCode() {
+	case Throttled, ThrottlingException, ConcurrentAccess:
+		return true
+	}
+	return false
 }
 
+// TopicARN returns the ARN of the topic with the supplied name in the supplied
+// region and account. SNS has no API to look a topic up by name, but its ARN
+// can be derived from it.
+func TopicARN(region, accountID, name string) string {
+	return arn.ARN{
+		Partition: awsclient.Partition(region),
+		Service:   "sns",
+		Region:    region,
+		AccountID: accountID,
+		Resource:  name,
+	}.String()
+}
+
+// CheckPartition returns an error if the supplied ARN isn't in the partition
+// of the supplied region. ARNs that can't be parsed aren't checked.
+func CheckPartition(region, resourceArn string) error {
+	a, err := arn.Parse(resourceArn)
+	if err != nil {
+		return nil
+	}
+	if want := awsclient.Partition(region); a.Partition != want {
+		return fmt.Errorf("ARN %q is in partition %q rather than %q of region %q, check the endpoint configuration", resourceArn, a.Partition, want, region)
+	}
+	return nil
+}
+
+// CheckARNUnchanged returns an error if the supplied ARN, which the spec of a
+// Topic implies, isn't in the region of the Topic, or isn't the ARN of the
+// topic it was last observed to manage. Such a Topic would otherwise manage,
+// or create, another topic and orphan its original one. A changed ARN is
+// accepted if the ARN change policy of the Topic is Adopt.
+func CheckARNUnchanged(p v1alpha1.TopicParameters, observed *string, topicArn string) error {
+	if a, err := arn.Parse(topicArn); err == nil && a.Region != p.Region {
+		return fmt.Errorf("topic %q is in region %q rather than %q, revert the region or set the external name to a topic in region %q", topicArn, a.Region, p.Region, p.Region)
+	}
+	if p.ARNChangePolicy != nil && *p.ARNChangePolicy == v1alpha1.ARNChangePolicyAdopt {
+		return nil
+	}
+	if o := aws.ToString(observed); o != "" && o != topicArn {
+		return fmt.Errorf("topic ARN changed from %q to %q, revert the external name or set the ARN change policy to %s", o, topicArn, v1alpha1.ARNChangePolicyAdopt)
+	}
+	return nil
+}
+
+// FailsOnExisting returns true if a Topic with the supplied parameters must
+// not manage a topic of the same name that it didn't create.
+func FailsOnExisting(p v1alpha1.TopicParameters) bool {
+	return p.ExistingResourcePolicy != nil && *p.ExistingResourcePolicy == v1alpha1.ExistingResourcePolicyFail
+}
+
+// CreatedTopic returns true if the supplied Topic recorded that it is about
+// to create, or created, the topic with the supplied name or ARN.
+func CreatedTopic(cr *v1alpha1.Topic, topic string) bool {
+	name, ok := cr.GetAnnotations()[AnnotationKeyCreatedTopic]
+	if !ok {
+		return false
+	}
+	if a, err := arn.Parse(topic); err == nil {
+		return a.Resource == name
+	}
+	return topic == name
+}
+
+// IndexKeyTopic is the field index of Topics by the topic they address, as
+// returned by TopicIndexValues.
+const IndexKeyTopic = "topic"
+
+// TopicIndexValues returns the values the supplied Topic is indexed by under
+// IndexKeyTopic: the ARN in its external name if it has been created or
+// imported, or else its name, region and ProviderConfig.
+func TopicIndexValues(t *v1alpha1.Topic) []string {
+	en := meta.GetExternalName(t)
+	if en == "" {
+		en = t.GetName()
+	}
+	if arn.IsARN(en) {
+		return []string{en}
+	}
+	return []string{topicIndexValue(awsclient.ProviderConfigName(t), t.Spec.ForProvider.Region, en)}
+}
+
+// SharedIndexValues returns the values under IndexKeyTopic of the Topics
+// that may address the topic with the supplied ARN, which the supplied Topic
+// addresses. SharedWith tells which of them do.
+func SharedIndexValues(cr *v1alpha1.Topic, topicArn string) []string {
+	a, err := arn.Parse(topicArn)
+	if err != nil {
+		return nil
+	}
+	return []string{topicArn, topicIndexValue(awsclient.ProviderConfigName(cr), a.Region, a.Resource)}
+}
+
+func topicIndexValue(providerConfig, region, name string) string {
+	return strings.Join([]string{providerConfig, region, name}, "/")
+}
+
+// SharedWith returns the names of the supplied Topics, other than the
+// supplied one, that address the topic with the supplied ARN. Topics that
+// have been created or imported are matched by the ARN in their external
+// name, others by their name, region and ProviderConfig. Deleted Topics are
+// ignored.
+func SharedWith(cr *v1alpha1.Topic, topicArn string, topics []v1alpha1.Topic) []string {
+	a, err := arn.Parse(topicArn)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for i := range topics {
+		t := &topics[i]
+		if t.GetName() == cr.GetName() || t.GetDeletionTimestamp() != nil {
+			continue
+		}
+		en := meta.GetExternalName(t)
+		if en == "" {
+			en = t.GetName()
+		}
+		shared := en == topicArn
+		if !arn.IsARN(en) {
+			shared = en == a.Resource && t.Spec.ForProvider.Region == a.Region && awsclient.ProviderConfigName(t) == awsclient.ProviderConfigName(cr)
+		}
+		if shared {
+			names = append(names, t.GetName())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithDefaultTags returns a copy of the supplied parameters whose tags are
+// merged with the supplied default tags, which is the set of tags the topic
+// should have.
+func WithDefaultTags(p v1alpha1.TopicParameters, defaults map[string]string) v1alpha1.TopicParameters {
+	out := *p.DeepCopy()
+	out.Tags = awsclient.MergeTags(defaults, p.Tags)
+	return out
+}
+
+// WithoutDefaultTags drops the observed tags that match a default tag, so that
+// they aren't late initialized into the tags of the resource.
+func WithoutDefaultTags(tags []types.Tag, defaults map[string]string) []types.Tag {
+	out := make([]types.Tag, 0, len(tags))
+	for _, t := range tags {
+		if v, ok := defaults[aws.ToString(t.Key)]; ok && v == aws.ToString(t.Value) {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
 
 // LateInitialize fills the empty fields in *v1alpha1.TopicParameters with
 // the values returned by GetTopicAttributes
@@ -55,58 +278,240 @@ func LateInitialize(in *v1alpha1.TopicParameters,attributes map[string]string, t
 	}
 
 	in.FifoTopic = awsclient.LateInitializeBoolPtr(in.FifoTopic,awsclient.StrToBoolPtr(attributes[v1alpha1.FifoTopic]))
-	in.DeliveryPolicy = awsclient.LateInitializeStringPtr(in.DeliveryPolicy,aws.String(attributes[v1alpha1.TopicDeliveryPolicy]))
-	in.DisplayName = awsclient.LateInitializeStringPtr(in.DisplayName,aws.String(attributes[v1alpha1.TopicDisplayName]))
-	in.Policy = awsclient.LateInitializeStringPtr(in.Policy,aws.String(attributes[v1alpha1.TopicPolicy]))
+	in.DeliveryPolicy = awsclient.LateInitializeStringPtr(in.DeliveryPolicy,attributeOrNil(attributes, v1alpha1.TopicDeliveryPolicy))
+	in.DisplayName = awsclient.LateInitializeStringPtr(in.DisplayName,attributeOrNil(attributes, v1alpha1.TopicDisplayName))
+	in.Policy = awsclient.LateInitializeStringPtr(in.Policy,attributeOrNil(attributes, v1alpha1.TopicPolicy))
 	in.ContentBasedDeduplication = awsclient.LateInitializeBoolPtr(in.ContentBasedDeduplication,awsclient.StrToBoolPtr(attributes[v1alpha1.FifoTopicContentBasedDeduplication]))
-	if in.KMSMasterKeyID == nil && attributes[v1alpha1.TopicKMSMasterKeyID] != ""{
-		in.KMSMasterKeyID = aws.String(attributes[v1alpha1.TopicKMSMasterKeyID])
+	in.KMSMasterKeyID = awsclient.LateInitializeStringPtr(in.KMSMasterKeyID,attributeOrNil(attributes, v1alpha1.TopicKMSMasterKeyID))
+	in.SignatureVersion = awsclient.LateInitializeStringPtr(in.SignatureVersion,attributeOrNil(attributes, v1alpha1.TopicSignatureVersion))
+	in.TracingConfig = awsclient.LateInitializeStringPtr(in.TracingConfig,attributeOrNil(attributes, v1alpha1.TopicTracingConfig))
+	in.ArchivePolicy = awsclient.LateInitializeStringPtr(in.ArchivePolicy,attributeOrNil(attributes, v1alpha1.TopicArchivePolicy))
+	lateInitializeDeliveryStatusLogging(in, attributes)
+}
+
+// lateInitializeDeliveryStatusLogging fills the empty delivery status logging
+// fields of the supplied parameters with the supplied attributes. Protocols
+// whose delivery status isn't logged aren't added.
+func lateInitializeDeliveryStatusLogging(in *v1alpha1.TopicParameters, attributes map[string]string) {
+	for _, p := range deliveryStatusProtocols {
+		role := attributeOrNil(attributes, p.prefix+v1alpha1.TopicSuccessFeedbackRoleArn)
+		rate := awsclient.StrToIntPtr(attributes[p.prefix+v1alpha1.TopicSuccessFeedbackSampleRate])
+		failureRole := attributeOrNil(attributes, p.prefix+v1alpha1.TopicFailureFeedbackRoleArn)
+		if role == nil && rate == nil && failureRole == nil {
+			continue
+		}
+		if in.DeliveryStatusLogging == nil {
+			in.DeliveryStatusLogging = &v1alpha1.DeliveryStatusLogging{}
+		}
+		c := p.config(in.DeliveryStatusLogging)
+		if *c == nil {
+			*c = &v1alpha1.DeliveryStatusLoggingConfig{}
+		}
+		(*c).SuccessFeedbackRoleARN = awsclient.LateInitializeStringPtr((*c).SuccessFeedbackRoleARN, role)
+		if (*c).SuccessFeedbackSampleRate == nil && rate != nil {
+			(*c).SuccessFeedbackSampleRate = aws.Int32(int32(*rate))
+		}
+		(*c).FailureFeedbackRoleARN = awsclient.LateInitializeStringPtr((*c).FailureFeedbackRoleARN, failureRole)
+	}
+}
+
+// attributeOrNil returns the supplied attribute, or nil if it's unset or
+// empty. SNS omits attributes that were never set, so an empty value means
+// there is nothing to late initialize.
+func attributeOrNil(attributes map[string]string, key string) *string {
+	if attributes[key] == "" {
+		return nil
 	}
+	return aws.String(attributes[key])
 }
 
 // GenerateObservation generates the observation for the Topic object
-// based on the Topic attributes received from AWS
-func GenerateObservation(attributes map[string]string) v1alpha1.TopicObservation{
+// based on the Topic attributes received from AWS. The supplied external
+// name, i.e. the ARN of the Topic, is observed if the attributes lack one.
+func GenerateObservation(externalName string, attributes map[string]string) v1alpha1.TopicObservation{
+	topicArn := attributes[v1alpha1.TopicArn]
+	if topicArn == "" {
+		topicArn = externalName
+	}
 
 	ob := v1alpha1.TopicObservation{
-		TopicArn: aws.String(attributes[v1alpha1.TopicArn]),
+		TopicArn: aws.String(topicArn),
 		SubscriptionsConfirmed: awsclient.StrToIntPtr(attributes[v1alpha1.TopicSubscriptionConfirmed]),
 		SubscriptionsPending: awsclient.StrToIntPtr(attributes[v1alpha1.TopicSubscriptionPending]),
 		SubscriptionsDeleted: awsclient.StrToIntPtr(attributes[v1alpha1.TopicSubscriptionDeleted]),
 		EffectiveDeliveryPolicy: aws.String(attributes[v1alpha1.TopicEffectiveDeliveryPolicy]),
+		Owner: attributeOrNil(attributes, v1alpha1.TopicOwner),
+		KMSMasterKeyID: attributeOrNil(attributes, v1alpha1.TopicKMSMasterKeyID),
+		BeginningArchiveTime: attributeOrNil(attributes, v1alpha1.TopicBeginningArchiveTime),
+		UsesCustomerManagedKey: UsesCustomerManagedKey(attributes[v1alpha1.TopicKMSMasterKeyID]),
+		EffectiveHTTPDeliveryPolicy: ParseEffectiveDeliveryPolicy(attributes[v1alpha1.TopicEffectiveDeliveryPolicy]),
+	}
+	if a, err := arn.Parse(topicArn); err == nil {
+		ob.Name = aws.String(a.Resource)
+		ob.BaseName = aws.String(strings.TrimSuffix(a.Resource, fifoTopicSuffix))
 	}
 	return ob
 }
 
-// IsUpToDate returns true if the Topic attributes in AWS
-// are same as Topic spec, else returns false
-func IsUpToDate(p v1alpha1.TopicParameters, attributes map[string]string, tags []types.Tag) bool{
+// effectiveDeliveryPolicy is the JSON serialization of the effective delivery
+// policy of a topic. Only the http section is parsed; SNS doesn't support
+// delivery policies for other protocols.
+type effectiveDeliveryPolicy struct {
+	HTTP *struct {
+		DefaultHealthyRetryPolicy *struct {
+			NumRetries         *int    `json:"numRetries"`
+			NumNoDelayRetries  *int    `json:"numNoDelayRetries"`
+			NumMinDelayRetries *int    `json:"numMinDelayRetries"`
+			NumMaxDelayRetries *int    `json:"numMaxDelayRetries"`
+			MinDelayTarget     *int    `json:"minDelayTarget"`
+			MaxDelayTarget     *int    `json:"maxDelayTarget"`
+			BackoffFunction    *string `json:"backoffFunction"`
+		} `json:"defaultHealthyRetryPolicy"`
+		DisableSubscriptionOverrides *bool `json:"disableSubscriptionOverrides"`
+		DefaultThrottlePolicy        *struct {
+			MaxReceivesPerSecond *int `json:"maxReceivesPerSecond"`
+		} `json:"defaultThrottlePolicy"`
+	} `json:"http"`
+}
 
-	if len(p.Tags) != len(tags){
-		return false
+// ParseEffectiveDeliveryPolicy parses the http section of the supplied
+// effective delivery policy of a topic. It returns nil if the policy is empty,
+// can't be parsed or has no http section.
+func ParseEffectiveDeliveryPolicy(policy string) *v1alpha1.DeliveryPolicyObservation {
+	if policy == "" {
+		return nil
+	}
+	p := effectiveDeliveryPolicy{}
+	if err := json.Unmarshal([]byte(policy), &p); err != nil || p.HTTP == nil {
+		return nil
+	}
+	ob := &v1alpha1.DeliveryPolicyObservation{DisableSubscriptionOverrides: p.HTTP.DisableSubscriptionOverrides}
+	if r := p.HTTP.DefaultHealthyRetryPolicy; r != nil {
+		ob.HealthyRetryPolicy = &v1alpha1.RetryPolicyObservation{
+			NumRetries:         r.NumRetries,
+			NumNoDelayRetries:  r.NumNoDelayRetries,
+			NumMinDelayRetries: r.NumMinDelayRetries,
+			NumMaxDelayRetries: r.NumMaxDelayRetries,
+			MinDelayTarget:     r.MinDelayTarget,
+			MaxDelayTarget:     r.MaxDelayTarget,
+			BackoffFunction:    r.BackoffFunction,
+		}
+	}
+	if t := p.HTTP.DefaultThrottlePolicy; t != nil {
+		ob.MaxReceivesPerSecond = t.MaxReceivesPerSecond
 	}
+	return ob
+}
 
-	for _,v := range tags{
-		tagVal, ok := p.Tags[aws.ToString(v.Key)]
-		if !ok || !strings.EqualFold(tagVal,aws.ToString(v.Value)){
-			return false
+// awsManagedKeyAlias is the alias of the AWS managed KMS key of SNS.
+const awsManagedKeyAlias = "alias/aws/sns"
+
+// UsesCustomerManagedKey returns whether the supplied KMS master key of a
+// topic is a customer managed key rather than the AWS managed key of SNS. The
+// AWS managed key is recognised by its alias, either as is or as an alias ARN.
+// It returns nil if no key is supplied, i.e. the topic isn't encrypted.
+func UsesCustomerManagedKey(keyID string) *bool {
+	if keyID == "" {
+		return nil
+	}
+	return aws.Bool(keyID != awsManagedKeyAlias && !strings.HasSuffix(keyID, ":"+awsManagedKeyAlias))
+}
+
+// CountSubscriptionsByProtocol returns the number of subscriptions to the
+// topic with the supplied ARN for each protocol. All pages of subscriptions
+// are listed.
+func CountSubscriptionsByProtocol(ctx context.Context, c Client, topicArn string) (map[string]int, error) {
+	counts := map[string]int{}
+	var token *string
+	for {
+		resp, err := c.ListSubscriptionsByTopic(ctx, &awssns.ListSubscriptionsByTopicInput{
+			TopicArn:  aws.String(topicArn),
+			NextToken: token,
+		})
+		if err != nil {
+			return nil, err
 		}
+		for _, s := range resp.Subscriptions {
+			counts[aws.ToString(s.Protocol)]++
+		}
+		if aws.ToString(resp.NextToken) == "" {
+			return counts, nil
+		}
+		token = resp.NextToken
 	}
+}
 
-	if !strings.EqualFold(aws.ToString(p.Policy),attributes[v1alpha1.TopicPolicy]){
-		return false
+// ObserveTags records the supplied tags of a topic in the supplied
+// observation. The time the tags were observed at is carried over from the
+// supplied previous observation if they haven't changed since, and is the
+// supplied current time otherwise.
+func ObserveTags(ob *v1alpha1.TopicObservation, previous v1alpha1.TopicObservation, tags []types.Tag, now time.Time) {
+	if len(tags) > 0 {
+		ob.Tags = make(map[string]string, len(tags))
+		for _, t := range tags {
+			ob.Tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+		}
 	}
-	if !strings.EqualFold(aws.ToString(p.DisplayName),attributes[v1alpha1.TopicDisplayName]){
-		return false
+	ob.TagsObservedAt = previous.TagsObservedAt
+	if previous.TagsObservedAt == nil || !tagsEqual(previous.Tags, tags) {
+		t := metav1.NewTime(now)
+		ob.TagsObservedAt = &t
 	}
-	if !strings.EqualFold(aws.ToString(p.DeliveryPolicy),attributes[v1alpha1.TopicDeliveryPolicy]){
-		return false
+}
+
+// GenerateCostTags summarizes which of the supplied recommended cost
+// allocation tag keys are present in the supplied tags. It returns nil if no
+// tags are recommended.
+func GenerateCostTags(recommended []string, tags []types.Tag) *v1alpha1.CostTagsObservation {
+	if len(recommended) == 0 {
+		return nil
 	}
+	values := make(map[string]string, len(tags))
+	for _, t := range tags {
+		values[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	ob := &v1alpha1.CostTagsObservation{}
+	for _, k := range recommended {
+		v, ok := values[k]
+		if !ok {
+			ob.Missing = append(ob.Missing, k)
+			continue
+		}
+		if ob.Present == nil {
+			ob.Present = map[string]string{}
+		}
+		ob.Present[k] = v
+	}
+	sort.Strings(ob.Missing)
+	return ob
+}
+
+// TrimObservation drops the optional fields of the supplied observation, so
+// that the Topic stays below the maximum object size. The fields that
+// identify the topic are kept.
+func TrimObservation(ob *v1alpha1.TopicObservation) {
+	ob.EffectiveDeliveryPolicy = nil
+	ob.EffectiveHTTPDeliveryPolicy = nil
+	ob.Tags = nil
+	ob.TagsObservedAt = nil
+	ob.SubscriptionsByProtocol = nil
+	ob.CostTags = nil
+}
+
+// IsUpToDate returns true if the Topic attributes in AWS
+// are same as Topic spec, else returns false
+func IsUpToDate(p v1alpha1.TopicParameters, attributes map[string]string, tags []types.Tag) bool{
 
-	if !strings.EqualFold(aws.ToString(p.KMSMasterKeyID),attributes[v1alpha1.TopicKMSMasterKeyID]){
+	if !tagsEqual(p.Tags, tags) {
 		return false
 	}
 
+	for _, a := range stringAttributes(p) {
+		if a.needsUpdate(attributes) {
+			return false
+		}
+	}
+
 	b, e := strconv.ParseBool(attributes[v1alpha1.FifoTopic])
 	if e != nil || aws.ToBool(p.FifoTopic) != b{
 		return false
@@ -119,13 +524,165 @@ func IsUpToDate(p v1alpha1.TopicParameters, attributes map[string]string, tags [
 	return true
 }
 
+// tagsEqual returns true if the supplied tags of a topic are exactly the
+// supplied desired tags. Tag keys and values are case-sensitive.
+func tagsEqual(desired map[string]string, tags []types.Tag) bool {
+	observed := make(map[string]string, len(tags))
+	for _, t := range tags {
+		observed[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	for k, v := range desired {
+		if o, ok := observed[k]; !ok || o != v {
+			return false
+		}
+	}
+	for k := range observed {
+		if _, ok := desired[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// resettableAttributes are the string attributes SNS accepts an empty value
+// for, which resets them. Clearing the DisplayName removes the display name,
+// and clearing the KmsMasterKeyId disables server-side encryption. SNS
+// rejects an empty Policy or DeliveryPolicy, so clearing those in the spec
+// leaves the topic's values as they are.
+var resettableAttributes = map[string]bool{
+	v1alpha1.TopicDisplayName:    true,
+	v1alpha1.TopicKMSMasterKeyID: true,
+}
+
+// A stringAttribute is a string topic attribute and its desired value.
+type stringAttribute struct {
+	key     string
+	desired *string
+	equal   func(desired, observed string) bool
+}
+
+func stringAttributes(p v1alpha1.TopicParameters) []stringAttribute {
+	return append([]stringAttribute{
+		{key: v1alpha1.TopicPolicy, desired: p.Policy, equal: policyEqual},
+		{key: v1alpha1.TopicDisplayName, desired: p.DisplayName, equal: strings.EqualFold},
+		{key: v1alpha1.TopicKMSMasterKeyID, desired: p.KMSMasterKeyID, equal: strings.EqualFold},
+		{key: v1alpha1.TopicDeliveryPolicy, desired: p.DeliveryPolicy, equal: strings.EqualFold},
+		{key: v1alpha1.TopicSignatureVersion, desired: p.SignatureVersion, equal: stringsEqual},
+		{key: v1alpha1.TopicTracingConfig, desired: p.TracingConfig, equal: stringsEqual},
+		{key: v1alpha1.TopicArchivePolicy, desired: p.ArchivePolicy, equal: jsonEqual},
+	}, deliveryStatusAttributes(p)...)
+}
+
+// deliveryStatusAttributes returns the delivery status logging attributes of
+// the protocols the supplied parameters configure. Sample rates are
+// serialized as strings, like SNS returns them.
+func deliveryStatusAttributes(p v1alpha1.TopicParameters) []stringAttribute {
+	if p.DeliveryStatusLogging == nil {
+		return nil
+	}
+	var attrs []stringAttribute
+	for _, dp := range deliveryStatusProtocols {
+		c := *dp.config(p.DeliveryStatusLogging)
+		if c == nil {
+			continue
+		}
+		var rate *string
+		if c.SuccessFeedbackSampleRate != nil {
+			rate = aws.String(strconv.Itoa(int(*c.SuccessFeedbackSampleRate)))
+		}
+		attrs = append(attrs,
+			stringAttribute{key: dp.prefix + v1alpha1.TopicSuccessFeedbackRoleArn, desired: c.SuccessFeedbackRoleARN, equal: stringsEqual},
+			stringAttribute{key: dp.prefix + v1alpha1.TopicSuccessFeedbackSampleRate, desired: rate, equal: stringsEqual},
+			stringAttribute{key: dp.prefix + v1alpha1.TopicFailureFeedbackRoleArn, desired: c.FailureFeedbackRoleARN, equal: stringsEqual},
+		)
+	}
+	return attrs
+}
+
+func stringsEqual(a, b string) bool { return a == b }
+
+// A deliveryStatusProtocol is a protocol SNS can log the delivery status of
+// messages for.
+type deliveryStatusProtocol struct {
+	// prefix of the names of the topic attributes that configure it.
+	prefix string
+
+	// field of the delivery status logging configuration that configures
+	// it, as named in the spec.
+	field string
+
+	// config returns the field of the supplied delivery status logging
+	// configuration that configures the protocol.
+	config func(l *v1alpha1.DeliveryStatusLogging) **v1alpha1.DeliveryStatusLoggingConfig
+}
+
+var deliveryStatusProtocols = []deliveryStatusProtocol{
+	{prefix: v1alpha1.DeliveryStatusProtocolHTTP, field: "http", config: func(l *v1alpha1.DeliveryStatusLogging) **v1alpha1.DeliveryStatusLoggingConfig { return &l.HTTP }},
+	{prefix: v1alpha1.DeliveryStatusProtocolSQS, field: "sqs", config: func(l *v1alpha1.DeliveryStatusLogging) **v1alpha1.DeliveryStatusLoggingConfig { return &l.SQS }},
+	{prefix: v1alpha1.DeliveryStatusProtocolLambda, field: "lambda", config: func(l *v1alpha1.DeliveryStatusLogging) **v1alpha1.DeliveryStatusLoggingConfig { return &l.Lambda }},
+	{prefix: v1alpha1.DeliveryStatusProtocolApplication, field: "application", config: func(l *v1alpha1.DeliveryStatusLogging) **v1alpha1.DeliveryStatusLoggingConfig { return &l.Application }},
+	{prefix: v1alpha1.DeliveryStatusProtocolFirehose, field: "firehose", config: func(l *v1alpha1.DeliveryStatusLogging) **v1alpha1.DeliveryStatusLoggingConfig { return &l.Firehose }},
+}
+
+// needsUpdate returns true if the attribute should be set to its desired
+// value. Attributes without a desired value aren't managed. An empty desired
+// value resets the attribute if it's resettable, and is ignored otherwise.
+func (a stringAttribute) needsUpdate(attributes map[string]string) bool {
+	if a.desired == nil {
+		return false
+	}
+	if *a.desired == "" && !resettableAttributes[a.key] {
+		return false
+	}
+	return !a.equal(*a.desired, attributes[a.key])
+}
+
+// requiredAttributes are the attributes SNS returns for every topic.
+var requiredAttributes = []string{v1alpha1.TopicArn, v1alpha1.TopicOwner}
+
+// CheckAttributesComplete returns an error if the supplied attributes, as
+// returned by GetTopicAttributes, lack any of the attributes SNS returns for
+// every topic. SNS is eventually consistent and may briefly return incomplete
+// attributes after a topic changed. Comparing them to the desired parameters
+// would report drift that doesn't exist, so they should be read again later.
+func CheckAttributesComplete(attributes map[string]string) error {
+	var missing []string
+	for _, k := range requiredAttributes {
+		if _, ok := attributes[k]; !ok {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("SNS returned incomplete topic attributes, missing %s", strings.Join(missing, ", "))
+}
+
 // GetConnectionDetails returns the Topic Arn which will be included in the secret
 func GetConnectionDetails(in v1alpha1.Topic) managed.ConnectionDetails{
 	if in.Status.AtProvider.TopicArn == nil{
 		return nil
 	}
+	c := TopicConnectionDetails(aws.ToString(in.Status.AtProvider.TopicArn), in.Spec.ForProvider.Region)
+	if in.Status.AtProvider.Name != nil {
+		c[ConnectionDetailsKeyName] = []byte(aws.ToString(in.Status.AtProvider.Name))
+	}
+	if in.Status.AtProvider.BaseName != nil {
+		c[ConnectionDetailsKeyBaseName] = []byte(aws.ToString(in.Status.AtProvider.BaseName))
+	}
+	return c
+}
+
+// TopicConnectionDetails returns the connection details of the topic with
+// the supplied ARN in the supplied region. The ARN is the endpoint, and the
+// name of the topic is parsed from it.
+func TopicConnectionDetails(topicArn, region string) managed.ConnectionDetails {
 	c := managed.ConnectionDetails{
-		xpv1.ResourceCredentialsSecretEndpointKey: []byte(aws.ToString(in.Status.AtProvider.TopicArn)),
+		xpv1.ResourceCredentialsSecretEndpointKey: []byte(topicArn),
+		ConnectionDetailsKeyRegion:                []byte(region),
+	}
+	if a, err := arn.Parse(topicArn); err == nil {
+		c[ConnectionDetailsKeyTopicName] = []byte(a.Resource)
 	}
 	return c
 }
@@ -152,6 +709,20 @@ func GenerateTopicAttributeMap(in v1alpha1.TopicParameters) map[string]string{
 	if in.ContentBasedDeduplication != nil{
 		attributes[v1alpha1.FifoTopicContentBasedDeduplication] = strconv.FormatBool(aws.ToBool(in.ContentBasedDeduplication))
 	}
+	if in.SignatureVersion != nil{
+		attributes[v1alpha1.TopicSignatureVersion] = aws.ToString(in.SignatureVersion)
+	}
+	if in.TracingConfig != nil{
+		attributes[v1alpha1.TopicTracingConfig] = aws.ToString(in.TracingConfig)
+	}
+	if in.ArchivePolicy != nil{
+		attributes[v1alpha1.TopicArchivePolicy] = aws.ToString(in.ArchivePolicy)
+	}
+	for _, a := range deliveryStatusAttributes(in) {
+		if a.desired != nil {
+			attributes[a.key] = *a.desired
+		}
+	}
 	if len(attributes) == 0{
 		return nil
 	}
@@ -163,21 +734,14 @@ func GenerateTopicAttributeMap(in v1alpha1.TopicParameters) map[string]string{
 func GetAttributeDiff(in v1alpha1.TopicParameters, attributes map[string]string) map[string]string{
 	out := make(map[string]string)
 
-	if !strings.EqualFold(aws.ToString(in.Policy),attributes[v1alpha1.TopicPolicy]){
-		out[v1alpha1.TopicPolicy] = aws.ToString(in.Policy)
+	for _, a := range stringAttributes(in) {
+		if a.needsUpdate(attributes) {
+			out[a.key] = aws.ToString(a.desired)
+		}
 	}
 	if aws.ToBool(in.FifoTopic) != aws.ToBool(awsclient.StrToBoolPtr(attributes[v1alpha1.FifoTopic])){
 		out[v1alpha1.FifoTopic] = strconv.FormatBool(aws.ToBool(in.FifoTopic))
 	}
-	if !strings.EqualFold(aws.ToString(in.DisplayName),attributes[v1alpha1.TopicDisplayName]){
-		out[v1alpha1.TopicDisplayName] = aws.ToString(in.DisplayName)
-	}
-	if !strings.EqualFold(aws.ToString(in.KMSMasterKeyID),attributes[v1alpha1.TopicKMSMasterKeyID]){
-		out[v1alpha1.TopicKMSMasterKeyID] = aws.ToString(in.KMSMasterKeyID)
-	}
-	if !strings.EqualFold(aws.ToString(in.DeliveryPolicy),attributes[v1alpha1.TopicDeliveryPolicy]){
-		out[v1alpha1.TopicDeliveryPolicy] = aws.ToString(in.DeliveryPolicy)
-	}
 	if aws.ToBool(in.ContentBasedDeduplication) != aws.ToBool(awsclient.StrToBoolPtr(attributes[v1alpha1.FifoTopicContentBasedDeduplication])){
 		out[v1alpha1.FifoTopicContentBasedDeduplication] = strconv.FormatBool(aws.ToBool(in.ContentBasedDeduplication))
 	}
@@ -188,6 +752,54 @@ func GetAttributeDiff(in v1alpha1.TopicParameters, attributes map[string]string)
 	return out
 }
 
+// CheckAttributesApplied returns an error naming the attributes of the
+// supplied set that still differ from the desired parameters in the supplied
+// attributes, as read back after setting them.
+func CheckAttributesApplied(p v1alpha1.TopicParameters, set, attributes map[string]string) error {
+	diff := GetAttributeDiff(p, attributes)
+	var notApplied []string
+	for k := range set {
+		if _, ok := diff[k]; ok {
+			notApplied = append(notApplied, k)
+		}
+	}
+	if len(notApplied) == 0 {
+		return nil
+	}
+	sort.Strings(notApplied)
+	return fmt.Errorf("SNS did not apply topic attributes: %s", strings.Join(notApplied, ", "))
+}
+
+// CheckTagsApplied returns an error naming the keys of the supplied added
+// and removed tags that the supplied tags, as listed after tagging and
+// untagging the topic, don't reflect. A key that was removed and added again
+// with another value is expected to have that value.
+func CheckTagsApplied(add []types.Tag, remove []string, tags []types.Tag) error {
+	observed := make(map[string]string, len(tags))
+	for _, t := range tags {
+		observed[aws.ToString(t.Key)] = aws.ToString(t.Value)
+	}
+	added := make(map[string]bool, len(add))
+	var notApplied []string
+	for _, t := range add {
+		k := aws.ToString(t.Key)
+		added[k] = true
+		if v, ok := observed[k]; !ok || v != aws.ToString(t.Value) {
+			notApplied = append(notApplied, k)
+		}
+	}
+	for _, k := range remove {
+		if _, ok := observed[k]; ok && !added[k] {
+			notApplied = append(notApplied, k)
+		}
+	}
+	if len(notApplied) == 0 {
+		return nil
+	}
+	sort.Strings(notApplied)
+	return fmt.Errorf("SNS did not apply topic tags: %s", strings.Join(notApplied, ", "))
+}
+
 // GetDiffTags returns tags which are required to be added
 // or removed from external resource
 func GetDiffTags(in v1alpha1.TopicParameters,tags []types.Tag) (addTags []types.Tag, removeTags []string){
@@ -0,0 +1,1127 @@
+package sns
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssns "github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/smithy-go"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"provider-aws-controlapi/apis/sns/v1alpha1"
+)
+
+const loggingRole = "arn:aws:iam::123456789012:role/sns-logging"
+
+func TestGetAttributeDiff(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		in         v1alpha1.TopicParameters
+		attributes map[string]string
+		want       map[string]string
+	}{
+		"PolicyReordered": {
+			reason: "A policy that AWS stored in canonical form should not be updated.",
+			in:     v1alpha1.TopicParameters{Policy: aws.String(policy)},
+			attributes: map[string]string{
+				v1alpha1.TopicPolicy: reorderedPolicy,
+			},
+		},
+		"PolicyChanged": {
+			reason: "A policy with different statements should be updated.",
+			in:     v1alpha1.TopicParameters{Policy: aws.String(policy)},
+			attributes: map[string]string{
+				v1alpha1.TopicPolicy: `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"sns:Publish"}]}`,
+			},
+			want: map[string]string{
+				v1alpha1.TopicPolicy: policy,
+			},
+		},
+		"DisplayNameCleared": {
+			reason:     "A cleared display name should be reset to empty.",
+			in:         v1alpha1.TopicParameters{DisplayName: aws.String("")},
+			attributes: map[string]string{v1alpha1.TopicDisplayName: "example"},
+			want:       map[string]string{v1alpha1.TopicDisplayName: ""},
+		},
+		"KMSMasterKeyIDCleared": {
+			reason:     "A cleared KMS master key should be reset to empty, which disables encryption.",
+			in:         v1alpha1.TopicParameters{KMSMasterKeyID: aws.String("")},
+			attributes: map[string]string{v1alpha1.TopicKMSMasterKeyID: "alias/aws/sns"},
+			want:       map[string]string{v1alpha1.TopicKMSMasterKeyID: ""},
+		},
+		"PolicyCleared": {
+			reason:     "A cleared policy should be left as it is, since SNS rejects an empty policy.",
+			in:         v1alpha1.TopicParameters{Policy: aws.String("")},
+			attributes: map[string]string{v1alpha1.TopicPolicy: policy},
+		},
+		"DeliveryPolicyCleared": {
+			reason:     "A cleared delivery policy should be left as it is, since SNS rejects an empty delivery policy.",
+			in:         v1alpha1.TopicParameters{DeliveryPolicy: aws.String("")},
+			attributes: map[string]string{v1alpha1.TopicDeliveryPolicy: `{"http":{}}`},
+		},
+		"DeliveryStatusLoggingChanged": {
+			reason: "Delivery status logging attributes that differ should be updated, with sample rates as strings.",
+			in: v1alpha1.TopicParameters{DeliveryStatusLogging: &v1alpha1.DeliveryStatusLogging{
+				Lambda: &v1alpha1.DeliveryStatusLoggingConfig{
+					SuccessFeedbackRoleARN:    aws.String(loggingRole),
+					SuccessFeedbackSampleRate: aws.Int32(25),
+					FailureFeedbackRoleARN:    aws.String(loggingRole),
+				},
+			}},
+			attributes: map[string]string{
+				"LambdaSuccessFeedbackRoleArn":    loggingRole,
+				"LambdaSuccessFeedbackSampleRate": "100",
+			},
+			want: map[string]string{
+				"LambdaSuccessFeedbackSampleRate": "25",
+				"LambdaFailureFeedbackRoleArn":    loggingRole,
+			},
+		},
+		"SignatureAndTracingChanged": {
+			reason: "A signature version or tracing config that differs should be updated.",
+			in: v1alpha1.TopicParameters{
+				SignatureVersion: aws.String("2"),
+				TracingConfig:    aws.String("Active"),
+			},
+			attributes: map[string]string{
+				v1alpha1.TopicSignatureVersion: "1",
+				v1alpha1.TopicTracingConfig:    "PassThrough",
+			},
+			want: map[string]string{
+				v1alpha1.TopicSignatureVersion: "2",
+				v1alpha1.TopicTracingConfig:    "Active",
+			},
+		},
+		"ArchivePolicyReformatted": {
+			reason: "An archive policy that SNS stored formatted differently should not be updated.",
+			in:     v1alpha1.TopicParameters{ArchivePolicy: aws.String(`{"MessageRetentionPeriod": "30"}`)},
+			attributes: map[string]string{
+				v1alpha1.TopicArchivePolicy: `{"MessageRetentionPeriod":"30"}`,
+			},
+		},
+		"ArchivePolicyChanged": {
+			reason: "An archive policy with a different retention period should be updated.",
+			in:     v1alpha1.TopicParameters{ArchivePolicy: aws.String(`{"MessageRetentionPeriod": "30"}`)},
+			attributes: map[string]string{
+				v1alpha1.TopicArchivePolicy: `{"MessageRetentionPeriod":"7"}`,
+			},
+			want: map[string]string{
+				v1alpha1.TopicArchivePolicy: `{"MessageRetentionPeriod": "30"}`,
+			},
+		},
+		"Unset": {
+			reason: "Attributes that aren't set in the spec shouldn't be managed.",
+			attributes: map[string]string{
+				"HTTPSuccessFeedbackRoleArn": loggingRole,
+				v1alpha1.TopicDisplayName:    "example",
+				v1alpha1.TopicKMSMasterKeyID: "alias/aws/sns",
+				v1alpha1.TopicPolicy:         policy,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetAttributeDiff(tc.in, tc.attributes)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetAttributeDiff(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsUpToDate(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		in         v1alpha1.TopicParameters
+		attributes map[string]string
+		tags       []types.Tag
+		want       bool
+	}{
+		"PolicyReordered": {
+			reason: "A policy that AWS stored in canonical form should be up to date.",
+			in:     v1alpha1.TopicParameters{Policy: aws.String(policy)},
+			attributes: map[string]string{
+				v1alpha1.TopicPolicy:                        reorderedPolicy,
+				v1alpha1.FifoTopic:                          "false",
+				v1alpha1.FifoTopicContentBasedDeduplication: "false",
+			},
+			want: true,
+		},
+		"PolicyChanged": {
+			reason: "A policy with different statements should not be up to date.",
+			in:     v1alpha1.TopicParameters{Policy: aws.String(policy)},
+			attributes: map[string]string{
+				v1alpha1.TopicPolicy:                        `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"sns:Publish"}]}`,
+				v1alpha1.FifoTopic:                          "false",
+				v1alpha1.FifoTopicContentBasedDeduplication: "false",
+			},
+			want: false,
+		},
+		"DeliveryStatusLoggingEqual": {
+			reason: "Delivery status logging attributes that match should be up to date.",
+			in: v1alpha1.TopicParameters{DeliveryStatusLogging: &v1alpha1.DeliveryStatusLogging{
+				SQS: &v1alpha1.DeliveryStatusLoggingConfig{SuccessFeedbackRoleARN: aws.String(loggingRole), SuccessFeedbackSampleRate: aws.Int32(0)},
+			}},
+			attributes: map[string]string{
+				"SQSSuccessFeedbackRoleArn":                 loggingRole,
+				"SQSSuccessFeedbackSampleRate":              "0",
+				v1alpha1.FifoTopic:                          "false",
+				v1alpha1.FifoTopicContentBasedDeduplication: "false",
+			},
+			want: true,
+		},
+		"DeliveryStatusLoggingChanged": {
+			reason: "A sample rate that differs should not be up to date.",
+			in: v1alpha1.TopicParameters{DeliveryStatusLogging: &v1alpha1.DeliveryStatusLogging{
+				SQS: &v1alpha1.DeliveryStatusLoggingConfig{SuccessFeedbackSampleRate: aws.Int32(50)},
+			}},
+			attributes: map[string]string{
+				"SQSSuccessFeedbackSampleRate":              "0",
+				v1alpha1.FifoTopic:                          "false",
+				v1alpha1.FifoTopicContentBasedDeduplication: "false",
+			},
+			want: false,
+		},
+		"TagsEqual": {
+			reason: "Tags with the same keys and values should be up to date.",
+			in:     v1alpha1.TopicParameters{Tags: map[string]string{"env": "prod", "team": "payments"}},
+			attributes: map[string]string{
+				v1alpha1.FifoTopic:                          "false",
+				v1alpha1.FifoTopicContentBasedDeduplication: "false",
+			},
+			tags: []types.Tag{{Key: aws.String("team"), Value: aws.String("payments")}, {Key: aws.String("env"), Value: aws.String("prod")}},
+			want: true,
+		},
+		"TagValueCaseDiffers": {
+			reason: "Tag values are case-sensitive, so a value that only differs in case should not be up to date.",
+			in:     v1alpha1.TopicParameters{Tags: map[string]string{"env": "Prod"}},
+			attributes: map[string]string{
+				v1alpha1.FifoTopic:                          "false",
+				v1alpha1.FifoTopicContentBasedDeduplication: "false",
+			},
+			tags: []types.Tag{{Key: aws.String("env"), Value: aws.String("prod")}},
+			want: false,
+		},
+		"TagKeyCaseDiffers": {
+			reason: "Tag keys are case-sensitive, so a key that only differs in case should not be up to date.",
+			in:     v1alpha1.TopicParameters{Tags: map[string]string{"Env": "prod"}},
+			attributes: map[string]string{
+				v1alpha1.FifoTopic:                          "false",
+				v1alpha1.FifoTopicContentBasedDeduplication: "false",
+			},
+			tags: []types.Tag{{Key: aws.String("env"), Value: aws.String("prod")}},
+			want: false,
+		},
+		"TagOnlyInSpec": {
+			reason: "A tag that only the spec has should not be up to date.",
+			in:     v1alpha1.TopicParameters{Tags: map[string]string{"env": "prod", "team": "payments"}},
+			attributes: map[string]string{
+				v1alpha1.FifoTopic:                          "false",
+				v1alpha1.FifoTopicContentBasedDeduplication: "false",
+			},
+			tags: []types.Tag{{Key: aws.String("env"), Value: aws.String("prod")}},
+			want: false,
+		},
+		"TagOnlyInAWS": {
+			reason: "A tag that only the topic in AWS has should not be up to date.",
+			in:     v1alpha1.TopicParameters{Tags: map[string]string{"env": "prod"}},
+			attributes: map[string]string{
+				v1alpha1.FifoTopic:                          "false",
+				v1alpha1.FifoTopicContentBasedDeduplication: "false",
+			},
+			tags: []types.Tag{{Key: aws.String("env"), Value: aws.String("prod")}, {Key: aws.String("team"), Value: aws.String("payments")}},
+			want: false,
+		},
+		"DuplicateKeyInAWS": {
+			reason: "Tags should not be up to date if a key differing only in case replaces one of the spec, even if the number of tags matches.",
+			in:     v1alpha1.TopicParameters{Tags: map[string]string{"env": "prod", "team": "payments"}},
+			attributes: map[string]string{
+				v1alpha1.FifoTopic:                          "false",
+				v1alpha1.FifoTopicContentBasedDeduplication: "false",
+			},
+			tags: []types.Tag{{Key: aws.String("env"), Value: aws.String("prod")}, {Key: aws.String("ENV"), Value: aws.String("prod")}},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsUpToDate(tc.in, tc.attributes, tc.tags)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsUpToDate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTopicARN(t *testing.T) {
+	type args struct {
+		region    string
+		accountID string
+		name      string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"Commercial": {
+			reason: "Topics in commercial regions should use the aws partition.",
+			args:   args{region: "us-west-2", accountID: "123456789012", name: "example"},
+			want:   "arn:aws:sns:us-west-2:123456789012:example",
+		},
+		"China": {
+			reason: "Topics in China regions should use the aws-cn partition.",
+			args:   args{region: "cn-north-1", accountID: "123456789012", name: "example"},
+			want:   "arn:aws-cn:sns:cn-north-1:123456789012:example",
+		},
+		"GovCloud": {
+			reason: "Topics in GovCloud regions should use the aws-us-gov partition.",
+			args:   args{region: "us-gov-west-1", accountID: "123456789012", name: "example.fifo"},
+			want:   "arn:aws-us-gov:sns:us-gov-west-1:123456789012:example.fifo",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := TopicARN(tc.args.region, tc.args.accountID, tc.args.name)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nTopicARN(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCheckPartition(t *testing.T) {
+	type args struct {
+		region string
+		arn    string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"Match": {
+			reason: "An ARN in the partition of the region should pass.",
+			args:   args{region: "cn-north-1", arn: "arn:aws-cn:sns:cn-north-1:123456789012:example"},
+		},
+		"Mismatch": {
+			reason: "An ARN in another partition than that of the region should fail.",
+			args:   args{region: "us-west-2", arn: "arn:aws-cn:sns:us-west-2:123456789012:example"},
+			want:   true,
+		},
+		"NotAnARN": {
+			reason: "A value that isn't an ARN shouldn't be checked.",
+			args:   args{region: "us-west-2", arn: "example"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := CheckPartition(tc.args.region, tc.args.arn)
+			if diff := cmp.Diff(tc.want, err != nil); diff != "" {
+				t.Errorf("\n%s\nCheckPartition(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCheckARNUnchanged(t *testing.T) {
+	const topicArn = "arn:aws:sns:us-west-2:123456789012:example"
+	adopt := v1alpha1.ARNChangePolicyAdopt
+	refuse := v1alpha1.ARNChangePolicyRefuse
+
+	type args struct {
+		p        v1alpha1.TopicParameters
+		observed *string
+		topicArn string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"NeverObserved": {
+			reason: "A Topic that was never observed has no ARN to change.",
+			args:   args{p: v1alpha1.TopicParameters{Region: "us-west-2"}, topicArn: topicArn},
+		},
+		"Unchanged": {
+			reason: "A Topic that implies the ARN it was observed with should pass.",
+			args:   args{p: v1alpha1.TopicParameters{Region: "us-west-2"}, observed: aws.String(topicArn), topicArn: topicArn},
+		},
+		"Changed": {
+			reason: "A Topic that implies another ARN than it was observed with should fail.",
+			args:   args{p: v1alpha1.TopicParameters{Region: "us-west-2"}, observed: aws.String(topicArn), topicArn: "arn:aws:sns:us-west-2:123456789012:other"},
+			want:   true,
+		},
+		"ChangedRefused": {
+			reason: "A Topic whose ARN change policy is Refuse should fail if it implies another ARN.",
+			args:   args{p: v1alpha1.TopicParameters{Region: "us-west-2", ARNChangePolicy: &refuse}, observed: aws.String(topicArn), topicArn: "arn:aws:sns:us-west-2:123456789012:other"},
+			want:   true,
+		},
+		"ChangedAdopted": {
+			reason: "A Topic whose ARN change policy is Adopt should pass if it implies another ARN.",
+			args:   args{p: v1alpha1.TopicParameters{Region: "us-west-2", ARNChangePolicy: &adopt}, observed: aws.String(topicArn), topicArn: "arn:aws:sns:us-west-2:123456789012:other"},
+		},
+		"RegionChanged": {
+			reason: "A Topic whose region isn't that of its ARN should fail, regardless of its ARN change policy.",
+			args:   args{p: v1alpha1.TopicParameters{Region: "eu-west-1", ARNChangePolicy: &adopt}, observed: aws.String(topicArn), topicArn: topicArn},
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := CheckARNUnchanged(tc.args.p, tc.args.observed, tc.args.topicArn)
+			if diff := cmp.Diff(tc.want, err != nil); diff != "" {
+				t.Errorf("\n%s\nCheckARNUnchanged(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSharedWith(t *testing.T) {
+	const topicArn = "arn:aws:sns:us-west-2:123456789012:example"
+	topic := func(name, externalName, region, providerConfig string) v1alpha1.Topic {
+		t := v1alpha1.Topic{Spec: v1alpha1.TopicSpec{ForProvider: v1alpha1.TopicParameters{Region: region}}}
+		t.SetName(name)
+		meta.SetExternalName(&t, externalName)
+		if providerConfig != "" {
+			t.SetProviderConfigReference(&xpv1.Reference{Name: providerConfig})
+		}
+		return t
+	}
+	cr := topic("example", topicArn, "us-west-2", "")
+
+	cases := map[string]struct {
+		reason string
+		topics []v1alpha1.Topic
+		want   []string
+	}{
+		"Self": {
+			reason: "A Topic shouldn't be reported as sharing its topic with itself.",
+			topics: []v1alpha1.Topic{cr},
+		},
+		"SameARN": {
+			reason: "Topics whose external name is the same ARN should be reported.",
+			topics: []v1alpha1.Topic{cr, topic("b", topicArn, "us-west-2", ""), topic("a", topicArn, "us-west-2", "")},
+			want:   []string{"a", "b"},
+		},
+		"SameName": {
+			reason: "Topics that aren't created yet but will be under the same name, region and ProviderConfig should be reported.",
+			topics: []v1alpha1.Topic{topic("other", "example", "us-west-2", "default")},
+			want:   []string{"other"},
+		},
+		"OtherRegion": {
+			reason: "Topics of the same name in another region address another topic.",
+			topics: []v1alpha1.Topic{topic("other", "example", "us-east-1", "")},
+		},
+		"OtherProviderConfig": {
+			reason: "Topics of the same name that use another ProviderConfig may address a topic in another account.",
+			topics: []v1alpha1.Topic{topic("other", "example", "us-west-2", "other-account")},
+		},
+		"OtherARN": {
+			reason: "Topics with another ARN address another topic.",
+			topics: []v1alpha1.Topic{topic("other", "arn:aws:sns:us-west-2:210987654321:example", "us-west-2", "")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := SharedWith(&cr, topicArn, tc.topics)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSharedWith(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+
+			// Only the Topics found by their index values are checked by
+			// the controller, which must include every shared one.
+			values := map[string]bool{}
+			for _, v := range SharedIndexValues(&cr, topicArn) {
+				values[v] = true
+			}
+			var indexed []v1alpha1.Topic
+			for i := range tc.topics {
+				for _, v := range TopicIndexValues(&tc.topics[i]) {
+					if values[v] {
+						indexed = append(indexed, tc.topics[i])
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.want, SharedWith(&cr, topicArn, indexed)); diff != "" {
+				t.Errorf("\n%s\nSharedWith(...): -want indexed, +got indexed:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   bool
+	}{
+		"NotFound": {
+			reason: "The NotFound code should be recognised.",
+			err:    &smithy.GenericAPIError{Code: TopicNotFound},
+			want:   true,
+		},
+		"NotFoundException": {
+			reason: "The NotFoundException code should be recognised.",
+			err:    &smithy.GenericAPIError{Code: NotFoundException},
+			want:   true,
+		},
+		"ResourceNotFoundException": {
+			reason: "The ResourceNotFoundException code should be recognised.",
+			err:    &smithy.GenericAPIError{Code: ResourceNotFoundException},
+			want:   true,
+		},
+		"Wrapped": {
+			reason: "A wrapped not found error should be recognised.",
+			err:    errors.Wrap(&smithy.GenericAPIError{Code: NotFoundException}, "cannot get Topic attributes"),
+			want:   true,
+		},
+		"OtherCode": {
+			reason: "Other API error codes should not be treated as not found.",
+			err:    &smithy.GenericAPIError{Code: "AuthorizationError"},
+		},
+		"NotAPIError": {
+			reason: "Errors that aren't API errors should not be treated as not found.",
+			err:    errors.New("boom"),
+		},
+		"Nil": {
+			reason: "A nil error should not be treated as not found.",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsNotFound(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsNotFound(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   bool
+	}{
+		"Throttled": {
+			reason: "The Throttled code should be recognised.",
+			err:    &smithy.GenericAPIError{Code: Throttled},
+			want:   true,
+		},
+		"ThrottlingException": {
+			reason: "The ThrottlingException code should be recognised.",
+			err:    &smithy.GenericAPIError{Code: ThrottlingException},
+			want:   true,
+		},
+		"ConcurrentAccess": {
+			reason: "The ConcurrentAccess code should be recognised.",
+			err:    &smithy.GenericAPIError{Code: ConcurrentAccess},
+			want:   true,
+		},
+		"Wrapped": {
+			reason: "A wrapped throttling error should be recognised.",
+			err:    errors.Wrap(&smithy.GenericAPIError{Code: Throttled}, "cannot set Topic attributes"),
+			want:   true,
+		},
+		"OtherCode": {
+			reason: "Other API error codes should not be treated as transient.",
+			err:    &smithy.GenericAPIError{Code: "InvalidParameter"},
+		},
+		"NotAPIError": {
+			reason: "Errors that aren't API errors should not be treated as transient.",
+			err:    errors.New("boom"),
+		},
+		"Nil": {
+			reason: "A nil error should not be treated as transient.",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsTransient(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsTransient(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDefaultTags(t *testing.T) {
+	defaults := map[string]string{"team": "payments", "env": "dev"}
+
+	type want struct {
+		upToDate   bool
+		addTags    []types.Tag
+		removeTags []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		in     v1alpha1.TopicParameters
+		tags   []types.Tag
+		want   want
+	}{
+		"DefaultsApplied": {
+			reason: "A Topic tagged with the default tags should be up to date.",
+			tags: []types.Tag{
+				{Key: aws.String("team"), Value: aws.String("payments")},
+				{Key: aws.String("env"), Value: aws.String("dev")},
+			},
+			want: want{upToDate: true},
+		},
+		"ResourceTagWins": {
+			reason: "A resource tag should override the default tag with the same key.",
+			in:     v1alpha1.TopicParameters{Tags: map[string]string{"env": "prod"}},
+			tags: []types.Tag{
+				{Key: aws.String("team"), Value: aws.String("payments")},
+				{Key: aws.String("env"), Value: aws.String("dev")},
+			},
+			want: want{
+				addTags:    []types.Tag{{Key: aws.String("env"), Value: aws.String("prod")}},
+				removeTags: []string{"env"},
+			},
+		},
+		"DefaultsMissing": {
+			reason: "Default tags missing from the Topic should be added.",
+			tags:   []types.Tag{{Key: aws.String("team"), Value: aws.String("payments")}},
+			want: want{
+				addTags: []types.Tag{{Key: aws.String("env"), Value: aws.String("dev")}},
+			},
+		},
+	}
+
+	attributes := map[string]string{
+		v1alpha1.FifoTopic:                          "false",
+		v1alpha1.FifoTopicContentBasedDeduplication: "false",
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := WithDefaultTags(tc.in, defaults)
+			if diff := cmp.Diff(tc.want.upToDate, IsUpToDate(p, attributes, tc.tags)); diff != "" {
+				t.Errorf("\n%s\nIsUpToDate(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			add, remove := GetDiffTags(p, tc.tags)
+			if diff := cmp.Diff(tc.want.addTags, add, cmpopts.IgnoreUnexported(types.Tag{})); diff != "" {
+				t.Errorf("\n%s\nGetDiffTags(...): -want add, +got add:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.removeTags, remove); diff != "" {
+				t.Errorf("\n%s\nGetDiffTags(...): -want remove, +got remove:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWithoutDefaultTags(t *testing.T) {
+	defaults := map[string]string{"team": "payments"}
+	tags := []types.Tag{
+		{Key: aws.String("team"), Value: aws.String("payments")},
+		{Key: aws.String("app"), Value: aws.String("orders")},
+	}
+	want := []types.Tag{{Key: aws.String("app"), Value: aws.String("orders")}}
+
+	got := WithoutDefaultTags(tags, defaults)
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(types.Tag{})); diff != "" {
+		t.Errorf("\nTags matching a default tag should be dropped.\nWithoutDefaultTags(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestLateInitialize(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		attributes map[string]string
+		want       v1alpha1.TopicParameters
+	}{
+		"Unset": {
+			reason: "Attributes SNS doesn't report should leave the parameters nil.",
+			attributes: map[string]string{
+				v1alpha1.TopicDisplayName:    "",
+				v1alpha1.TopicDeliveryPolicy: "",
+			},
+		},
+		"Set": {
+			reason: "Attributes SNS reports should be late initialized.",
+			attributes: map[string]string{
+				v1alpha1.TopicDisplayName:      "example",
+				v1alpha1.TopicKMSMasterKeyID:   "alias/aws/sns",
+				v1alpha1.FifoTopic:             "false",
+				v1alpha1.TopicSignatureVersion: "1",
+				v1alpha1.TopicTracingConfig:    "PassThrough",
+				v1alpha1.TopicArchivePolicy:    `{"MessageRetentionPeriod":"30"}`,
+			},
+			want: v1alpha1.TopicParameters{
+				DisplayName:      aws.String("example"),
+				KMSMasterKeyID:   aws.String("alias/aws/sns"),
+				FifoTopic:        aws.Bool(false),
+				SignatureVersion: aws.String("1"),
+				TracingConfig:    aws.String("PassThrough"),
+				ArchivePolicy:    aws.String(`{"MessageRetentionPeriod":"30"}`),
+			},
+		},
+		"DeliveryStatusLogging": {
+			reason: "The delivery status logging of the protocols SNS reports it for should be late initialized.",
+			attributes: map[string]string{
+				"FirehoseSuccessFeedbackRoleArn":    loggingRole,
+				"FirehoseSuccessFeedbackSampleRate": "5",
+				"FirehoseFailureFeedbackRoleArn":    loggingRole,
+			},
+			want: v1alpha1.TopicParameters{DeliveryStatusLogging: &v1alpha1.DeliveryStatusLogging{
+				Firehose: &v1alpha1.DeliveryStatusLoggingConfig{
+					SuccessFeedbackRoleARN:    aws.String(loggingRole),
+					SuccessFeedbackSampleRate: aws.Int32(5),
+					FailureFeedbackRoleARN:    aws.String(loggingRole),
+				},
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := v1alpha1.TopicParameters{}
+			LateInitialize(&got, tc.attributes, nil)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nLateInitialize(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// subscriptionPages returns the supplied pages of subscriptions in order.
+type subscriptionPages struct {
+	Client
+	pages [][]types.Subscription
+	err   error
+}
+
+func (c *subscriptionPages) ListSubscriptionsByTopic(_ context.Context, in *awssns.ListSubscriptionsByTopicInput, _ ...func(*awssns.Options)) (*awssns.ListSubscriptionsByTopicOutput, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	page := 0
+	if in.NextToken != nil {
+		page, _ = strconv.Atoi(*in.NextToken)
+	}
+	out := &awssns.ListSubscriptionsByTopicOutput{Subscriptions: c.pages[page]}
+	if page+1 < len(c.pages) {
+		out.NextToken = aws.String(strconv.Itoa(page + 1))
+	}
+	return out, nil
+}
+
+func TestCountSubscriptionsByProtocol(t *testing.T) {
+	errBoom := errors.New("boom")
+	sub := func(protocol string) types.Subscription {
+		return types.Subscription{Protocol: aws.String(protocol)}
+	}
+
+	type want struct {
+		counts map[string]int
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      *subscriptionPages
+		want   want
+	}{
+		"MixedProtocols": {
+			reason: "Subscriptions on every page should be counted by protocol.",
+			c: &subscriptionPages{pages: [][]types.Subscription{
+				{sub("sqs"), sub("https"), sub("sqs")},
+				{sub("lambda"), sub("sqs"), sub("email")},
+			}},
+			want: want{counts: map[string]int{"sqs": 3, "https": 1, "lambda": 1, "email": 1}},
+		},
+		"NoSubscriptions": {
+			reason: "A topic without subscriptions should have no counts.",
+			c:      &subscriptionPages{pages: [][]types.Subscription{{}}},
+			want:   want{counts: map[string]int{}},
+		},
+		"ListFailed": {
+			reason: "Errors listing subscriptions should be returned.",
+			c:      &subscriptionPages{err: errBoom},
+			want:   want{err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := CountSubscriptionsByProtocol(context.Background(), tc.c, "arn:aws:sns:us-west-2:123456789012:example")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCountSubscriptionsByProtocol(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.counts, got); diff != "" {
+				t.Errorf("\n%s\nCountSubscriptionsByProtocol(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGenerateCostTags(t *testing.T) {
+	tags := []types.Tag{
+		{Key: aws.String("cost-center"), Value: aws.String("1234")},
+		{Key: aws.String("app"), Value: aws.String("orders")},
+	}
+
+	cases := map[string]struct {
+		reason      string
+		recommended []string
+		want        *v1alpha1.CostTagsObservation
+	}{
+		"NoneRecommended": {
+			reason: "Nothing should be reported if no cost tags are recommended.",
+		},
+		"MissingTags": {
+			reason:      "Recommended cost tags the topic doesn't have should be reported as missing.",
+			recommended: []string{"team", "cost-center", "environment"},
+			want: &v1alpha1.CostTagsObservation{
+				Present: map[string]string{"cost-center": "1234"},
+				Missing: []string{"environment", "team"},
+			},
+		},
+		"AllPresent": {
+			reason:      "A topic with every recommended cost tag should have none missing.",
+			recommended: []string{"cost-center"},
+			want: &v1alpha1.CostTagsObservation{
+				Present: map[string]string{"cost-center": "1234"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GenerateCostTags(tc.recommended, tags)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGenerateCostTags(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCheckAttributesComplete(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		attributes map[string]string
+		want       error
+	}{
+		"Complete": {
+			reason:     "Attributes with every key SNS always returns should be accepted.",
+			attributes: map[string]string{v1alpha1.TopicArn: "arn:aws:sns:us-east-1:123456789012:example", v1alpha1.TopicOwner: "123456789012"},
+		},
+		"MissingOwner": {
+			reason:     "Attributes without an Owner should be reported as incomplete.",
+			attributes: map[string]string{v1alpha1.TopicArn: "arn:aws:sns:us-east-1:123456789012:example"},
+			want:       fmt.Errorf("SNS returned incomplete topic attributes, missing Owner"),
+		},
+		"Empty": {
+			reason: "An empty attribute map should be reported as incomplete.",
+			want:   fmt.Errorf("SNS returned incomplete topic attributes, missing TopicArn, Owner"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := CheckAttributesComplete(tc.attributes)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCheckAttributesComplete(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGenerateObservation(t *testing.T) {
+	topicArn := "arn:aws:sns:eu-west-1:123456789012:orders.fifo"
+	cases := map[string]struct {
+		reason       string
+		externalName string
+		attributes   map[string]string
+		want         v1alpha1.TopicObservation
+	}{
+		"TopicArnAttribute": {
+			reason:       "The ARN of the topic should be observed from its attributes.",
+			externalName: "arn:aws:sns:eu-west-1:123456789012:other",
+			attributes:   map[string]string{v1alpha1.TopicArn: topicArn},
+			want: v1alpha1.TopicObservation{
+				TopicArn:                aws.String(topicArn),
+				Name:                    aws.String("orders.fifo"),
+				BaseName:                aws.String("orders"),
+				EffectiveDeliveryPolicy: aws.String(""),
+			},
+		},
+		"ExternalNameFallback": {
+			reason:       "The external name should be observed as the ARN of the topic if its attributes lack one.",
+			externalName: topicArn,
+			attributes:   map[string]string{v1alpha1.TopicArn: ""},
+			want: v1alpha1.TopicObservation{
+				TopicArn:                aws.String(topicArn),
+				Name:                    aws.String("orders.fifo"),
+				BaseName:                aws.String("orders"),
+				EffectiveDeliveryPolicy: aws.String(""),
+			},
+		},
+		"OwnerAndKMSMasterKey": {
+			reason:       "The owner of the topic and the KMS key it's encrypted with should be observed from its attributes.",
+			externalName: topicArn,
+			attributes: map[string]string{
+				v1alpha1.TopicArn:            topicArn,
+				v1alpha1.TopicOwner:          "123456789012",
+				v1alpha1.TopicKMSMasterKeyID: "alias/aws/sns",
+			},
+			want: v1alpha1.TopicObservation{
+				TopicArn:                aws.String(topicArn),
+				Name:                    aws.String("orders.fifo"),
+				BaseName:                aws.String("orders"),
+				Owner:                   aws.String("123456789012"),
+				KMSMasterKeyID:          aws.String("alias/aws/sns"),
+				UsesCustomerManagedKey:  aws.Bool(false),
+				EffectiveDeliveryPolicy: aws.String(""),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GenerateObservation(tc.externalName, tc.attributes)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGenerateObservation(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			cr := v1alpha1.Topic{
+				Spec:   v1alpha1.TopicSpec{ForProvider: v1alpha1.TopicParameters{Region: "eu-west-1"}},
+				Status: v1alpha1.TopicStatus{AtProvider: got},
+			}
+			if GetConnectionDetails(cr) == nil {
+				t.Errorf("\n%s\nGetConnectionDetails(...): the observed Topic should have connection details", tc.reason)
+			}
+		})
+	}
+}
+
+func TestGetConnectionDetails(t *testing.T) {
+	topicArn := "arn:aws:sns:eu-west-1:123456789012:orders.fifo"
+	cases := map[string]struct {
+		reason string
+		in     v1alpha1.Topic
+		want   managed.ConnectionDetails
+	}{
+		"NotObserved": {
+			reason: "A Topic without an observed ARN should have no connection details.",
+			in:     v1alpha1.Topic{Spec: v1alpha1.TopicSpec{ForProvider: v1alpha1.TopicParameters{Region: "eu-west-1"}}},
+		},
+		"Observed": {
+			reason: "The ARN, topic name and region of an observed Topic should be published.",
+			in: v1alpha1.Topic{
+				Spec: v1alpha1.TopicSpec{ForProvider: v1alpha1.TopicParameters{Region: "eu-west-1"}},
+				Status: v1alpha1.TopicStatus{AtProvider: v1alpha1.TopicObservation{
+					TopicArn: aws.String(topicArn),
+					Name:     aws.String("orders.fifo"),
+					BaseName: aws.String("orders"),
+				}},
+			},
+			want: managed.ConnectionDetails{
+				xpv1.ResourceCredentialsSecretEndpointKey: []byte(topicArn),
+				ConnectionDetailsKeyTopicName:             []byte("orders.fifo"),
+				ConnectionDetailsKeyRegion:                []byte("eu-west-1"),
+				ConnectionDetailsKeyName:                  []byte("orders.fifo"),
+				ConnectionDetailsKeyBaseName:              []byte("orders"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := GetConnectionDetails(tc.in)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetConnectionDetails(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveTags(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2021, time.October, 1, 12, 0, 0, 0, time.UTC))
+	now := time.Date(2021, time.October, 2, 12, 0, 0, 0, time.UTC)
+	observedNow := metav1.NewTime(now)
+
+	cases := map[string]struct {
+		reason   string
+		previous v1alpha1.TopicObservation
+		tags     []types.Tag
+		want     v1alpha1.TopicObservation
+	}{
+		"FirstObservation": {
+			reason: "Tags observed for the first time should be observed now.",
+			tags:   []types.Tag{{Key: aws.String("team"), Value: aws.String("orders")}},
+			want: v1alpha1.TopicObservation{
+				Tags:           map[string]string{"team": "orders"},
+				TagsObservedAt: &observedNow,
+			},
+		},
+		"Unchanged": {
+			reason: "Tags that haven't changed should keep the time they were observed at.",
+			previous: v1alpha1.TopicObservation{
+				Tags:           map[string]string{"team": "orders"},
+				TagsObservedAt: &earlier,
+			},
+			tags: []types.Tag{{Key: aws.String("team"), Value: aws.String("orders")}},
+			want: v1alpha1.TopicObservation{
+				Tags:           map[string]string{"team": "orders"},
+				TagsObservedAt: &earlier,
+			},
+		},
+		"UnchangedNoTags": {
+			reason: "A topic that still has no tags should keep the time its tags were observed at.",
+			previous: v1alpha1.TopicObservation{
+				TagsObservedAt: &earlier,
+			},
+			want: v1alpha1.TopicObservation{
+				TagsObservedAt: &earlier,
+			},
+		},
+		"ValueChanged": {
+			reason: "A changed tag value should update the time the tags were observed at.",
+			previous: v1alpha1.TopicObservation{
+				Tags:           map[string]string{"team": "orders"},
+				TagsObservedAt: &earlier,
+			},
+			tags: []types.Tag{{Key: aws.String("team"), Value: aws.String("payments")}},
+			want: v1alpha1.TopicObservation{
+				Tags:           map[string]string{"team": "payments"},
+				TagsObservedAt: &observedNow,
+			},
+		},
+		"Removed": {
+			reason: "Removed tags should update the time the tags were observed at.",
+			previous: v1alpha1.TopicObservation{
+				Tags:           map[string]string{"team": "orders"},
+				TagsObservedAt: &earlier,
+			},
+			want: v1alpha1.TopicObservation{
+				TagsObservedAt: &observedNow,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := v1alpha1.TopicObservation{}
+			ObserveTags(&got, tc.previous, tc.tags, now)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nObserveTags(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParseEffectiveDeliveryPolicy(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		policy string
+		want   *v1alpha1.DeliveryPolicyObservation
+	}{
+		"Empty": {
+			reason: "An empty effective delivery policy should not be observed.",
+		},
+		"Invalid": {
+			reason: "An effective delivery policy that can't be parsed should not be observed.",
+			policy: "{",
+		},
+		"NoHTTP": {
+			reason: "An effective delivery policy without an http section should not be observed.",
+			policy: `{}`,
+		},
+		"Default": {
+			reason: "The retry behaviour of the effective delivery policy should be observed.",
+			policy: `{"http":{"defaultHealthyRetryPolicy":{"minDelayTarget":20,"maxDelayTarget":20,"numRetries":3,"numMaxDelayRetries":0,"numNoDelayRetries":0,"numMinDelayRetries":0,"backoffFunction":"linear"},"disableSubscriptionOverrides":false,"defaultRequestPolicy":{"headerContentType":"text/plain; charset=UTF-8"}}}`,
+			want: &v1alpha1.DeliveryPolicyObservation{
+				HealthyRetryPolicy: &v1alpha1.RetryPolicyObservation{
+					NumRetries:         aws.Int(3),
+					NumNoDelayRetries:  aws.Int(0),
+					NumMinDelayRetries: aws.Int(0),
+					NumMaxDelayRetries: aws.Int(0),
+					MinDelayTarget:     aws.Int(20),
+					MaxDelayTarget:     aws.Int(20),
+					BackoffFunction:    aws.String("linear"),
+				},
+				DisableSubscriptionOverrides: aws.Bool(false),
+			},
+		},
+		"Throttled": {
+			reason: "The throttle policy of the effective delivery policy should be observed.",
+			policy: `{"http":{"defaultThrottlePolicy":{"maxReceivesPerSecond":10}}}`,
+			want:   &v1alpha1.DeliveryPolicyObservation{MaxReceivesPerSecond: aws.Int(10)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ParseEffectiveDeliveryPolicy(tc.policy)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nParseEffectiveDeliveryPolicy(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUsesCustomerManagedKey(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		keyID  string
+		want   *bool
+	}{
+		"Unencrypted": {
+			reason: "Whether an unencrypted topic uses a customer managed key should not be observed.",
+		},
+		"AWSManagedAlias": {
+			reason: "The alias of the AWS managed key of SNS should not be reported as a customer managed key.",
+			keyID:  "alias/aws/sns",
+			want:   aws.Bool(false),
+		},
+		"AWSManagedAliasARN": {
+			reason: "The alias ARN of the AWS managed key of SNS should not be reported as a customer managed key.",
+			keyID:  "arn:aws:kms:us-west-2:123456789012:alias/aws/sns",
+			want:   aws.Bool(false),
+		},
+		"CustomerManagedKeyARN": {
+			reason: "A key ARN should be reported as a customer managed key.",
+			keyID:  "arn:aws:kms:us-west-2:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			want:   aws.Bool(true),
+		},
+		"CustomerManagedAlias": {
+			reason: "An alias of a customer managed key should be reported as a customer managed key.",
+			keyID:  "alias/orders",
+			want:   aws.Bool(true),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := UsesCustomerManagedKey(tc.keyID)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nUsesCustomerManagedKey(%q): -want, +got:\n%s\n", tc.reason, tc.keyID, diff)
+			}
+		})
+	}
+}
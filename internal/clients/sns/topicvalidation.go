@@ -0,0 +1,191 @@
+package sns
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/pkg/errors"
+
+	"provider-aws-controlapi/apis/sns/v1alpha1"
+)
+
+// Limits SNS places on topics.
+const (
+	maxTopicTags        = 50
+	maxTopicTagKeyLen   = 128
+	maxTopicTagValueLen = 256
+	fifoTopicSuffix     = ".fifo"
+	maxSampleRate       = 100
+)
+
+var (
+	topicNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+	kmsKeyIDRegexp  = regexp.MustCompile(`^([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}|mrk-[0-9a-f]{32})$`)
+	kmsAliasRegexp  = regexp.MustCompile(`^alias/[A-Za-z0-9/_-]+$`)
+)
+
+// ValidateTopic checks the supplied Topic parameters against the constraints
+// SNS enforces, without calling AWS. The name is the name the topic is
+// created with. Every problem found is returned so that they can all be fixed
+// at once.
+func ValidateTopic(name string, p v1alpha1.TopicParameters) []error {
+	var errs []error
+	if err := validateTopicName(name, aws.ToBool(p.FifoTopic)); err != nil {
+		errs = append(errs, err)
+	}
+	if aws.ToBool(p.ContentBasedDeduplication) && !aws.ToBool(p.FifoTopic) {
+		errs = append(errs, errors.New("contentBasedDeduplication can only be enabled for FIFO topics"))
+	}
+	if p.Policy != nil {
+		if err := validatePolicy(aws.ToString(p.Policy)); err != nil {
+			errs = append(errs, errors.Wrap(err, "invalid policy"))
+		}
+	}
+	if p.DeliveryPolicy != nil {
+		if err := validateJSONObject(aws.ToString(p.DeliveryPolicy)); err != nil {
+			errs = append(errs, errors.Wrap(err, "invalid deliveryPolicy"))
+		}
+	}
+	if aws.ToString(p.ArchivePolicy) != "" {
+		if !aws.ToBool(p.FifoTopic) {
+			errs = append(errs, errors.New("archivePolicy can only be set for FIFO topics"))
+		}
+		if err := validateJSONObject(aws.ToString(p.ArchivePolicy)); err != nil {
+			errs = append(errs, errors.Wrap(err, "invalid archivePolicy"))
+		}
+	}
+	if p.KMSMasterKeyID != nil {
+		if err := validateKMSKeyID(aws.ToString(p.KMSMasterKeyID)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := validateEnum("signatureVersion", p.SignatureVersion, v1alpha1.SignatureVersion1, v1alpha1.SignatureVersion2); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateEnum("tracingConfig", p.TracingConfig, v1alpha1.TracingConfigPassThrough, v1alpha1.TracingConfigActive); err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, validateDeliveryStatusLogging(p.DeliveryStatusLogging)...)
+	return append(errs, validateTopicTags(p.Tags)...)
+}
+
+func validateTopicName(name string, fifo bool) error {
+	base := strings.TrimSuffix(name, fifoTopicSuffix)
+	switch {
+	case !topicNameRegexp.MatchString(base):
+		return errors.Errorf("topic name %q must be 1 to 256 alphanumeric characters, hyphens or underscores", name)
+	case fifo && base == name:
+		return errors.Errorf("FIFO topic name %q must end with %q", name, fifoTopicSuffix)
+	case !fifo && base != name:
+		return errors.Errorf("topic name %q ends with %q but fifoTopic is not enabled", name, fifoTopicSuffix)
+	}
+	return nil
+}
+
+// CheckNamePattern returns an error if the supplied topic name doesn't match
+// the supplied naming convention, a regular expression. Every name matches
+// an empty pattern.
+func CheckNamePattern(name, pattern string) error {
+	if pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return errors.Wrapf(err, "cannot parse the name pattern %q of the ProviderConfig", pattern)
+	}
+	if !re.MatchString(name) {
+		return errors.Errorf("topic name %q does not match the name pattern %q of the ProviderConfig", name, pattern)
+	}
+	return nil
+}
+
+func validateJSONObject(doc string) error {
+	m := map[string]interface{}{}
+	return errors.Wrap(json.Unmarshal([]byte(doc), &m), "must be a JSON object")
+}
+
+func validatePolicy(doc string) error {
+	m := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(doc), &m); err != nil {
+		return errors.Wrap(err, "must be a JSON object")
+	}
+	if _, ok := m[policyStatement]; !ok {
+		return errors.Errorf("must contain a %s", policyStatement)
+	}
+	return nil
+}
+
+func validateKMSKeyID(id string) error {
+	if kmsKeyIDRegexp.MatchString(id) || kmsAliasRegexp.MatchString(id) {
+		return nil
+	}
+	if a, err := arn.Parse(id); err == nil && a.Service == "kms" {
+		if strings.HasPrefix(a.Resource, "alias/") || kmsKeyIDRegexp.MatchString(strings.TrimPrefix(a.Resource, "key/")) {
+			return nil
+		}
+	}
+	return errors.Errorf("kmsMasterKeyId %q must be a key ID, key ARN, alias name or alias ARN", id)
+}
+
+// validateEnum returns an error if the supplied field is set to a value other
+// than the supplied allowed ones. An empty value leaves the attribute as it
+// is, so it's allowed too.
+func validateEnum(field string, v *string, allowed ...string) error {
+	if v == nil || *v == "" {
+		return nil
+	}
+	for _, a := range allowed {
+		if *v == a {
+			return nil
+		}
+	}
+	return errors.Errorf("%s %q must be one of %s", field, *v, strings.Join(allowed, ", "))
+}
+
+func validateDeliveryStatusLogging(l *v1alpha1.DeliveryStatusLogging) []error {
+	if l == nil {
+		return nil
+	}
+	var errs []error
+	for _, p := range deliveryStatusProtocols {
+		c := *p.config(l)
+		if c == nil {
+			continue
+		}
+		path := "deliveryStatusLogging." + p.field
+		if r := c.SuccessFeedbackSampleRate; r != nil && (*r < 0 || *r > maxSampleRate) {
+			errs = append(errs, errors.Errorf("%s.successFeedbackSampleRate %d must be between 0 and %d", path, *r, maxSampleRate))
+		}
+		for field, role := range map[string]*string{"successFeedbackRoleArn": c.SuccessFeedbackRoleARN, "failureFeedbackRoleArn": c.FailureFeedbackRoleARN} {
+			if role == nil || *role == "" {
+				continue
+			}
+			if a, err := arn.Parse(*role); err != nil || a.Service != "iam" {
+				errs = append(errs, errors.Errorf("%s.%s %q must be the ARN of an IAM role", path, field, *role))
+			}
+		}
+	}
+	return errs
+}
+
+func validateTopicTags(tags map[string]string) []error {
+	var errs []error
+	if len(tags) > maxTopicTags {
+		errs = append(errs, errors.Errorf("a topic can have at most %d tags, got %d", maxTopicTags, len(tags)))
+	}
+	for k, v := range tags {
+		switch {
+		case len(k) == 0 || len(k) > maxTopicTagKeyLen:
+			errs = append(errs, errors.Errorf("tag key %q must be 1 to %d characters", k, maxTopicTagKeyLen))
+		case strings.HasPrefix(strings.ToLower(k), "aws:"):
+			errs = append(errs, errors.Errorf("tag key %q uses the reserved aws: prefix", k))
+		}
+		if len(v) > maxTopicTagValueLen {
+			errs = append(errs, errors.Errorf("value of tag %q must be at most %d characters", k, maxTopicTagValueLen))
+		}
+	}
+	return errs
+}
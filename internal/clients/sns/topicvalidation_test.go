@@ -0,0 +1,241 @@
+package sns
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+
+	"provider-aws-controlapi/apis/sns/v1alpha1"
+)
+
+func TestValidateTopic(t *testing.T) {
+	manyTags := map[string]string{}
+	for i := 0; i <= maxTopicTags; i++ {
+		manyTags[fmt.Sprintf("tag-%d", i)] = "v"
+	}
+
+	type args struct {
+		name string
+		p    v1alpha1.TopicParameters
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []error
+	}{
+		"Valid": {
+			reason: "A topic within every SNS limit should be valid.",
+			args: args{
+				name: "orders",
+				p: v1alpha1.TopicParameters{
+					Policy:           aws.String(`{"Version": "2012-10-17", "Statement": []}`),
+					DeliveryPolicy:   aws.String(`{"http": {"defaultHealthyRetryPolicy": {"numRetries": 3}}}`),
+					KMSMasterKeyID:   aws.String("alias/aws/sns"),
+					SignatureVersion: aws.String("2"),
+					TracingConfig:    aws.String("Active"),
+					Tags:             map[string]string{"team": "payments"},
+				},
+			},
+		},
+		"ValidFIFO": {
+			reason: "A FIFO topic with content based deduplication should be valid.",
+			args: args{
+				name: "orders.fifo",
+				p: v1alpha1.TopicParameters{
+					FifoTopic:                 aws.Bool(true),
+					ContentBasedDeduplication: aws.Bool(true),
+					KMSMasterKeyID:            aws.String("arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"),
+				},
+			},
+		},
+		"InvalidName": {
+			reason: "Topic names may only contain alphanumeric characters, hyphens and underscores.",
+			args: args{
+				name: "orders.v2",
+			},
+			want: []error{errors.New(`topic name "orders.v2" must be 1 to 256 alphanumeric characters, hyphens or underscores`)},
+		},
+		"FIFOWithoutSuffix": {
+			reason: "FIFO topic names must end with .fifo.",
+			args: args{
+				name: "orders",
+				p:    v1alpha1.TopicParameters{FifoTopic: aws.Bool(true)},
+			},
+			want: []error{errors.New(`FIFO topic name "orders" must end with ".fifo"`)},
+		},
+		"SuffixWithoutFIFO": {
+			reason: "Only FIFO topic names may end with .fifo.",
+			args: args{
+				name: "orders.fifo",
+			},
+			want: []error{errors.New(`topic name "orders.fifo" ends with ".fifo" but fifoTopic is not enabled`)},
+		},
+		"DeduplicationWithoutFIFO": {
+			reason: "Content based deduplication is only supported by FIFO topics.",
+			args: args{
+				name: "orders",
+				p:    v1alpha1.TopicParameters{ContentBasedDeduplication: aws.Bool(true)},
+			},
+			want: []error{errors.New("contentBasedDeduplication can only be enabled for FIFO topics")},
+		},
+		"InvalidPolicies": {
+			reason: "Policies must be JSON objects, and access policies must contain a Statement.",
+			args: args{
+				name: "orders",
+				p: v1alpha1.TopicParameters{
+					Policy:         aws.String(`{"Version": "2012-10-17"}`),
+					DeliveryPolicy: aws.String(`{"http": `),
+				},
+			},
+			want: []error{
+				errors.Wrap(errors.New("must contain a Statement"), "invalid policy"),
+				errors.Wrap(errors.Wrap(errors.New("unexpected end of JSON input"), "must be a JSON object"), "invalid deliveryPolicy"),
+			},
+		},
+		"InvalidKMSKey": {
+			reason: "The KMS master key must be a key ID, key ARN, alias name or alias ARN.",
+			args: args{
+				name: "orders",
+				p:    v1alpha1.TopicParameters{KMSMasterKeyID: aws.String("arn:aws:s3:::bucket")},
+			},
+			want: []error{errors.New(`kmsMasterKeyId "arn:aws:s3:::bucket" must be a key ID, key ARN, alias name or alias ARN`)},
+		},
+		"InvalidSignatureAndTracing": {
+			reason: "The signature version must be 1 or 2, and the tracing config PassThrough or Active.",
+			args: args{
+				name: "orders",
+				p: v1alpha1.TopicParameters{
+					SignatureVersion: aws.String("3"),
+					TracingConfig:    aws.String("active"),
+				},
+			},
+			want: []error{
+				errors.New(`signatureVersion "3" must be one of 1, 2`),
+				errors.New(`tracingConfig "active" must be one of PassThrough, Active`),
+			},
+		},
+		"ArchivePolicyOnFIFOTopic": {
+			reason: "FIFO topics should accept an archive policy.",
+			args: args{
+				name: "orders.fifo",
+				p: v1alpha1.TopicParameters{
+					FifoTopic:     aws.Bool(true),
+					ArchivePolicy: aws.String(`{"MessageRetentionPeriod": "30"}`),
+				},
+			},
+		},
+		"ArchivePolicyOnStandardTopic": {
+			reason: "Standard topics don't support archive policies, which must be JSON objects.",
+			args: args{
+				name: "orders",
+				p:    v1alpha1.TopicParameters{ArchivePolicy: aws.String(`"30"`)},
+			},
+			want: []error{
+				errors.New("archivePolicy can only be set for FIFO topics"),
+				errors.Wrap(errors.Wrap(errors.New("json: cannot unmarshal string into Go value of type map[string]interface {}"), "must be a JSON object"), "invalid archivePolicy"),
+			},
+		},
+		"InvalidDeliveryStatusLogging": {
+			reason: "Sample rates must be percentages and feedback roles must be IAM role ARNs.",
+			args: args{
+				name: "orders",
+				p: v1alpha1.TopicParameters{DeliveryStatusLogging: &v1alpha1.DeliveryStatusLogging{
+					HTTP: &v1alpha1.DeliveryStatusLoggingConfig{SuccessFeedbackSampleRate: aws.Int32(101)},
+					SQS: &v1alpha1.DeliveryStatusLoggingConfig{
+						SuccessFeedbackRoleARN:    aws.String("arn:aws:iam::123456789012:role/sns-logging"),
+						SuccessFeedbackSampleRate: aws.Int32(100),
+						FailureFeedbackRoleARN:    aws.String("sns-logging"),
+					},
+				}},
+			},
+			want: []error{
+				errors.New("deliveryStatusLogging.http.successFeedbackSampleRate 101 must be between 0 and 100"),
+				errors.New(`deliveryStatusLogging.sqs.failureFeedbackRoleArn "sns-logging" must be the ARN of an IAM role`),
+			},
+		},
+		"TooManyTags": {
+			reason: "A topic can have at most 50 tags.",
+			args: args{
+				name: "orders",
+				p:    v1alpha1.TopicParameters{Tags: manyTags},
+			},
+			want: []error{errors.New("a topic can have at most 50 tags, got 51")},
+		},
+		"InvalidTags": {
+			reason: "Tag keys must not use the aws: prefix and tag values must fit the SNS limits.",
+			args: args{
+				name: "orders",
+				p: v1alpha1.TopicParameters{Tags: map[string]string{
+					"aws:owner": "me",
+					"team":      strings.Repeat("a", maxTopicTagValueLen+1),
+				}},
+			},
+			want: []error{
+				errors.New(`tag key "aws:owner" uses the reserved aws: prefix`),
+				errors.New(`value of tag "team" must be at most 256 characters`),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValidateTopic(tc.args.name, tc.args.p)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors(), sortErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateTopic(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// sortErrors ignores the order of errors, which depends on map iteration.
+func sortErrors() cmp.Option {
+	return cmpopts.SortSlices(func(a, b error) bool { return a.Error() < b.Error() })
+}
+
+func TestCheckNamePattern(t *testing.T) {
+	type args struct {
+		name    string
+		pattern string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NoPattern": {
+			reason: "Any name should be accepted if there is no name pattern.",
+			args:   args{name: "anything"},
+		},
+		"Matches": {
+			reason: "A name matching the pattern should be accepted.",
+			args:   args{name: "team-payments-events", pattern: "^team-[a-z]+-.*"},
+		},
+		"DoesNotMatch": {
+			reason: "A name not matching the pattern should be rejected.",
+			args:   args{name: "events", pattern: "^team-[a-z]+-.*"},
+			want:   errors.Errorf("topic name %q does not match the name pattern %q of the ProviderConfig", "events", "^team-[a-z]+-.*"),
+		},
+		"InvalidPattern": {
+			reason: "A pattern that isn't a valid regular expression should be reported.",
+			args:   args{name: "events", pattern: "("},
+			want:   errors.Wrapf(&syntax.Error{Code: syntax.ErrMissingParen, Expr: "("}, "cannot parse the name pattern %q of the ProviderConfig", "("),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := CheckNamePattern(tc.args.name, tc.args.pattern)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCheckNamePattern(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
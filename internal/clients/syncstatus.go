@@ -0,0 +1,62 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AnnotationKeyLastSyncStatus is the annotation that records the outcome of
+// the last observation of a managed resource, so that external tooling can
+// select resources by sync state without parsing their conditions.
+const AnnotationKeyLastSyncStatus = "controlapi.aws/last-sync-status"
+
+// Values of the AnnotationKeyLastSyncStatus annotation.
+const (
+	LastSyncStatusSynced  = "synced"
+	LastSyncStatusDrifted = "drifted"
+	LastSyncStatusError   = "error"
+)
+
+const errRecordSyncStatus = "cannot record last sync status"
+
+// LastSyncStatus returns the sync status of an observation. An external
+// resource that doesn't exist yet is reported as drifted, since it doesn't
+// reflect the desired state.
+func LastSyncStatus(o managed.ExternalObservation, err error) string {
+	switch {
+	case err != nil:
+		return LastSyncStatusError
+	case !o.ResourceExists || !o.ResourceUpToDate:
+		return LastSyncStatusDrifted
+	default:
+		return LastSyncStatusSynced
+	}
+}
+
+// RecordLastSyncStatus sets the last sync status annotation of the supplied
+// managed resource to reflect the supplied observation. The annotation is
+// only patched when its value changes. A copy of the managed resource is
+// patched, so that the stored resource the API server returns doesn't
+// overwrite the status that was just observed; only its resource version is
+// kept, so that the status can still be updated.
+func RecordLastSyncStatus(ctx context.Context, kube client.Client, mg resource.Managed, o managed.ExternalObservation, err error) error {
+	s := LastSyncStatus(o, err)
+	if mg.GetAnnotations()[AnnotationKeyLastSyncStatus] == s {
+		return nil
+	}
+	patched := mg.DeepCopyObject().(client.Object)
+	p := client.MergeFrom(mg.DeepCopyObject().(client.Object))
+	meta.AddAnnotations(patched, map[string]string{AnnotationKeyLastSyncStatus: s})
+	meta.AddAnnotations(mg, map[string]string{AnnotationKeyLastSyncStatus: s})
+	if err := kube.Patch(ctx, patched, p); err != nil {
+		return errors.Wrap(resource.Ignore(kerrors.IsNotFound, err), errRecordSyncStatus)
+	}
+	mg.SetResourceVersion(patched.GetResourceVersion())
+	return nil
+}
@@ -0,0 +1,159 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	snsv1alpha1 "provider-aws-controlapi/apis/sns/v1alpha1"
+)
+
+func TestRecordLastSyncStatus(t *testing.T) {
+	errBoom := errors.New("boom")
+	errGone := kerrors.NewNotFound(schema.GroupResource{}, "example")
+
+	// stored returns the Topic as stored, before it was observed.
+	stored := func() *snsv1alpha1.Topic {
+		cr := &snsv1alpha1.Topic{}
+		cr.SetName("example")
+		cr.SetResourceVersion("1")
+		cr.SetAnnotations(map[string]string{"team": "orders", AnnotationKeyLastSyncStatus: LastSyncStatusDrifted})
+		return cr
+	}
+	// observed returns the Topic as just observed.
+	observed := func() *snsv1alpha1.Topic {
+		cr := stored()
+		cr.Status.AtProvider.TopicArn = aws.String("arn:aws:sns:us-west-2:123456789012:example")
+		cr.SetConditions(xpv1.Available())
+		return cr
+	}
+
+	type args struct {
+		o     managed.ExternalObservation
+		err   error
+		patch error
+	}
+	type want struct {
+		err     error
+		patch   string
+		mg      *snsv1alpha1.Topic
+		patched bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Unchanged": {
+			reason: "The annotation should not be patched if its value doesn't change.",
+			args: args{
+				o: managed.ExternalObservation{ResourceExists: false},
+			},
+			want: want{
+				mg: observed(),
+			},
+		},
+		"Changed": {
+			reason: "Only the annotation should be patched, and the stored Topic should not overwrite the observed status.",
+			args: args{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			},
+			want: want{
+				patch:   `{"metadata":{"annotations":{"controlapi.aws/last-sync-status":"synced"}}}`,
+				patched: true,
+				mg: func() *snsv1alpha1.Topic {
+					cr := observed()
+					cr.SetAnnotations(map[string]string{"team": "orders", AnnotationKeyLastSyncStatus: LastSyncStatusSynced})
+					cr.SetResourceVersion("2")
+					return cr
+				}(),
+			},
+		},
+		"NotFound": {
+			reason: "A Topic that no longer exists should not be an error.",
+			args: args{
+				err:   errBoom,
+				patch: errGone,
+			},
+			want: want{
+				patched: true,
+				patch:   `{"metadata":{"annotations":{"controlapi.aws/last-sync-status":"error"}}}`,
+				mg: func() *snsv1alpha1.Topic {
+					cr := observed()
+					cr.SetAnnotations(map[string]string{"team": "orders", AnnotationKeyLastSyncStatus: LastSyncStatusError})
+					return cr
+				}(),
+			},
+		},
+		"PatchFailed": {
+			reason: "Errors patching the annotation should be returned.",
+			args: args{
+				err:   errBoom,
+				patch: errBoom,
+			},
+			want: want{
+				err:     errors.Wrap(errBoom, errRecordSyncStatus),
+				patched: true,
+				patch:   `{"metadata":{"annotations":{"controlapi.aws/last-sync-status":"error"}}}`,
+				mg: func() *snsv1alpha1.Topic {
+					cr := observed()
+					cr.SetAnnotations(map[string]string{"team": "orders", AnnotationKeyLastSyncStatus: LastSyncStatusError})
+					return cr
+				}(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			patched := false
+			patch := ""
+			kube := &test.MockClient{
+				MockPatch: func(_ context.Context, obj client.Object, p client.Patch, _ ...client.PatchOption) error {
+					patched = true
+					data, err := p.Data(obj)
+					if err != nil {
+						return err
+					}
+					patch = string(data)
+					if tc.args.patch != nil {
+						return tc.args.patch
+					}
+					// Like the API server, return the stored Topic with
+					// the patch applied.
+					cr := stored()
+					cr.SetAnnotations(obj.GetAnnotations())
+					cr.SetResourceVersion("2")
+					cr.DeepCopyInto(obj.(*snsv1alpha1.Topic))
+					return nil
+				},
+			}
+			mg := observed()
+
+			err := RecordLastSyncStatus(context.Background(), kube, mg, tc.args.o, tc.args.err)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRecordLastSyncStatus(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.patched, patched); diff != "" {
+				t.Errorf("\n%s\nRecordLastSyncStatus(...): -want patched, +got patched:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.patch, patch); diff != "" {
+				t.Errorf("\n%s\nRecordLastSyncStatus(...): -want patch, +got patch:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.mg, mg, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\nRecordLastSyncStatus(...): -want managed resource, +got managed resource:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package aws
+
+// MergeTags returns the tags a resource should have given the default tags of
+// its ProviderConfig and its own tags. Tags of the resource take precedence
+// over default tags with the same key. Nil is returned if there are no tags.
+func MergeTags(defaults, tags map[string]string) map[string]string {
+	if len(defaults) == 0 && len(tags) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(defaults)+len(tags))
+	for k, v := range defaults {
+		out[k] = v
+	}
+	for k, v := range tags {
+		out[k] = v
+	}
+	return out
+}
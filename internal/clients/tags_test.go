@@ -0,0 +1,52 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMergeTags(t *testing.T) {
+	type args struct {
+		defaults map[string]string
+		tags     map[string]string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   map[string]string
+	}{
+		"NoTags": {
+			reason: "Nil should be returned when there are neither default nor resource tags.",
+			args:   args{defaults: map[string]string{}},
+		},
+		"DefaultsOnly": {
+			reason: "Default tags should apply to resources without tags.",
+			args:   args{defaults: map[string]string{"team": "payments"}},
+			want:   map[string]string{"team": "payments"},
+		},
+		"ResourceOnly": {
+			reason: "Resource tags should be kept when there are no default tags.",
+			args:   args{tags: map[string]string{"app": "orders"}},
+			want:   map[string]string{"app": "orders"},
+		},
+		"ResourceWins": {
+			reason: "Resource tags should take precedence over default tags with the same key.",
+			args: args{
+				defaults: map[string]string{"team": "payments", "env": "dev"},
+				tags:     map[string]string{"env": "prod", "app": "orders"},
+			},
+			want: map[string]string{"team": "payments", "env": "prod", "app": "orders"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := MergeTags(tc.args.defaults, tc.args.tags)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nMergeTags(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+
+	"provider-aws-controlapi/apis/v1beta1"
+)
+
+// SetRequestOptions applies the retry and timeout settings of the supplied
+// ProviderConfig to the supplied config.
+func SetRequestOptions(pc *v1beta1.ProviderConfig, cfg *aws.Config) *aws.Config {
+	if pc.Spec.MaxRetries != nil {
+		attempts := int(*pc.Spec.MaxRetries) + 1
+		cfg.Retryer = func() aws.Retryer {
+			return retry.AddWithMaxAttempts(retry.NewStandard(), attempts)
+		}
+	}
+	if d := RequestTimeout(pc); d > 0 {
+		hc, ok := cfg.HTTPClient.(*awshttp.BuildableClient)
+		if !ok {
+			hc = awshttp.NewBuildableClient()
+		}
+		cfg.HTTPClient = hc.WithTimeout(d)
+	}
+	return cfg
+}
+
+// RequestTimeout returns the request timeout of the supplied ProviderConfig,
+// or zero if requests aren't bounded.
+func RequestTimeout(pc *v1beta1.ProviderConfig) time.Duration {
+	if pc.Spec.RequestTimeout == nil {
+		return 0
+	}
+	return pc.Spec.RequestTimeout.Duration
+}
+
+// WithTimeout returns a child of the supplied context that is cancelled after
+// the supplied timeout. The context is returned as is if the timeout is zero.
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
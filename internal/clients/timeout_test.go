@@ -0,0 +1,86 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"provider-aws-controlapi/apis/v1beta1"
+)
+
+func TestSetRequestOptions(t *testing.T) {
+	type want struct {
+		maxAttempts int
+		httpClient  bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		spec   v1beta1.ProviderConfigSpec
+		want   want
+	}{
+		"NoOptions": {
+			reason: "The SDK defaults should be used when no options are set.",
+		},
+		"MaxRetries": {
+			reason: "The retryer should make one attempt more than the configured number of retries.",
+			spec:   v1beta1.ProviderConfigSpec{MaxRetries: aws.Int32(5)},
+			want:   want{maxAttempts: 6},
+		},
+		"NoRetries": {
+			reason: "A request should be attempted once if retries are disabled.",
+			spec:   v1beta1.ProviderConfigSpec{MaxRetries: aws.Int32(0)},
+			want:   want{maxAttempts: 1},
+		},
+		"RequestTimeout": {
+			reason: "An HTTP client with the configured timeout should be used.",
+			spec:   v1beta1.ProviderConfigSpec{RequestTimeout: &metav1.Duration{Duration: time.Minute}},
+			want:   want{httpClient: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cfg := SetRequestOptions(&v1beta1.ProviderConfig{Spec: tc.spec}, &aws.Config{})
+			got := want{httpClient: cfg.HTTPClient != nil}
+			if cfg.Retryer != nil {
+				got.maxAttempts = cfg.Retryer().MaxAttempts()
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nSetRequestOptions(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		timeout  time.Duration
+		deadline bool
+	}{
+		"NoTimeout": {
+			reason: "The context should have no deadline if no timeout is set.",
+		},
+		"Timeout": {
+			reason:   "The context should have a deadline if a timeout is set.",
+			timeout:  time.Minute,
+			deadline: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := WithTimeout(context.Background(), tc.timeout)
+			defer cancel()
+			_, got := ctx.Deadline()
+			if diff := cmp.Diff(tc.deadline, got); diff != "" {
+				t.Errorf("\n%s\nWithTimeout(...): -want deadline, +got deadline:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
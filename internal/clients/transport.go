@@ -0,0 +1,91 @@
+package aws
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"provider-aws-controlapi/apis/v1beta1"
+)
+
+const (
+	errParseHTTPSProxy = "cannot parse the HTTPS proxy URL"
+	errGetCABundle     = "cannot get the CA bundle"
+	errNoCABundle      = "the CA bundle source selects neither a Secret nor a ConfigMap"
+	errParseCABundle   = "the CA bundle contains no PEM encoded certificates"
+)
+
+// newHTTPClient returns the HTTP client AWS requests made with the supplied
+// ProviderConfig are sent with. It sends requests through the HTTPS proxy of
+// the ProviderConfig and trusts its CA bundle in addition to the system
+// certificate authorities. It returns nil if neither is configured, in which
+// case the default HTTP client of the SDK should be used.
+func newHTTPClient(ctx context.Context, c client.Client, pc *v1beta1.ProviderConfig) (*awshttp.BuildableClient, error) {
+	if pc.Spec.HTTPSProxy == nil && pc.Spec.CABundle == nil {
+		return nil, nil
+	}
+
+	var proxy *url.URL
+	if pc.Spec.HTTPSProxy != nil {
+		u, err := url.Parse(*pc.Spec.HTTPSProxy)
+		if err != nil {
+			return nil, errors.Wrap(err, errParseHTTPSProxy)
+		}
+		proxy = u
+	}
+
+	var roots *x509.CertPool
+	if pc.Spec.CABundle != nil {
+		bundle, err := getCABundle(ctx, c, pc.Spec.CABundle)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetCABundle)
+		}
+		if roots, err = x509.SystemCertPool(); err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(bundle) {
+			return nil, errors.New(errParseCABundle)
+		}
+	}
+
+	return awshttp.NewBuildableClient().WithTransportOptions(func(t *http.Transport) {
+		if proxy != nil {
+			t.Proxy = http.ProxyURL(proxy)
+		}
+		if roots != nil {
+			if t.TLSClientConfig == nil {
+				t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+			}
+			t.TLSClientConfig.RootCAs = roots
+		}
+	}), nil
+}
+
+// getCABundle reads the CA bundle from the Secret or ConfigMap key selected
+// by the supplied source.
+func getCABundle(ctx context.Context, c client.Client, s *v1beta1.CABundleSource) ([]byte, error) {
+	switch {
+	case s.SecretRef != nil:
+		sec := &corev1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: s.SecretRef.Namespace, Name: s.SecretRef.Name}, sec); err != nil {
+			return nil, err
+		}
+		return sec.Data[s.SecretRef.Key], nil
+	case s.ConfigMapRef != nil:
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: s.ConfigMapRef.Namespace, Name: s.ConfigMapRef.Name}, cm); err != nil {
+			return nil, err
+		}
+		return []byte(cm.Data[s.ConfigMapRef.Key]), nil
+	default:
+		return nil, errors.New(errNoCABundle)
+	}
+}
@@ -0,0 +1,163 @@
+package aws
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"provider-aws-controlapi/apis/v1beta1"
+)
+
+// newCA returns a self-signed CA certificate and its PEM encoding.
+func newCA(t *testing.T) (*x509.Certificate, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "private-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	errBoom := errors.New("boom")
+	ca, bundle := newCA(t)
+
+	getBundle := func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		if key.Namespace != "crossplane-system" || key.Name != "ca" {
+			return errors.Errorf("unexpected key %s", key)
+		}
+		switch o := obj.(type) {
+		case *corev1.Secret:
+			o.Data = map[string][]byte{"ca.crt": bundle}
+		case *corev1.ConfigMap:
+			o.Data = map[string]string{"ca.crt": string(bundle)}
+		}
+		return nil
+	}
+
+	type want struct {
+		client  bool
+		proxy   *url.URL
+		trusted bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		spec   v1beta1.ProviderConfigSpec
+		want   want
+	}{
+		"NotConfigured": {
+			reason: "The default HTTP client of the SDK should be used if neither a proxy nor a CA bundle is configured.",
+		},
+		"HTTPSProxy": {
+			reason: "Requests should be sent through the configured proxy.",
+			spec:   v1beta1.ProviderConfigSpec{HTTPSProxy: aws.String("http://proxy.example.com:3128")},
+			want:   want{client: true, proxy: &url.URL{Scheme: "http", Host: "proxy.example.com:3128"}},
+		},
+		"InvalidHTTPSProxy": {
+			reason: "An error should be returned if the proxy URL can't be parsed.",
+			spec:   v1beta1.ProviderConfigSpec{HTTPSProxy: aws.String("http://proxy example.com")},
+			want:   want{err: errors.Wrap(&url.Error{Op: "parse", URL: "http://proxy example.com", Err: url.InvalidHostError(" ")}, errParseHTTPSProxy)},
+		},
+		"CABundleSecret": {
+			reason: "The CA bundle read from a Secret should be trusted.",
+			kube:   &test.MockClient{MockGet: getBundle},
+			spec: v1beta1.ProviderConfigSpec{CABundle: &v1beta1.CABundleSource{SecretRef: &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "ca"},
+				Key:             "ca.crt",
+			}}},
+			want: want{client: true, trusted: true},
+		},
+		"CABundleConfigMap": {
+			reason: "The CA bundle read from a ConfigMap should be trusted.",
+			kube:   &test.MockClient{MockGet: getBundle},
+			spec: v1beta1.ProviderConfigSpec{CABundle: &v1beta1.CABundleSource{ConfigMapRef: &v1beta1.ConfigMapKeySelector{
+				Namespace: "crossplane-system",
+				Name:      "ca",
+				Key:       "ca.crt",
+			}}},
+			want: want{client: true, trusted: true},
+		},
+		"GetCABundleError": {
+			reason: "An error should be returned if the CA bundle can't be read.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			spec: v1beta1.ProviderConfigSpec{CABundle: &v1beta1.CABundleSource{ConfigMapRef: &v1beta1.ConfigMapKeySelector{
+				Namespace: "crossplane-system",
+				Name:      "ca",
+				Key:       "ca.crt",
+			}}},
+			want: want{err: errors.Wrap(errBoom, errGetCABundle)},
+		},
+		"NoCABundleSource": {
+			reason: "An error should be returned if the CA bundle source selects nothing.",
+			spec:   v1beta1.ProviderConfigSpec{CABundle: &v1beta1.CABundleSource{}},
+			want:   want{err: errors.Wrap(errors.New(errNoCABundle), errGetCABundle)},
+		},
+		"InvalidCABundle": {
+			reason: "An error should be returned if the CA bundle contains no certificates.",
+			kube:   &test.MockClient{MockGet: getBundle},
+			spec: v1beta1.ProviderConfigSpec{CABundle: &v1beta1.CABundleSource{SecretRef: &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{Namespace: "crossplane-system", Name: "ca"},
+				Key:             "missing",
+			}}},
+			want: want{err: errors.New(errParseCABundle)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			hc, err := newHTTPClient(context.Background(), tc.kube, &v1beta1.ProviderConfig{Spec: tc.spec})
+			got := want{client: hc != nil, err: err}
+			if hc != nil {
+				tr := hc.GetTransport()
+				req, _ := http.NewRequest(http.MethodPost, "https://sns.us-west-2.amazonaws.com", nil)
+				if tr.Proxy != nil {
+					got.proxy, _ = tr.Proxy(req)
+				}
+				if tr.TLSClientConfig != nil && tr.TLSClientConfig.RootCAs != nil {
+					_, err := ca.Verify(x509.VerifyOptions{Roots: tr.TLSClientConfig.RootCAs})
+					got.trusted = err == nil
+				}
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nnewHTTPClient(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
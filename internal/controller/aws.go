@@ -17,25 +17,77 @@ limitations under the License.
 package controller
 
 import (
-	"k8s.io/client-go/util/workqueue"
+	snsv1alpha1 "provider-aws-controlapi/apis/sns/v1alpha1"
+	"provider-aws-controlapi/apis/v1beta1"
+	awsclient "provider-aws-controlapi/internal/clients"
 	"provider-aws-controlapi/internal/controller/config"
+	"provider-aws-controlapi/internal/controller/options"
+	"provider-aws-controlapi/internal/controller/sns/subscription"
 	"provider-aws-controlapi/internal/controller/sns/topic"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
 )
 
-// Setup creates all Template controllers with the supplied logger and adds them to
-// the supplied manager.
-func Setup(mgr ctrl.Manager, l logging.Logger, wl workqueue.RateLimiter, poll time.Duration) error {
-	for _, setup := range []func(ctrl.Manager, logging.Logger, workqueue.RateLimiter, time.Duration) error{
-		config.Setup,
-		topic.SetupTopic,
-	} {
-		if err := setup(mgr, l, wl,poll); err != nil {
+// A kindSetup adds the controller of a resource kind to a manager.
+type kindSetup struct {
+	kind  string
+	setup func(ctrl.Manager, options.Options) error
+}
+
+// controllers are the controllers added by Setup.
+var controllers = []kindSetup{
+	{kind: v1beta1.ProviderConfigKind, setup: config.Setup},
+	{kind: snsv1alpha1.TopicKind, setup: topic.SetupTopic},
+	{kind: snsv1alpha1.SubscriptionKind, setup: subscription.SetupSubscription},
+}
+
+// inventoryKinds are the kinds of managed resources SetupInventory counts.
+var inventoryKinds = map[string]func() resource.ManagedList{
+	snsv1alpha1.TopicKind:        func() resource.ManagedList { return &snsv1alpha1.TopicList{} },
+	snsv1alpha1.SubscriptionKind: func() resource.ManagedList { return &snsv1alpha1.SubscriptionList{} },
+}
+
+// Kinds returns the kinds of the resources reconciled by the controllers
+// Setup adds.
+func Kinds() []string {
+	kinds := make([]string, 0, len(controllers))
+	for _, c := range controllers {
+		kinds = append(kinds, c.kind)
+	}
+	return kinds
+}
+
+// Setup creates all Template controllers with the supplied options and adds
+// them to the supplied manager. The controllers skip status updates that
+// wouldn't change the stored status, and each has its own rate limiter from
+// the supplied ones, which replaces that of the options. Managed resources
+// that are up to date are observed again every poll interval, which the
+// --poll flag sets to a minute by default, to detect drift. Up to
+// maxConcurrentReconcilesPerProviderConfig managed resources that use the
+// same ProviderConfig are reconciled at once, regardless of their kind; zero
+// allows any number.
+func Setup(mgr ctrl.Manager, o options.Options, rl RateLimiters, maxConcurrentReconcilesPerProviderConfig int) error {
+	mgr = newStatusCoalescingManager(mgr)
+	o.ProviderConfigLimiter = awsclient.NewProviderConfigLimiter(maxConcurrentReconcilesPerProviderConfig)
+	for _, c := range controllers {
+		ko := o
+		ko.RateLimiter = rl.For(c.kind)
+		if err := c.setup(mgr, ko); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// SetupInventory adds a runnable to the supplied manager that counts the
+// managed resources of every kind in the controlapi_managed_resources metric
+// every interval. A zero interval disables the count.
+func SetupInventory(mgr ctrl.Manager, l logging.Logger, interval time.Duration) error {
+	if interval <= 0 {
+		return nil
+	}
+	return mgr.Add(awsclient.NewInventory(mgr.GetClient(), l.WithValues("runnable", "inventory"), interval, inventoryKinds))
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	xpfake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	snsv1alpha1 "provider-aws-controlapi/apis/sns/v1alpha1"
+	"provider-aws-controlapi/apis/v1beta1"
+	"provider-aws-controlapi/internal/controller/options"
+	"provider-aws-controlapi/internal/version"
+)
+
+func TestSetupCreationGracePeriod(t *testing.T) {
+	gracePeriod := 5 * time.Minute
+
+	got := map[string]time.Duration{}
+	recorder := func(kind string) func(ctrl.Manager, options.Options) error {
+		return func(_ ctrl.Manager, o options.Options) error {
+			got[kind] = o.CreationGracePeriod
+			return nil
+		}
+	}
+
+	want := map[string]time.Duration{}
+	recording := make([]kindSetup, 0, len(controllers))
+	for _, c := range controllers {
+		recording = append(recording, kindSetup{kind: c.kind, setup: recorder(c.kind)})
+		want[c.kind] = gracePeriod
+	}
+
+	saved := controllers
+	controllers = recording
+	defer func() { controllers = saved }()
+
+	mgr := &xpfake.Manager{Client: &test.MockClient{}}
+	o := options.Options{Logger: logging.NewNopLogger(), PollInterval: time.Minute, CreationGracePeriod: gracePeriod, MaxConcurrentReconciles: 1}
+	if err := Setup(mgr, o, RateLimiters{RPS: 1}, 0); err != nil {
+		t.Fatalf("Setup(...): %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("\nEach controller should be set up with the supplied creation grace period.\nSetup(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestKinds(t *testing.T) {
+	report := version.NewReport(Kinds(), nil)
+	want := []string{snsv1alpha1.SubscriptionKind, snsv1alpha1.TopicKind, v1beta1.ProviderConfigKind}
+	if diff := cmp.Diff(want, report.Kinds, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("\nThe kinds of all registered controllers should be reported.\nNewReport(...): -want, +got:\n%s\n", diff)
+	}
+}
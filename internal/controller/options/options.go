@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package options contains the options the controllers are set up with.
+package options
+
+import (
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"k8s.io/client-go/util/workqueue"
+
+	awsclient "provider-aws-controlapi/internal/clients"
+)
+
+// Options configure a controller.
+type Options struct {
+	// Logger of the controller.
+	Logger logging.Logger
+
+	// RateLimiter of the controller.
+	RateLimiter workqueue.RateLimiter
+
+	// ProviderConfigLimiter bounds the concurrent reconciles of the managed
+	// resources that use the same ProviderConfig, across controllers.
+	ProviderConfigLimiter *awsclient.ProviderConfigLimiter
+
+	// PollInterval at which managed resources that are up to date are
+	// observed again, to detect drift.
+	PollInterval time.Duration
+
+	// CreationGracePeriod during which managed resources that were just
+	// created may be reported as not existing before they are created again.
+	CreationGracePeriod time.Duration
+
+	// MaxConcurrentReconciles of the controller.
+	MaxConcurrentReconciles int
+
+	// Topic configures the Topic controller only.
+	Topic TopicOptions
+}
+
+// TopicOptions configure the Topic controller.
+type TopicOptions struct {
+	// MaxObjectSize of a Topic, beyond which its optional observation fields
+	// are dropped. Zero disables the limit.
+	MaxObjectSize int
+
+	// ObserveSubscriptionProtocols lists the subscriptions of each Topic to
+	// count them by protocol.
+	ObserveSubscriptionProtocols bool
+
+	// VerifyAttributes reads the attributes of a Topic back after updating
+	// them.
+	VerifyAttributes bool
+
+	// VerifyTags lists the tags of a Topic again after updating them.
+	VerifyTags bool
+}
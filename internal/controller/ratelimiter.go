@@ -0,0 +1,42 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// RateLimiters builds a separate rate limiter for the controller of each
+// resource kind, so that one kind being throttled doesn't hold back the
+// reconciles of another.
+type RateLimiters struct {
+	// RPS is the number of reconciles per second each kind is allowed.
+	RPS int
+
+	// KindRPS overrides RPS for the kinds it contains.
+	KindRPS map[string]int
+}
+
+// For returns a new rate limiter for the controller of the supplied kind.
+func (r RateLimiters) For(kind string) workqueue.RateLimiter {
+	rps, ok := r.KindRPS[kind]
+	if !ok {
+		rps = r.RPS
+	}
+	return ratelimiter.NewDefaultProviderRateLimiter(rps)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestRateLimitersFor(t *testing.T) {
+	rl := RateLimiters{RPS: 10, KindRPS: map[string]int{"Topic": 2}}
+
+	limiters := map[string]workqueue.RateLimiter{}
+	for _, c := range controllers {
+		l := rl.For(c.kind)
+		for kind, other := range limiters {
+			if l == other {
+				t.Errorf("\nEach controller should have its own rate limiter.\nFor(%q): shares its rate limiter with %q", c.kind, kind)
+			}
+		}
+		limiters[c.kind] = l
+	}
+
+	cases := map[string]struct {
+		reason string
+		kind   string
+		want   rate.Limit
+	}{
+		"Default": {
+			reason: "Kinds without an override should be limited to the default rate.",
+			kind:   "Subscription",
+			want:   10,
+		},
+		"Override": {
+			reason: "Kinds with an override should be limited to their own rate.",
+			kind:   "Topic",
+			want:   2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			l, ok := limiters[tc.kind].(*workqueue.BucketRateLimiter)
+			if !ok {
+				t.Fatalf("\n%s\nFor(%q): want a *workqueue.BucketRateLimiter, got %T", tc.reason, tc.kind, limiters[tc.kind])
+			}
+			if diff := cmp.Diff(tc.want, l.Limit()); diff != "" {
+				t.Errorf("\n%s\nFor(%q): -want rate, +got rate:\n%s\n", tc.reason, tc.kind, diff)
+			}
+		})
+	}
+}
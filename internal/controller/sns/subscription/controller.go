@@ -0,0 +1,344 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscription
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	awssns "github.com/aws/aws-sdk-go-v2/service/sns"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	snsv1alpha1 "provider-aws-controlapi/apis/sns/v1alpha1"
+	"provider-aws-controlapi/apis/v1beta1"
+	awsclient "provider-aws-controlapi/internal/clients"
+	"provider-aws-controlapi/internal/clients/sns"
+	"provider-aws-controlapi/internal/controller/options"
+)
+
+const (
+	errNotSubscription                 = "managed resource is not a Subscription custom resource"
+	errTrackPCUsage                    = "cannot track ProviderConfig usage"
+	errGetCreds                        = "cannot obtain AWS credentials"
+	errKubeUpdateFailed                = "cannot update Subscription custom resource"
+	errSubscribeFailed                 = "cannot create Subscription"
+	errUnsubscribeFailed               = "cannot delete Subscription"
+	errGetSubscriptionAttributesFailed = "cannot get Subscription attributes"
+	errSetSubscriptionAttributesFailed = "cannot update Subscription attributes"
+)
+
+// SetupSubscription adds a controller that reconciles Subscription managed
+// resources.
+func SetupSubscription(mgr ctrl.Manager, o options.Options) error {
+	name := managed.ControllerName(snsv1alpha1.SubscriptionGroupKind)
+	l := o.Logger
+
+	co := controller.Options{
+		RateLimiter:             ratelimiter.NewDefaultManagedRateLimiter(o.RateLimiter),
+		MaxConcurrentReconciles: o.MaxConcurrentReconciles,
+	}
+
+	r := managed.NewReconciler(mgr,
+		resource.ManagedKind(snsv1alpha1.SubscriptionGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:        mgr.GetClient(),
+			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
+			log:         l.WithValues("controller", name),
+			newClientFn: sns.GetSubscriptionClient}),
+		managed.WithInitializers(managed.NewNameAsExternalName(mgr.GetClient()), awsclient.NewProviderRefInitializer(mgr.GetClient())),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithCreationGracePeriod(o.CreationGracePeriod),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(co).
+		For(&snsv1alpha1.Subscription{}).
+		Complete(o.ProviderConfigLimiter.Limit(r, mgr.GetClient(), func() resource.Managed { return &snsv1alpha1.Subscription{} }))
+}
+
+type connector struct {
+	kube        client.Client
+	usage       resource.Tracker
+	log         logging.Logger
+	newClientFn func(aws.Config) sns.SubscriptionClient
+}
+
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	cr, ok := mg.(*snsv1alpha1.Subscription)
+	if !ok {
+		return nil, errors.New(errNotSubscription)
+	}
+
+	if err := c.usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errTrackPCUsage)
+	}
+
+	cfg, pc, err := awsclient.GetConfig(ctx, c.kube, mg, cr.Spec.ForProvider.Region, c.log)
+	if err != nil {
+		return nil, err
+	}
+	if err := awsclient.RecordEndpoint(ctx, c.kube, pc, cfg, awssns.ServiceID, cr.Spec.ForProvider.Region); err != nil {
+		return nil, err
+	}
+	if pc.Spec.PrewarmCredentials {
+		if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+			return nil, awsclient.Wrap(err, errGetCreds)
+		}
+	}
+	e := &external{c.newClientFn(*cfg), c.kube, awsclient.RequestTimeout(pc), awsclient.NewAuditor(pc, c.log), time.Now}
+	if aws.ToBool(cr.Spec.ForProvider.ObserveOnly) {
+		return awsclient.ObserveOnly(e, c.log), nil
+	}
+	return e, nil
+}
+
+type external struct {
+	client sns.SubscriptionClient
+	kube   client.Client
+
+	// requestTimeout bounds each observe, create, update and delete.
+	requestTimeout time.Duration
+
+	// auditor records the attributes that were changed.
+	auditor awsclient.Auditor
+
+	// now returns the current time, which decides whether a Subscription is
+	// in its maintenance window.
+	now func() time.Time
+}
+
+// Observe observes the external Subscription and records the outcome in the last
+// sync status annotation of the managed resource.
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx, cancel := awsclient.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	o, err := c.observe(ctx, mg)
+	if rerr := awsclient.RecordLastSyncStatus(ctx, c.kube, mg, o, err); rerr != nil && err == nil {
+		return managed.ExternalObservation{}, rerr
+	}
+	return o, err
+}
+
+func (c *external) observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*snsv1alpha1.Subscription)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotSubscription)
+	}
+
+	if cr.Spec.ForProvider.FilterPolicy != nil {
+		if err := sns.ValidateFilterPolicy(aws.ToString(cr.Spec.ForProvider.FilterPolicy)); err != nil {
+			cr.SetConditions(snsv1alpha1.FilterPolicyInvalid(err))
+		} else {
+			cr.SetConditions(snsv1alpha1.FilterPolicyValid())
+		}
+	}
+
+	// Subscriptions that were created without returning their ARN can't be
+	// observed until the endpoint confirms them. This isn't an error, the
+	// subscription just isn't usable yet. Pending subscriptions can't be
+	// unsubscribed either, so one that is being deleted is reported as gone
+	// to let its finalizer be removed.
+	if sns.IsSubscriptionPending(meta.GetExternalName(cr)) {
+		if meta.WasDeleted(cr) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		cr.SetConditions(xpv1.Unavailable())
+		return managed.ExternalObservation{
+			ResourceExists:   true,
+			ResourceUpToDate: true,
+		}, nil
+	}
+
+	// The external name is the SubscriptionArn once the subscription has
+	// been created. Until then it defaults to the name of the object.
+	if !arn.IsARN(meta.GetExternalName(cr)) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	resp, err := c.client.GetSubscriptionAttributes(ctx, &awssns.GetSubscriptionAttributesInput{
+		SubscriptionArn: aws.String(meta.GetExternalName(cr)),
+	})
+	if err != nil {
+		if sns.IsNotFound(err) {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		return managed.ExternalObservation{}, awsclient.Wrap(err, errGetSubscriptionAttributesFailed)
+	}
+
+	current := cr.Spec.ForProvider.DeepCopy()
+	sns.LateInitializeSubscription(&cr.Spec.ForProvider, resp.Attributes)
+	if !cmp.Equal(current, &cr.Spec.ForProvider) {
+		if err := c.kube.Update(ctx, cr); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errKubeUpdateFailed)
+		}
+	}
+
+	cr.Status.AtProvider = sns.GenerateSubscriptionObservation(resp.Attributes)
+	if aws.ToBool(cr.Status.AtProvider.PendingConfirmation) {
+		cr.SetConditions(xpv1.Unavailable())
+	} else {
+		cr.SetConditions(xpv1.Available())
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: sns.IsSubscriptionUpToDate(cr.Spec.ForProvider, resp.Attributes),
+	}, nil
+}
+
+// checkMaintenanceWindow returns an error if the supplied Subscription is in
+// its maintenance window, during which it's neither created, updated nor
+// deleted.
+func (c *external) checkMaintenanceWindow(cr *snsv1alpha1.Subscription) error {
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	return sns.CheckMaintenanceWindow(cr.Spec.ForProvider.MaintenanceWindow, now())
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	ctx, cancel := awsclient.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	cr, ok := mg.(*snsv1alpha1.Subscription)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotSubscription)
+	}
+	if err := c.checkMaintenanceWindow(cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	cr.SetConditions(xpv1.Creating())
+
+	// Asking for the SubscriptionArn lets us observe subscriptions that are
+	// still pending confirmation, rather than getting the placeholder back.
+	resp, err := c.client.Subscribe(ctx, &awssns.SubscribeInput{
+		TopicArn:              aws.String(cr.Spec.ForProvider.TopicArn),
+		Protocol:              aws.String(cr.Spec.ForProvider.Protocol),
+		Endpoint:              cr.Spec.ForProvider.Endpoint,
+		Attributes:            sns.GenerateSubscriptionAttributeMap(cr.Spec.ForProvider),
+		ReturnSubscriptionArn: true,
+	})
+	if err != nil {
+		return managed.ExternalCreation{}, awsclient.Wrap(err, errSubscribeFailed)
+	}
+
+	return managed.ExternalCreation{}, awsclient.PersistExternalName(ctx, c.kube, cr, aws.ToString(resp.SubscriptionArn))
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	ctx, cancel := awsclient.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	cr, ok := mg.(*snsv1alpha1.Subscription)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotSubscription)
+	}
+	if err := c.checkMaintenanceWindow(cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	resp, err := c.client.GetSubscriptionAttributes(ctx, &awssns.GetSubscriptionAttributesInput{
+		SubscriptionArn: aws.String(meta.GetExternalName(cr)),
+	})
+	if err != nil {
+		return managed.ExternalUpdate{}, awsclient.Wrap(resource.Ignore(sns.IsNotFound, err), errGetSubscriptionAttributesFailed)
+	}
+
+	// The attributes that were set are audited even if setting a later one
+	// fails.
+	var set []string
+	defer func() { c.audit(ctx, cr, set) }()
+
+	diff := sns.GetSubscriptionAttributeDiff(cr.Spec.ForProvider, resp.Attributes)
+	names := make([]string, 0, len(diff))
+	for k := range diff {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		_, err := c.client.SetSubscriptionAttributes(ctx, &awssns.SetSubscriptionAttributesInput{
+			SubscriptionArn: aws.String(meta.GetExternalName(cr)),
+			AttributeName:   aws.String(k),
+			AttributeValue:  aws.String(diff[k]),
+		})
+		if err != nil {
+			return managed.ExternalUpdate{}, awsclient.Wrap(err, errSetSubscriptionAttributesFailed)
+		}
+		set = append(set, k)
+	}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+// audit records the supplied attributes that were changed on the supplied
+// Subscription, if any. Only those that were actually applied should be
+// supplied, rather than all those that differed.
+func (c *external) audit(ctx context.Context, cr *snsv1alpha1.Subscription, set []string) {
+	if c.auditor == nil || len(set) == 0 {
+		return
+	}
+	c.auditor.Audit(ctx, awsclient.AuditEvent{Kind: snsv1alpha1.SubscriptionKind, Name: cr.GetName(), ARN: meta.GetExternalName(cr), Attributes: set})
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	ctx, cancel := awsclient.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	cr, ok := mg.(*snsv1alpha1.Subscription)
+	if !ok {
+		return errors.New(errNotSubscription)
+	}
+	if err := c.checkMaintenanceWindow(cr); err != nil {
+		return err
+	}
+	if err := awsclient.CheckDeletionProtection(cr); err != nil {
+		return err
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+
+	// A subscription that was never confirmed can't be unsubscribed, SNS
+	// removes it once the confirmation expires.
+	if sns.IsSubscriptionPending(meta.GetExternalName(cr)) {
+		return nil
+	}
+
+	_, err := c.client.Unsubscribe(ctx, &awssns.UnsubscribeInput{
+		SubscriptionArn: aws.String(meta.GetExternalName(cr)),
+	})
+	return awsclient.Wrap(resource.Ignore(sns.IsNotFound, err), errUnsubscribeFailed)
+}
@@ -0,0 +1,426 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subscription
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awssns "github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/smithy-go"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	snsv1alpha1 "provider-aws-controlapi/apis/sns/v1alpha1"
+	awsclient "provider-aws-controlapi/internal/clients"
+	"provider-aws-controlapi/internal/clients/sns"
+	"provider-aws-controlapi/internal/clients/sns/fake"
+)
+
+const (
+	subscriptionName = "example"
+	subscriptionArn  = "arn:aws:sns:us-west-2:123456789012:example:1234abcd-12ab-34cd-56ef-1234567890ab"
+	topicArn         = "arn:aws:sns:us-west-2:123456789012:example"
+)
+
+var (
+	errBoom     = errors.New("boom")
+	errNotFound = &smithy.GenericAPIError{Code: sns.NotFoundException}
+)
+
+type subscriptionModifier func(*snsv1alpha1.Subscription)
+
+func withExternalName(n string) subscriptionModifier {
+	return func(r *snsv1alpha1.Subscription) { meta.SetExternalName(r, n) }
+}
+
+func withAnnotations(a map[string]string) subscriptionModifier {
+	return func(r *snsv1alpha1.Subscription) { meta.AddAnnotations(r, a) }
+}
+
+func withRawMessageDelivery(b bool) subscriptionModifier {
+	return func(r *snsv1alpha1.Subscription) { r.Spec.ForProvider.RawMessageDelivery = aws.Bool(b) }
+}
+
+func withFilterPolicy(p string) subscriptionModifier {
+	return func(r *snsv1alpha1.Subscription) { r.Spec.ForProvider.FilterPolicy = aws.String(p) }
+}
+
+func withDeletionTimestamp() subscriptionModifier {
+	return func(r *snsv1alpha1.Subscription) { r.SetDeletionTimestamp(&metav1.Time{Time: time.Now()}) }
+}
+
+func subscription(m ...subscriptionModifier) *snsv1alpha1.Subscription {
+	cr := &snsv1alpha1.Subscription{
+		Spec: snsv1alpha1.SubscriptionSpec{
+			ForProvider: snsv1alpha1.SubscriptionParameters{
+				Region:   "us-west-2",
+				TopicArn: topicArn,
+				Protocol: "sqs",
+				Endpoint: aws.String("arn:aws:sqs:us-west-2:123456789012:example"),
+			},
+		},
+	}
+	cr.SetName(subscriptionName)
+	for _, f := range m {
+		f(cr)
+	}
+	return cr
+}
+
+func attributes(pending bool) map[string]string {
+	a := map[string]string{
+		snsv1alpha1.SubscriptionArn:                 subscriptionArn,
+		snsv1alpha1.SubscriptionOwner:               "123456789012",
+		snsv1alpha1.SubscriptionRawMessageDelivery:  "false",
+		snsv1alpha1.SubscriptionPendingConfirmation: "false",
+	}
+	if pending {
+		a[snsv1alpha1.SubscriptionPendingConfirmation] = "true"
+	}
+	return a
+}
+
+func getAttributes(attrs map[string]string, err error) func(context.Context, *awssns.GetSubscriptionAttributesInput, []func(*awssns.Options)) (*awssns.GetSubscriptionAttributesOutput, error) {
+	return func(_ context.Context, _ *awssns.GetSubscriptionAttributesInput, _ []func(*awssns.Options)) (*awssns.GetSubscriptionAttributesOutput, error) {
+		if err != nil {
+			return nil, err
+		}
+		return &awssns.GetSubscriptionAttributesOutput{Attributes: attrs}, nil
+	}
+}
+
+func TestObserve(t *testing.T) {
+	type want struct {
+		o          managed.ExternalObservation
+		err        error
+		syncStatus string
+		condition  *xpv1.Condition
+	}
+
+	kube := &test.MockClient{
+		MockPatch:  test.NewMockPatchFn(nil),
+		MockUpdate: test.NewMockUpdateFn(nil),
+	}
+
+	unavailable := xpv1.Unavailable()
+	available := xpv1.Available()
+
+	cases := map[string]struct {
+		reason string
+		client sns.SubscriptionClient
+		mg     resource.Managed
+		want   want
+	}{
+		"NotCreated": {
+			reason: "A Subscription whose external name isn't an ARN yet should not exist.",
+			client: &fake.MockSubscriptionClient{},
+			mg:     subscription(withExternalName(subscriptionName)),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}, syncStatus: awsclient.LastSyncStatusDrifted},
+		},
+		"PendingConfirmation": {
+			reason: "A Subscription pending confirmation should exist but be unavailable.",
+			client: &fake.MockSubscriptionClient{},
+			mg:     subscription(withExternalName(snsv1alpha1.SubscriptionPendingConfirmationArn)),
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				syncStatus: awsclient.LastSyncStatusSynced,
+				condition:  &unavailable,
+			},
+		},
+		"PendingConfirmationDeleted": {
+			reason: "A Subscription pending confirmation that is being deleted should not exist, so that its finalizer is removed.",
+			client: &fake.MockSubscriptionClient{},
+			mg:     subscription(withExternalName(snsv1alpha1.SubscriptionPendingConfirmationArn), withDeletionTimestamp()),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}, syncStatus: awsclient.LastSyncStatusDrifted},
+		},
+		"NotFound": {
+			reason: "A Subscription that was unsubscribed should not exist.",
+			client: &fake.MockSubscriptionClient{MockGetSubscriptionAttributes: getAttributes(nil, errNotFound)},
+			mg:     subscription(withExternalName(subscriptionArn)),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}, syncStatus: awsclient.LastSyncStatusDrifted},
+		},
+		"GetAttributesFailed": {
+			reason: "Errors getting the attributes of the Subscription should be returned.",
+			client: &fake.MockSubscriptionClient{MockGetSubscriptionAttributes: getAttributes(nil, errBoom)},
+			mg:     subscription(withExternalName(subscriptionArn)),
+			want:   want{err: awsclient.Wrap(errBoom, errGetSubscriptionAttributesFailed), syncStatus: awsclient.LastSyncStatusError},
+		},
+		"Confirmed": {
+			reason: "A confirmed Subscription should be available and up to date.",
+			client: &fake.MockSubscriptionClient{MockGetSubscriptionAttributes: getAttributes(attributes(false), nil)},
+			mg:     subscription(withExternalName(subscriptionArn)),
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				syncStatus: awsclient.LastSyncStatusSynced,
+				condition:  &available,
+			},
+		},
+		"AwaitingConfirmation": {
+			reason: "A Subscription whose ARN was returned but that isn't confirmed yet should be unavailable.",
+			client: &fake.MockSubscriptionClient{MockGetSubscriptionAttributes: getAttributes(attributes(true), nil)},
+			mg:     subscription(withExternalName(subscriptionArn)),
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				syncStatus: awsclient.LastSyncStatusSynced,
+				condition:  &unavailable,
+			},
+		},
+		"Drifted": {
+			reason: "A Subscription whose attributes differ from its spec should not be up to date.",
+			client: &fake.MockSubscriptionClient{MockGetSubscriptionAttributes: getAttributes(attributes(false), nil)},
+			mg:     subscription(withExternalName(subscriptionArn), withRawMessageDelivery(true)),
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				syncStatus: awsclient.LastSyncStatusDrifted,
+				condition:  &available,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client, kube: kube}
+			got, err := e.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.syncStatus, tc.mg.GetAnnotations()[awsclient.AnnotationKeyLastSyncStatus]); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want sync status, +got sync status:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.condition != nil {
+				got := tc.mg.GetCondition(tc.want.condition.Type)
+				if diff := cmp.Diff(*tc.want.condition, got, test.EquateConditions()); diff != "" {
+					t.Errorf("\n%s\ne.Observe(...): -want condition, +got condition:\n%s\n", tc.reason, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	type want struct {
+		err          error
+		externalName string
+	}
+
+	subscribe := func(arn string) func(context.Context, *awssns.SubscribeInput, []func(*awssns.Options)) (*awssns.SubscribeOutput, error) {
+		return func(_ context.Context, in *awssns.SubscribeInput, _ []func(*awssns.Options)) (*awssns.SubscribeOutput, error) {
+			if !in.ReturnSubscriptionArn {
+				return nil, errBoom
+			}
+			return &awssns.SubscribeOutput{SubscriptionArn: aws.String(arn)}, nil
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		client sns.SubscriptionClient
+		want   want
+	}{
+		"Subscribed": {
+			reason: "The ARN of the subscription should be the external name of the Subscription.",
+			client: &fake.MockSubscriptionClient{MockSubscribe: subscribe(subscriptionArn)},
+			want:   want{externalName: subscriptionArn},
+		},
+		"PendingConfirmation": {
+			reason: "A subscription pending confirmation should be recorded as such in the external name of the Subscription.",
+			client: &fake.MockSubscriptionClient{MockSubscribe: subscribe(snsv1alpha1.SubscriptionPendingConfirmationArn)},
+			want:   want{externalName: snsv1alpha1.SubscriptionPendingConfirmationArn},
+		},
+		"SubscribeFailed": {
+			reason: "Errors subscribing should be returned.",
+			client: &fake.MockSubscriptionClient{
+				MockSubscribe: func(_ context.Context, _ *awssns.SubscribeInput, _ []func(*awssns.Options)) (*awssns.SubscribeOutput, error) {
+					return nil, errBoom
+				},
+			},
+			want: want{err: awsclient.Wrap(errBoom, errSubscribeFailed), externalName: subscriptionName},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kube := &test.MockClient{
+				MockGet:   test.NewMockGetFn(nil),
+				MockPatch: test.NewMockPatchFn(nil),
+			}
+			e := external{client: tc.client, kube: kube}
+			cr := subscription(withExternalName(subscriptionName))
+			_, err := e.Create(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(cr)); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want external name, +got external name:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type want struct {
+		err     error
+		set     map[string]string
+		audited []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     *snsv1alpha1.Subscription
+		get    error
+		set    error
+		failOn string
+		want   want
+	}{
+		"UpToDate": {
+			reason: "No attributes should be set if the Subscription is up to date.",
+			mg:     subscription(withExternalName(subscriptionArn), withRawMessageDelivery(false)),
+			want:   want{set: map[string]string{}},
+		},
+		"Updated": {
+			reason: "Attributes that differ from the spec should be set and audited.",
+			mg:     subscription(withExternalName(subscriptionArn), withRawMessageDelivery(true)),
+			want: want{
+				set:     map[string]string{snsv1alpha1.SubscriptionRawMessageDelivery: "true"},
+				audited: []string{snsv1alpha1.SubscriptionRawMessageDelivery},
+			},
+		},
+		"GetAttributesFailed": {
+			reason: "Errors getting the attributes of the Subscription should be returned.",
+			mg:     subscription(withExternalName(subscriptionArn), withRawMessageDelivery(true)),
+			get:    errBoom,
+			want:   want{err: awsclient.Wrap(errBoom, errGetSubscriptionAttributesFailed), set: map[string]string{}},
+		},
+		"SetAttributesFailed": {
+			reason: "Errors setting the attributes of the Subscription should be returned.",
+			mg:     subscription(withExternalName(subscriptionArn), withRawMessageDelivery(true)),
+			set:    errBoom,
+			want:   want{err: awsclient.Wrap(errBoom, errSetSubscriptionAttributesFailed), set: map[string]string{}},
+		},
+		"LaterAttributeFailed": {
+			reason: "Attributes that were set before setting another one failed should be audited.",
+			mg:     subscription(withExternalName(subscriptionArn), withRawMessageDelivery(true), withFilterPolicy(`{"store": ["example"]}`)),
+			set:    errBoom,
+			failOn: snsv1alpha1.SubscriptionRawMessageDelivery,
+			want: want{
+				err:     awsclient.Wrap(errBoom, errSetSubscriptionAttributesFailed),
+				set:     map[string]string{snsv1alpha1.SubscriptionFilterPolicy: `{"store": ["example"]}`},
+				audited: []string{snsv1alpha1.SubscriptionFilterPolicy},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			set := map[string]string{}
+			var audited []string
+			c := &fake.MockSubscriptionClient{
+				MockGetSubscriptionAttributes: getAttributes(attributes(false), tc.get),
+				MockSetSubscriptionAttributes: func(_ context.Context, in *awssns.SetSubscriptionAttributesInput, _ []func(*awssns.Options)) (*awssns.SetSubscriptionAttributesOutput, error) {
+					if tc.set != nil && (tc.failOn == "" || tc.failOn == aws.ToString(in.AttributeName)) {
+						return nil, tc.set
+					}
+					set[aws.ToString(in.AttributeName)] = aws.ToString(in.AttributeValue)
+					return &awssns.SetSubscriptionAttributesOutput{}, nil
+				},
+			}
+			auditor := awsclient.AuditorFn(func(_ context.Context, e awsclient.AuditEvent) { audited = e.Attributes })
+			e := external{client: c, auditor: auditor}
+			_, err := e.Update(context.Background(), tc.mg)
+			got := want{err: err, set: set, audited: audited}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type want struct {
+		err   error
+		calls int
+	}
+
+	cases := map[string]struct {
+		reason string
+		mg     *snsv1alpha1.Subscription
+		err    error
+		want   want
+	}{
+		"Unsubscribed": {
+			reason: "A confirmed Subscription should be unsubscribed.",
+			mg:     subscription(withExternalName(subscriptionArn)),
+			want:   want{calls: 1},
+		},
+		"PendingConfirmation": {
+			reason: "A Subscription pending confirmation can't be unsubscribed.",
+			mg:     subscription(withExternalName(snsv1alpha1.SubscriptionPendingConfirmationArn)),
+			want:   want{calls: 0},
+		},
+		"NotFound": {
+			reason: "A Subscription that was already unsubscribed should be deleted.",
+			mg:     subscription(withExternalName(subscriptionArn)),
+			err:    errNotFound,
+			want:   want{calls: 1},
+		},
+		"UnsubscribeFailed": {
+			reason: "Errors unsubscribing should be returned.",
+			mg:     subscription(withExternalName(subscriptionArn)),
+			err:    errBoom,
+			want:   want{err: awsclient.Wrap(errBoom, errUnsubscribeFailed), calls: 1},
+		},
+		"Protected": {
+			reason: "A Subscription with deletion protection should not be unsubscribed.",
+			mg:     subscription(withExternalName(subscriptionArn), withAnnotations(map[string]string{awsclient.AnnotationKeyDeletionProtection: "true"})),
+			want:   want{err: awsclient.CheckDeletionProtection(subscription(withAnnotations(map[string]string{awsclient.AnnotationKeyDeletionProtection: "true"})))},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			c := &fake.MockSubscriptionClient{
+				MockUnsubscribe: func(_ context.Context, _ *awssns.UnsubscribeInput, _ []func(*awssns.Options)) (*awssns.UnsubscribeOutput, error) {
+					calls++
+					if tc.err != nil {
+						return nil, tc.err
+					}
+					return &awssns.UnsubscribeOutput{}, nil
+				},
+			}
+			e := external{client: c}
+			err := e.Delete(context.Background(), tc.mg)
+			got := want{err: err, calls: calls}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -20,22 +20,30 @@ import (
 	"context"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	awssns "github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/aws/aws-sdk-go-v2/service/sns/types"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/google/go-cmp/cmp"
 	snsv1alpha1 "provider-aws-controlapi/apis/sns/v1alpha1"
+	"provider-aws-controlapi/apis/v1beta1"
 	awsclient "provider-aws-controlapi/internal/clients"
 	"provider-aws-controlapi/internal/clients/sns"
+	"provider-aws-controlapi/internal/controller/options"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
-	"k8s.io/client-go/util/workqueue"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
@@ -51,45 +59,147 @@ const (
 	errDeleteFailed             = "cannot delete Topic"
 	errGetTopicAttributesFailed = "cannot get Topic attributes"
 	errTag                      = "cannot tag Topic"
+	errUntag                    = "cannot untag Topic"
+	errSetTopicAttributesFailed = "cannot set Topic attributes"
+	errUpdateThrottled          = "update throttled, retrying with backoff"
 	errListTopicTagsFailed      = "cannot list Topic tags"
 	errUpdateFailed             = "failed to update the Queue resource"
 	errTrackPCUsage 			= "cannot track ProviderConfig usage"
 	errGetPC        			= "cannot get ProviderConfig"
-	errGetCreds     			= "cannot get credentials"
+	errGetCreds     			= "cannot obtain AWS credentials"
 	errNewClient 				= "cannot create new Service"
+	errListSubscriptionsFailed  = "cannot list Topic subscriptions"
+	errUnencryptedTopic         = "ProviderConfig requires encryption but the Topic has no KMS master key"
+	errAWSManagedKey            = "ProviderConfig requires a customer managed KMS key but the Topic uses the AWS managed key of SNS"
+	errRecoverCreate            = "cannot recover the interrupted creation of the Topic"
+	errListTopics               = "cannot list Topics referencing a KMS Key"
+	errListSharedTopics         = "cannot list Topics that address the same topic"
+	errIndexTopics              = "cannot index Topics by the topic they address"
+	errProbeExistingTopic       = "cannot check whether the Topic already exists"
 )
 
-
 // SetupTopic adds a controller that reconciles Topic managed resources.
-func SetupTopic(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, poll  time.Duration) error {
+func SetupTopic(mgr ctrl.Manager, o options.Options) error {
 	name := managed.ControllerName(snsv1alpha1.TopicGroupKind)
+	l := o.Logger
+
+	co := controller.Options{
+		RateLimiter:             ratelimiter.NewDefaultManagedRateLimiter(o.RateLimiter),
+		MaxConcurrentReconciles: o.MaxConcurrentReconciles,
+	}
 
-	o := controller.Options{
-		RateLimiter: ratelimiter.NewDefaultManagedRateLimiter(rl),
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &snsv1alpha1.Topic{}, sns.IndexKeyTopic, func(o client.Object) []string {
+		return sns.TopicIndexValues(o.(*snsv1alpha1.Topic))
+	}); err != nil {
+		return errors.Wrap(err, errIndexTopics)
 	}
 
 	r := managed.NewReconciler(mgr,
 		resource.ManagedKind(snsv1alpha1.TopicGroupVersionKind),
 		managed.WithExternalConnecter(&connector{
-			kube:        mgr.GetClient(),
-			//usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
-			newClientFn: sns.GetClient}),
+			kube:                         mgr.GetClient(),
+			log:                          l.WithValues("controller", name),
+			maxObjectSize:                o.Topic.MaxObjectSize,
+			observeSubscriptionProtocols: o.Topic.ObserveSubscriptionProtocols,
+			verifyAttributes:             o.Topic.VerifyAttributes,
+			verifyTags:                   o.Topic.VerifyTags,
+			usage:                        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
+			newClientFn:                  sns.GetClient}),
+		managed.WithInitializers(managed.NewNameAsExternalName(mgr.GetClient()), awsclient.NewProviderRefInitializer(mgr.GetClient()), &createRecoverer{kube: mgr.GetClient()}),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithCreationGracePeriod(o.CreationGracePeriod),
 		managed.WithLogger(l.WithValues("controller", name)),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		Named(name).
-		WithOptions(o).
-		For(&snsv1alpha1.Topic{}).
-		Complete(r)
+		WithOptions(co).
+		For(&snsv1alpha1.Topic{})
+
+	// KMS Keys are managed by provider-aws, which may not be installed. They
+	// can only be watched if their CRD was installed when the provider started.
+	if _, err := mgr.GetRESTMapper().RESTMapping(snsv1alpha1.KMSKeyGroupVersionKind.GroupKind(), snsv1alpha1.KMSKeyGroupVersionKind.Version); err == nil {
+		key := &unstructured.Unstructured{}
+		key.SetGroupVersionKind(snsv1alpha1.KMSKeyGroupVersionKind)
+		b = b.Watches(&source.Kind{Type: key}, handler.EnqueueRequestsFromMapFunc(enqueueForKMSKey(mgr.GetClient(), l.WithValues("controller", name))))
+	}
+
+	return b.Complete(o.ProviderConfigLimiter.Limit(r, mgr.GetClient(), func() resource.Managed { return &snsv1alpha1.Topic{} }))
+}
+
+// enqueueForKMSKey maps a KMS Key to the Topics that reference or select it.
+// A Topic whose key isn't created yet is thus reconciled as soon as the key
+// is, rather than at its next poll.
+func enqueueForKMSKey(c client.Reader, l logging.Logger) handler.MapFunc {
+	return func(key client.Object) []reconcile.Request {
+		topics := &snsv1alpha1.TopicList{}
+		if err := c.List(context.TODO(), topics); err != nil {
+			l.Debug(errListTopics, "error", err)
+			return nil
+		}
+		var reqs []reconcile.Request
+		for i := range topics.Items {
+			if referencesKMSKey(topics.Items[i].Spec.ForProvider, key) {
+				reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&topics.Items[i])})
+			}
+		}
+		return reqs
+	}
+}
+
+// referencesKMSKey returns true if the supplied parameters reference the
+// supplied KMS Key, or select it by its labels.
+func referencesKMSKey(p snsv1alpha1.TopicParameters, key client.Object) bool {
+	switch {
+	case p.KMSMasterKeyIDRef != nil:
+		return p.KMSMasterKeyIDRef.Name == key.GetName()
+	case p.KMSMasterKeyIDSelector != nil:
+		return labels.SelectorFromSet(p.KMSMasterKeyIDSelector.MatchLabels).Matches(labels.Set(key.GetLabels()))
+	}
+	return false
+}
+
+// A createRecoverer lets the reconciler proceed with a Topic whose creation
+// was interrupted, e.g. by a crash between creating the topic and recording
+// the crossplane.io/external-create-succeeded annotation. The reconciler
+// otherwise refuses to reconcile it until the external-create-pending
+// annotation is removed by hand, since it can't know whether a resource was
+// leaked. A Topic can't be leaked or duplicated: CreateTopic returns the
+// existing topic of the same name, and Observe finds a topic by its name
+// until its ARN is recorded as the external name.
+type createRecoverer struct {
+	kube client.Client
+}
+
+// Initialize marks the interrupted creation of the supplied Topic as failed,
+// so that the Topic is observed, and created again only if it doesn't exist.
+func (r *createRecoverer) Initialize(ctx context.Context, mg resource.Managed) error {
+	if !meta.ExternalCreateIncomplete(mg) {
+		return nil
+	}
+	meta.SetExternalCreateFailed(mg, time.Now())
+	return errors.Wrap(r.kube.Update(ctx, mg), errRecoverCreate)
 }
 
 // A connector is expected to produce an ExternalClient when its Connect method
 // is called.
 type connector struct {
-	kube        client.Client
-	//usage       resource.Tracker
-	newClientFn func(aws.Config) sns.Client
+	kube          client.Client
+	log           logging.Logger
+	maxObjectSize int
+	usage         resource.Tracker
+	newClientFn   func(aws.Config) sns.Client
+
+	// observeSubscriptionProtocols lists the subscriptions of each Topic to
+	// count them by protocol.
+	observeSubscriptionProtocols bool
+
+	// verifyAttributes reads the attributes of a Topic back after updating
+	// them.
+	verifyAttributes bool
+
+	// verifyTags lists the tags of a Topic again after updating them.
+	verifyTags bool
 }
 
 // Connect typically produces an ExternalClient by:
@@ -102,47 +212,181 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 	if !ok {
 		return nil, errors.New(errNotTopic)
 	}
-	/*
 	if err := c.usage.Track(ctx, mg); err != nil {
 		return nil, errors.Wrap(err, errTrackPCUsage)
 	}
-	*/
 
-	cfg, err := awsclient.GetConfig(ctx, c.kube, mg, cr.Spec.ForProvider.Region)
+	cfg, pc, err := awsclient.GetConfig(ctx, c.kube, mg, cr.Spec.ForProvider.Region, c.log)
 	if err != nil {
 		return nil, err
 	}
-	return &external{c.newClientFn(*cfg), c.kube}, nil
+	if err := awsclient.RecordEndpoint(ctx, c.kube, pc, cfg, awssns.ServiceID, cr.Spec.ForProvider.Region); err != nil {
+		return nil, err
+	}
+	if pc.Spec.PrewarmCredentials {
+		if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+			return nil, awsclient.Wrap(err, errGetCreds)
+		}
+	}
+	e := &external{
+		client:            c.newClientFn(*cfg),
+		kube:              c.kube,
+		log:               c.log,
+		maxObjectSize:     c.maxObjectSize,
+		resolver:          sns.NewSTSNameResolver(cfg),
+		defaultTags:       pc.Spec.DefaultTags,
+		requireEncryption: pc.Spec.RequireEncryption,
+		requireCMK:        pc.Spec.RequireCustomerManagedKey,
+		costTags:          pc.Spec.CostTags,
+		namePattern:       aws.ToString(pc.Spec.NamePattern),
+		requestTimeout:    awsclient.RequestTimeout(pc),
+		auditor:           awsclient.NewAuditor(pc, c.log),
+		now:               time.Now,
+		detectShared:      true,
+
+		observeSubscriptionProtocols: c.observeSubscriptionProtocols,
+		verifyAttributes:             c.verifyAttributes,
+		verifyTags:                   c.verifyTags,
+	}
+	if aws.ToBool(cr.Spec.ForProvider.ObserveOnly) {
+		return awsclient.ObserveOnly(e, c.log), nil
+	}
+	return e, nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
-	client sns.Client
-	kube   client.Client
+	client        sns.Client
+	kube          client.Client
+	log           logging.Logger
+	maxObjectSize int
+
+	// resolver resolves the name of a Topic to its ARN.
+	resolver sns.NameResolver
+
+	// defaultTags are the default tags of the ProviderConfig, which every
+	// Topic is tagged with in addition to its own tags.
+	defaultTags map[string]string
+
+	// requireEncryption denies Topics without a KMS master key.
+	requireEncryption bool
+
+	// requireCMK denies Topics that aren't encrypted with a customer managed
+	// KMS key.
+	requireCMK bool
+
+	// costTags are the keys of the cost allocation tags the ProviderConfig
+	// recommends every Topic has.
+	costTags []string
+
+	// namePattern is the regular expression the names of new Topics must
+	// match.
+	namePattern string
+
+	// requestTimeout bounds each observe, create, update and delete.
+	requestTimeout time.Duration
+
+	// auditor records the attributes and tags that were changed.
+	auditor awsclient.Auditor
+
+	// now returns the current time, which decides whether a Topic is in its
+	// maintenance window.
+	now func() time.Time
+
+	// observeSubscriptionProtocols lists the subscriptions of each Topic to
+	// count them by protocol.
+	observeSubscriptionProtocols bool
+
+	// verifyAttributes reads the attributes of a Topic back after updating
+	// them, to detect changes SNS didn't apply.
+	verifyAttributes bool
+
+	// verifyTags lists the tags of a Topic again after updating them, to
+	// detect changes SNS didn't apply.
+	verifyTags bool
+
+	// detectShared lists the other Topics to warn about those that address
+	// the same topic.
+	detectShared bool
 }
 
+// Observe observes the external Topic and records the outcome in the last
+// sync status annotation of the managed resource.
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx, cancel := awsclient.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	o, err := c.observe(ctx, mg)
+	if rerr := awsclient.RecordLastSyncStatus(ctx, c.kube, mg, o, err); rerr != nil && err == nil {
+		return managed.ExternalObservation{}, rerr
+	}
+	return o, err
+}
+
+func (c *external) observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
 	cr, ok := mg.(*snsv1alpha1.Topic)
 	if !ok {
 		return managed.ExternalObservation{}, errors.New(errNotTopic)
 	}
 
-	if strings.EqualFold(meta.GetExternalName(cr),cr.GetName()){
-		return managed.ExternalObservation{
-			ResourceExists: false,
-			ConnectionDetails: nil,
-			ResourceUpToDate: false,
-		},nil
+	if err := c.checkEncryption(cr); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	// The external name is the TopicArn once the Topic has been created or
+	// imported by ARN. Until then it's the name of the Topic, which is all
+	// that's needed to derive its ARN. Topics created by early versions of
+	// this provider also have their name as external name; it's migrated to
+	// the ARN below like that of an imported Topic.
+	topicArn := meta.GetExternalName(cr)
+	if !arn.IsARN(topicArn) && sns.FailsOnExisting(cr.Spec.ForProvider) && !sns.CreatedTopic(cr, topicArn) {
+		// A Topic that must not adopt an existing topic only manages the
+		// topic it created, whose ARN is recorded as its external name, or
+		// whose name it recorded before creating it if that ARN was lost.
+		// Create refuses to create it if a topic of its name exists.
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if !arn.IsARN(topicArn) {
+		resolved, err := c.resolver.ResolveTopicARN(ctx, cr.Spec.ForProvider.Region, topicArn)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		topicArn = resolved
+	}
+	if err := c.checkARNUnchanged(cr, topicArn); err != nil {
+		if !meta.WasDeleted(cr) {
+			return managed.ExternalObservation{}, err
+		}
+		// A Topic that is being deleted deletes the topic it was last
+		// observed to manage, whose ARN is persisted as its external name
+		// below, rather than the one its spec now implies. One that never
+		// observed a topic doesn't manage any.
+		observed := aws.ToString(cr.Status.AtProvider.TopicArn)
+		if observed == "" {
+			return managed.ExternalObservation{ResourceExists: false}, nil
+		}
+		topicArn = observed
 	}
 
 	//Check existence of the Topic and if exists, get all sns attributes values
 	topicAttributes, err := c.client.GetTopicAttributes(ctx,&awssns.GetTopicAttributesInput{
-		TopicArn: aws.String(meta.GetExternalName(cr)),
+		TopicArn: aws.String(topicArn),
 	})
 	if err != nil {
 		return managed.ExternalObservation{}, awsclient.Wrap(resource.Ignore(sns.IsNotFound, err), errGetTopicAttributesFailed)
 	}
+	if err := sns.CheckAttributesComplete(topicAttributes.Attributes); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	// A Topic imported by name is addressed by its ARN from now on, like one
+	// that was created by this controller.
+	if topicArn != meta.GetExternalName(cr) {
+		if err := awsclient.PersistExternalName(ctx, c.kube, cr, topicArn); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+	}
 
 	//Get all the tags on sns topic
 	topicTags, err := c.client.ListTagsForResource(ctx,&awssns.ListTagsForResourceInput{
@@ -154,7 +398,7 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 
 	current := cr.Spec.ForProvider.DeepCopy()
 	// LateInitialize to update tags and topic parameters which are auto generated after topic creation
-	sns.LateInitialize(&cr.Spec.ForProvider,topicAttributes.Attributes,topicTags.Tags)
+	sns.LateInitialize(&cr.Spec.ForProvider,topicAttributes.Attributes,sns.WithoutDefaultTags(topicTags.Tags, c.defaultTags))
 	if !cmp.Equal(current, &cr.Spec.ForProvider){
 		err := c.kube.Update(ctx,cr)
 		if err != nil {
@@ -163,7 +407,25 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 
 	cr.Status.SetConditions(xpv1.Available())
-	cr.Status.AtProvider = sns.GenerateObservation(topicAttributes.Attributes)
+	c.checkPartition(cr, topicAttributes.Attributes[snsv1alpha1.TopicArn])
+	if err := c.checkShared(ctx, cr); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+	previous := cr.Status.AtProvider
+	cr.Status.AtProvider = sns.GenerateObservation(meta.GetExternalName(cr), topicAttributes.Attributes)
+	sns.ObserveTags(&cr.Status.AtProvider, previous, topicTags.Tags, c.currentTime())
+	if c.observeSubscriptionProtocols {
+		counts, err := sns.CountSubscriptionsByProtocol(ctx, c.client, meta.GetExternalName(cr))
+		if err != nil {
+			return managed.ExternalObservation{}, awsclient.Wrap(err, errListSubscriptionsFailed)
+		}
+		cr.Status.AtProvider.SubscriptionsByProtocol = counts
+	}
+	cr.Status.AtProvider.CostTags = sns.GenerateCostTags(c.costTags, topicTags.Tags)
+	if awsclient.ExceedsObjectSize(cr, c.maxObjectSize) {
+		c.log.Info("Topic is approaching the maximum object size, dropping optional status fields", "name", cr.GetName(), "maxObjectSize", c.maxObjectSize)
+		sns.TrimObservation(&cr.Status.AtProvider)
+	}
 
 	// These fmt statements should be removed in the real implementation.
 	fmt.Printf("Observing: %+v", cr)
@@ -177,7 +439,7 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		// Return false when the external resource exists, but it not up to date
 		// with the desired managed resource state. This lets the managed
 		// resource reconciler know that it needs to call Update.
-		ResourceUpToDate: sns.IsUpToDate(cr.Spec.ForProvider,topicAttributes.Attributes,topicTags.Tags),
+		ResourceUpToDate: sns.IsUpToDate(sns.WithDefaultTags(cr.Spec.ForProvider, c.defaultTags),topicAttributes.Attributes,topicTags.Tags),
 
 		// Return any details that may be required to connect to the external
 		// resource. These will be stored as the connection secret.
@@ -185,12 +447,132 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}, nil
 }
 
+// checkEncryption denies Topics without a KMS master key if the
+// ProviderConfig requires encryption, and Topics encrypted with the AWS
+// managed key if it requires a customer managed key. Denied Topics aren't
+// reconciled until a suitable key is set. Topics that are being deleted aren't
+// denied whatever their key, so that their topic can still be deleted.
+func (c *external) checkEncryption(cr *snsv1alpha1.Topic) error {
+	if !c.requireEncryption && !c.requireCMK || meta.WasDeleted(cr) {
+		return nil
+	}
+	key := aws.ToString(cr.Spec.ForProvider.KMSMasterKeyID)
+	if key == "" {
+		err := errors.New(errUnencryptedTopic)
+		cr.SetConditions(snsv1alpha1.EncryptionNotCompliant(err))
+		return err
+	}
+	if c.requireCMK && !aws.ToBool(sns.UsesCustomerManagedKey(key)) {
+		err := errors.New(errAWSManagedKey)
+		cr.SetConditions(snsv1alpha1.EncryptionKeyNotCompliant(err))
+		return err
+	}
+	cr.SetConditions(snsv1alpha1.EncryptionCompliant())
+	return nil
+}
+
+// checkARNUnchanged refuses to reconcile a Topic whose spec implies another
+// ARN than that of the topic it manages, rather than orphaning that topic.
+func (c *external) checkARNUnchanged(cr *snsv1alpha1.Topic, topicArn string) error {
+	if err := sns.CheckARNUnchanged(cr.Spec.ForProvider, cr.Status.AtProvider.TopicArn, topicArn); err != nil {
+		cr.SetConditions(snsv1alpha1.ARNChanged(err))
+		return err
+	}
+	cr.SetConditions(snsv1alpha1.ARNUnchanged())
+	return nil
+}
+
+// checkPartition warns about a Topic whose ARN, as returned by SNS, isn't in
+// the partition of its region. The Topic is still reconciled.
+func (c *external) checkPartition(cr *snsv1alpha1.Topic, topicArn string) {
+	if err := sns.CheckPartition(cr.Spec.ForProvider.Region, topicArn); err != nil {
+		c.log.Info("Topic ARN is in an unexpected partition", "name", cr.GetName(), "error", err.Error())
+		cr.SetConditions(snsv1alpha1.PartitionMismatch(err))
+		return
+	}
+	cr.SetConditions(snsv1alpha1.PartitionMatch())
+}
+
+// checkShared warns about other Topics that address the same topic as the
+// supplied one, e.g. because two teams picked the same name. They'd silently
+// overwrite each other's attributes and tags, and the first to be deleted
+// would delete the topic of the other. The Topic is still reconciled.
+func (c *external) checkShared(ctx context.Context, cr *snsv1alpha1.Topic) error {
+	if !c.detectShared {
+		return nil
+	}
+	// Only the Topics that may address the same topic are listed, from the
+	// index registered by SetupTopic, rather than every Topic.
+	var topics []snsv1alpha1.Topic
+	for _, v := range sns.SharedIndexValues(cr, meta.GetExternalName(cr)) {
+		l := &snsv1alpha1.TopicList{}
+		if err := c.kube.List(ctx, l, client.MatchingFields{sns.IndexKeyTopic: v}); err != nil {
+			return errors.Wrap(err, errListSharedTopics)
+		}
+		topics = append(topics, l.Items...)
+	}
+	if shared := sns.SharedWith(cr, meta.GetExternalName(cr), topics); len(shared) > 0 {
+		err := errors.Errorf("topic %s is also managed by Topics %s", meta.GetExternalName(cr), strings.Join(shared, ", "))
+		c.log.Info("Topic shares its topic with other Topics", "name", cr.GetName(), "error", err.Error())
+		cr.SetConditions(snsv1alpha1.Shared(err))
+		return nil
+	}
+	cr.SetConditions(snsv1alpha1.Exclusive())
+	return nil
+}
+
+// checkNotExisting returns an error if a topic of the supplied name already
+// exists and the supplied Topic must not adopt it. CreateTopic would
+// otherwise return the ARN of the existing topic, which the Topic would then
+// silently manage.
+func (c *external) checkNotExisting(ctx context.Context, cr *snsv1alpha1.Topic, name string) error {
+	if !sns.FailsOnExisting(cr.Spec.ForProvider) || sns.CreatedTopic(cr, name) {
+		return nil
+	}
+	topicArn, err := c.resolver.ResolveTopicARN(ctx, cr.Spec.ForProvider.Region, name)
+	if err != nil {
+		return errors.Wrap(err, errProbeExistingTopic)
+	}
+	_, err = c.client.GetTopicAttributes(ctx, &awssns.GetTopicAttributesInput{TopicArn: aws.String(topicArn)})
+	switch {
+	case sns.IsNotFound(err):
+		return nil
+	case err != nil:
+		return awsclient.Wrap(err, errProbeExistingTopic)
+	}
+	return errors.Errorf("topic %s already exists, set the external name to its ARN or the existing resource policy to %s to manage it", topicArn, snsv1alpha1.ExistingResourcePolicyAdopt)
+}
+
+// checkMaintenanceWindow returns an error if the supplied Topic is in its
+// maintenance window, during which it's neither created, updated nor deleted.
+func (c *external) checkMaintenanceWindow(cr *snsv1alpha1.Topic) error {
+	return sns.CheckMaintenanceWindow(cr.Spec.ForProvider.MaintenanceWindow, c.currentTime())
+}
+
+// currentTime returns the current time, as told by the clock of the client.
+func (c *external) currentTime() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	ctx, cancel := awsclient.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
 	fmt.Printf("Inside Create function............................")
 	cr, ok := mg.(*snsv1alpha1.Topic)
 	if !ok {
 		return managed.ExternalCreation{}, errors.New(errNotTopic)
 	}
+	if err := c.checkMaintenanceWindow(cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+
+	if err := c.checkEncryption(cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
 
 	cr.SetConditions(xpv1.Creating())
 
@@ -200,11 +582,25 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	if name == ""{
 		name = cr.GetName()
 	}
+	if err := sns.CheckNamePattern(name, c.namePattern); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	if err := c.checkNotExisting(ctx, cr, name); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	if sns.FailsOnExisting(cr.Spec.ForProvider) && !sns.CreatedTopic(cr, name) {
+		// Record the topic as ours before creating it, so that it isn't
+		// mistaken for an existing topic should its ARN fail to persist.
+		if err := awsclient.PersistAnnotation(ctx, c.kube, cr, sns.AnnotationKeyCreatedTopic, name); err != nil {
+			return managed.ExternalCreation{}, err
+		}
+	}
 
 	// Convert Tags map to []types.Tag as required by CreateTopicInput
-	t := make([]types.Tag,len(cr.Spec.ForProvider.Tags))
+	tags := awsclient.MergeTags(c.defaultTags, cr.Spec.ForProvider.Tags)
+	t := make([]types.Tag,len(tags))
 	i := 0
-	for k,v := range cr.Spec.ForProvider.Tags{
+	for k,v := range tags{
 		t[i] = types.Tag{
 			Key: aws.String(k),
 			Value: aws.String(v),
@@ -224,11 +620,12 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 	// Changing the external name to full TopicArn as
 	// AWS APIs doesn't provide any option to get ARN using TopicName
-	// Neither do they treat TopicName as identifier
-	meta.SetExternalName(cr,*resp.TopicArn)
-	conn := managed.ConnectionDetails{
-		xpv1.ResourceCredentialsSecretEndpointKey: []byte(*resp.TopicArn),
+	// Neither do they treat TopicName as identifier. It's persisted right
+	// away since losing it to a conflicting write would create the Topic again.
+	if err := awsclient.PersistExternalName(ctx, c.kube, cr, aws.ToString(resp.TopicArn)); err != nil {
+		return managed.ExternalCreation{}, err
 	}
+	conn := sns.TopicConnectionDetails(aws.ToString(resp.TopicArn), cr.Spec.ForProvider.Region)
 
 	return managed.ExternalCreation{
 		// Optionally return any details that may be required to connect to the
@@ -238,11 +635,17 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 }
 
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	ctx, cancel := awsclient.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
 	fmt.Printf("Inside Update function............................")
 	cr, ok := mg.(*snsv1alpha1.Topic)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotTopic)
 	}
+	if err := c.checkMaintenanceWindow(cr); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
 
 
 	fmt.Printf("Updating: %+v", cr)
@@ -254,19 +657,39 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	if err != nil {
 		return managed.ExternalUpdate{}, awsclient.Wrap(resource.Ignore(sns.IsNotFound, err), errGetTopicAttributesFailed)
 	}
+	if err := sns.CheckAttributesComplete(topicAttributes.Attributes); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+
+	// Audit whatever was changed, even if the update fails part way.
+	var set map[string]string
+	var added []types.Tag
+	var removed []string
+	defer func() { c.audit(ctx, cr, set, added, removed) }()
 
 	// Identifying changed attributes and updating them in external resource
 	diffAttributes := sns.GetAttributeDiff(cr.Spec.ForProvider,topicAttributes.Attributes)
 	if diffAttributes != nil{
-		for k,v := range diffAttributes{
-			_, err := c.client.SetTopicAttributes(ctx,&awssns.SetTopicAttributesInput{
-				TopicArn: aws.String(meta.GetExternalName(cr)),
-				AttributeName: &k,
-				AttributeValue: &v,
-			})
-			if err != nil{
-				return managed.ExternalUpdate{},awsclient.Wrap(err,errKubeUpdateFailed)
-			}
+		var failed map[string]error
+		set, failed = c.setAttributes(ctx, cr, diffAttributes)
+
+		// Read the attributes back, so that the observation reflects the
+		// attributes SNS applied even if some of them couldn't be set.
+		resp, err := c.client.GetTopicAttributes(ctx, &awssns.GetTopicAttributesInput{
+			TopicArn: aws.String(meta.GetExternalName(cr)),
+		})
+		if err != nil {
+			return managed.ExternalUpdate{}, awsclient.Wrap(err, errGetTopicAttributesFailed)
+		}
+		if err := sns.CheckAttributesComplete(resp.Attributes); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		c.observeAttributes(cr, resp.Attributes)
+		if c.verifyAttributes && len(set) > 0 {
+			verifyAttributesApplied(cr, set, resp.Attributes)
+		}
+		if len(failed) > 0 {
+			return managed.ExternalUpdate{}, setAttributesFailed(cr, failed)
 		}
 	}
 
@@ -279,15 +702,16 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	// Identifying changes in tags and updating external resource accordingly
-	addTags,removeTags := sns.GetDiffTags(cr.Spec.ForProvider,topicTags.Tags)
+	addTags,removeTags := sns.GetDiffTags(sns.WithDefaultTags(cr.Spec.ForProvider, c.defaultTags),topicTags.Tags)
 	if removeTags != nil{
 		_, err := c.client.UntagResource(ctx,&awssns.UntagResourceInput{
 			ResourceArn: aws.String(meta.GetExternalName(cr)),
 			TagKeys: removeTags,
 		})
 		if err != nil{
-			return managed.ExternalUpdate{},awsclient.Wrap(err,errKubeUpdateFailed)
+			return managed.ExternalUpdate{}, updateFailed(cr, err, errUntag)
 		}
+		removed = removeTags
 	}
 	if addTags != nil{
 		_, err := c.client.TagResource(ctx,&awssns.TagResourceInput{
@@ -295,13 +719,18 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 			Tags: addTags,
 		})
 		if err != nil{
-			return managed.ExternalUpdate{},awsclient.Wrap(err,errKubeUpdateFailed)
+			return managed.ExternalUpdate{}, updateFailed(cr, err, errTag)
 		}
+		added = addTags
 	}
-
-	conn := managed.ConnectionDetails{
-		xpv1.ResourceCredentialsSecretEndpointKey: []byte(meta.GetExternalName(cr)),
+	if c.verifyTags && (addTags != nil || removeTags != nil) {
+		if err := c.verifyTagsApplied(ctx, cr, addTags, removeTags); err != nil {
+			return managed.ExternalUpdate{}, err
+		}
 	}
+	cr.SetConditions(snsv1alpha1.UpdateComplete())
+
+	conn := sns.TopicConnectionDetails(meta.GetExternalName(cr), cr.Spec.ForProvider.Region)
 
 	return managed.ExternalUpdate{
 		// Optionally return any details that may be required to connect to the
@@ -310,11 +739,161 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	}, nil
 }
 
+// updateFailed wraps the supplied error of an update of the supplied Topic
+// with the supplied message. Errors that say AWS throttled the update, or that
+// it conflicted with a concurrent one, are also reported with a condition,
+// and returned as such so that the update is retried with backoff.
+func updateFailed(cr *snsv1alpha1.Topic, err error, msg string) error {
+	err = awsclient.Wrap(err, msg)
+	if sns.IsTransient(err) {
+		cr.SetConditions(snsv1alpha1.UpdateThrottled(err))
+		return errors.Wrap(err, errUpdateThrottled)
+	}
+	return err
+}
+
+// audit records the supplied attributes and tags that were changed on the
+// supplied Topic, if any. Only those that were actually applied should be
+// supplied, rather than all those that differed.
+func (c *external) audit(ctx context.Context, cr *snsv1alpha1.Topic, set map[string]string, add []types.Tag, remove []string) {
+	if c.auditor == nil || (len(set) == 0 && len(add) == 0 && len(remove) == 0) {
+		return
+	}
+	e := awsclient.AuditEvent{Kind: snsv1alpha1.TopicKind, Name: cr.GetName(), ARN: meta.GetExternalName(cr)}
+	for k := range set {
+		e.Attributes = append(e.Attributes, k)
+	}
+	added := map[string]bool{}
+	for _, t := range add {
+		added[aws.ToString(t.Key)] = true
+		e.TagsAdded = append(e.TagsAdded, aws.ToString(t.Key))
+	}
+	// Changed tags are removed before they're added again.
+	for _, k := range remove {
+		if !added[k] {
+			e.TagsRemoved = append(e.TagsRemoved, k)
+		}
+	}
+	sort.Strings(e.Attributes)
+	sort.Strings(e.TagsAdded)
+	sort.Strings(e.TagsRemoved)
+	c.auditor.Audit(ctx, e)
+}
+
+// setAttributes sets each of the supplied attributes of the supplied Topic.
+// It carries on past attributes that can't be set, so that one rejected
+// attribute doesn't hold up the others, and returns the attributes that were
+// set and the errors of those that weren't.
+func (c *external) setAttributes(ctx context.Context, cr *snsv1alpha1.Topic, attributes map[string]string) (map[string]string, map[string]error) {
+	set := map[string]string{}
+	failed := map[string]error{}
+	for k, v := range attributes {
+		// Copy the attribute, rather than taking the addresses of the loop
+		// variables that are reused by each iteration.
+		name, value := k, v
+		_, err := c.client.SetTopicAttributes(ctx, &awssns.SetTopicAttributesInput{
+			TopicArn:       aws.String(meta.GetExternalName(cr)),
+			AttributeName:  &name,
+			AttributeValue: &value,
+		})
+		if err != nil {
+			failed[k] = err
+			continue
+		}
+		set[k] = v
+	}
+	return set, failed
+}
+
+// setAttributesFailed returns an error naming each of the supplied attributes
+// that couldn't be set. Like updateFailed, it also reports the failure with a
+// condition if every attribute was throttled.
+func setAttributesFailed(cr *snsv1alpha1.Topic, failed map[string]error) error {
+	names := make([]string, 0, len(failed))
+	transient := true
+	for k, err := range failed {
+		names = append(names, k)
+		transient = transient && sns.IsTransient(err)
+	}
+	sort.Strings(names)
+
+	var err error
+	if len(names) == 1 {
+		err = errors.Wrap(awsclient.Wrap(failed[names[0]], names[0]), errSetTopicAttributesFailed)
+	} else {
+		msgs := make([]string, len(names))
+		for i, k := range names {
+			msgs[i] = awsclient.Wrap(failed[k], k).Error()
+		}
+		err = errors.Errorf("%s: %s", errSetTopicAttributesFailed, strings.Join(msgs, "; "))
+	}
+	if transient {
+		cr.SetConditions(snsv1alpha1.UpdateThrottled(err))
+		return errors.Wrap(err, errUpdateThrottled)
+	}
+	return err
+}
+
+// observeAttributes refreshes the parts of the observation of the supplied
+// Topic that are derived from the supplied attributes. The subscriptions and
+// tags are only observed by Observe, and are kept.
+func (c *external) observeAttributes(cr *snsv1alpha1.Topic, attributes map[string]string) {
+	previous := cr.Status.AtProvider
+	cr.Status.AtProvider = sns.GenerateObservation(meta.GetExternalName(cr), attributes)
+	cr.Status.AtProvider.SubscriptionsByProtocol = previous.SubscriptionsByProtocol
+	cr.Status.AtProvider.Tags = previous.Tags
+	cr.Status.AtProvider.TagsObservedAt = previous.TagsObservedAt
+	cr.Status.AtProvider.CostTags = previous.CostTags
+	if awsclient.ExceedsObjectSize(cr, c.maxObjectSize) {
+		sns.TrimObservation(&cr.Status.AtProvider)
+	}
+}
+
+// verifyAttributesApplied reports whether SNS applied the supplied attributes
+// that were set, according to the supplied attributes that were read back
+// afterwards, with a condition. Attributes may not be applied due to eventual
+// consistency, or SNS silently ignoring some values.
+func verifyAttributesApplied(cr *snsv1alpha1.Topic, set, attributes map[string]string) {
+	if err := sns.CheckAttributesApplied(cr.Spec.ForProvider, set, attributes); err != nil {
+		cr.SetConditions(snsv1alpha1.AttributesNotApplied(err))
+		return
+	}
+	cr.SetConditions(snsv1alpha1.AttributesApplied())
+}
+
+// verifyTagsApplied lists the tags of the supplied Topic again after the
+// supplied tags were added and removed, and reports whether SNS applied the
+// changes with a condition. Tags may not be applied due to eventual
+// consistency, or permissions that silently drop some tags.
+func (c *external) verifyTagsApplied(ctx context.Context, cr *snsv1alpha1.Topic, add []types.Tag, remove []string) error {
+	resp, err := c.client.ListTagsForResource(ctx, &awssns.ListTagsForResourceInput{
+		ResourceArn: aws.String(meta.GetExternalName(cr)),
+	})
+	if err != nil {
+		return awsclient.Wrap(err, errListTopicTagsFailed)
+	}
+	if err := sns.CheckTagsApplied(add, remove, resp.Tags); err != nil {
+		cr.SetConditions(snsv1alpha1.TagsNotApplied(err))
+		return nil
+	}
+	cr.SetConditions(snsv1alpha1.TagsApplied())
+	return nil
+}
+
 func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	ctx, cancel := awsclient.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
 	cr, ok := mg.(*snsv1alpha1.Topic)
 	if !ok {
 		return errors.New(errNotTopic)
 	}
+	if err := c.checkMaintenanceWindow(cr); err != nil {
+		return err
+	}
+	if err := awsclient.CheckDeletionProtection(cr); err != nil {
+		return err
+	}
 
 	fmt.Printf("Deleting: %+v", cr)
 
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// A statusCoalescingManager hands out a client that skips no-op status
+// updates to the controllers it sets up.
+type statusCoalescingManager struct {
+	ctrl.Manager
+	client client.Client
+}
+
+func newStatusCoalescingManager(mgr ctrl.Manager) ctrl.Manager {
+	return &statusCoalescingManager{Manager: mgr, client: &statusCoalescingClient{Client: mgr.GetClient()}}
+}
+
+func (m *statusCoalescingManager) GetClient() client.Client {
+	return m.client
+}
+
+// A statusCoalescingClient skips status updates that wouldn't change the
+// status stored by the API server. The managed reconciler writes the status
+// after every observation, which for a large number of resources polled
+// frequently is mostly no-op writes.
+type statusCoalescingClient struct {
+	client.Client
+}
+
+func (c *statusCoalescingClient) Status() client.StatusWriter {
+	return &statusCoalescingWriter{StatusWriter: c.Client.Status(), reader: c.Client}
+}
+
+type statusCoalescingWriter struct {
+	client.StatusWriter
+	reader client.Reader
+}
+
+// Update writes the status of the supplied object unless the stored version
+// of the object already has the same status. The stored version is only
+// trusted if it's the version the supplied object was read at, so a stale
+// cache results in a write rather than a lost update.
+func (w *statusCoalescingWriter) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	current, ok := obj.DeepCopyObject().(client.Object)
+	if ok && w.reader.Get(ctx, client.ObjectKeyFromObject(obj), current) == nil &&
+		current.GetResourceVersion() == obj.GetResourceVersion() && statusEqual(obj, current) {
+		return nil
+	}
+	return w.StatusWriter.Update(ctx, obj, opts...)
+}
+
+// statusEqual returns true if the supplied objects have the same status.
+func statusEqual(a, b client.Object) bool {
+	ua, err := runtime.DefaultUnstructuredConverter.ToUnstructured(a)
+	if err != nil {
+		return false
+	}
+	ub, err := runtime.DefaultUnstructuredConverter.ToUnstructured(b)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(ua["status"], ub["status"])
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	snsv1alpha1 "provider-aws-controlapi/apis/sns/v1alpha1"
+)
+
+func observedTopic(resourceVersion string, c ...xpv1.Condition) *snsv1alpha1.Topic {
+	cr := &snsv1alpha1.Topic{}
+	cr.SetName("example")
+	cr.SetResourceVersion(resourceVersion)
+	cr.Status.AtProvider.TopicArn = aws.String("arn:aws:sns:us-west-2:123456789012:example")
+	cr.SetConditions(c...)
+	return cr
+}
+
+func TestStatusCoalescingWriterUpdate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		stored *snsv1alpha1.Topic
+		getErr error
+		obj    *snsv1alpha1.Topic
+		writes int
+	}{
+		"Unchanged": {
+			reason: "No status write should occur when nothing changed.",
+			stored: observedTopic("1", xpv1.Available(), xpv1.ReconcileSuccess()),
+			obj:    observedTopic("1", xpv1.Available(), xpv1.ReconcileSuccess()),
+			writes: 0,
+		},
+		"ConditionChanged": {
+			reason: "A condition transition should be written.",
+			stored: observedTopic("1", xpv1.Creating(), xpv1.ReconcileSuccess()),
+			obj:    observedTopic("1", xpv1.Available(), xpv1.ReconcileSuccess()),
+			writes: 1,
+		},
+		"ObservationChanged": {
+			reason: "A change to the observation should be written.",
+			stored: observedTopic("1", xpv1.Available()),
+			obj: func() *snsv1alpha1.Topic {
+				cr := observedTopic("1", xpv1.Available())
+				cr.Status.AtProvider.SubscriptionsPending = new(int)
+				return cr
+			}(),
+			writes: 1,
+		},
+		"StaleCache": {
+			reason: "The status should be written if the stored version differs from the updated one.",
+			stored: observedTopic("1", xpv1.Available()),
+			obj:    observedTopic("2", xpv1.Available()),
+			writes: 1,
+		},
+		"GetFailed": {
+			reason: "The status should be written if the stored version can't be read.",
+			getErr: errBoom,
+			obj:    observedTopic("1", xpv1.Available()),
+			writes: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			writes := 0
+			kube := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					if tc.getErr != nil {
+						return tc.getErr
+					}
+					tc.stored.DeepCopyInto(obj.(*snsv1alpha1.Topic))
+					return nil
+				},
+				MockStatusUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+					writes++
+					return nil
+				},
+			}
+			c := &statusCoalescingClient{Client: kube}
+			if err := c.Status().Update(context.Background(), tc.obj); err != nil {
+				t.Errorf("\n%s\nStatus().Update(...): %s\n", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.writes, writes); diff != "" {
+				t.Errorf("\n%s\nStatus().Update(...): -want writes, +got writes:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package export converts managed resources into CloudFormation templates,
+// e.g. to migrate them off Crossplane or to document them.
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	snsv1alpha1 "provider-aws-controlapi/apis/sns/v1alpha1"
+	"provider-aws-controlapi/internal/clients/sns"
+)
+
+// CloudFormation resource types.
+const (
+	TypeTopic       = "AWS::SNS::Topic"
+	TypeTopicPolicy = "AWS::SNS::TopicPolicy"
+)
+
+const templateFormatVersion = "2010-09-09"
+
+const (
+	errDecodeManifest   = "cannot decode manifest"
+	errConvertObject    = "cannot convert %s"
+	errParseAttribute   = "cannot parse attribute %s of Topic %s"
+	errDuplicateLogical = "logical ID %s of Topic %s is already used"
+)
+
+// A Template is a CloudFormation template.
+type Template struct {
+	AWSTemplateFormatVersion string              `json:"AWSTemplateFormatVersion"`
+	Resources                map[string]Resource `json:"Resources"`
+}
+
+// A Resource of a CloudFormation template.
+type Resource struct {
+	Type       string                 `json:"Type"`
+	Properties map[string]interface{} `json:"Properties,omitempty"`
+}
+
+// NewTemplate returns an empty CloudFormation template.
+func NewTemplate() *Template {
+	return &Template{AWSTemplateFormatVersion: templateFormatVersion, Resources: map[string]Resource{}}
+}
+
+// AddManifests adds the resources of every Topic in the supplied stream of
+// YAML or JSON documents to the template. Other kinds are ignored.
+func (t *Template) AddManifests(r io.Reader) error {
+	d := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		u := &unstructured.Unstructured{}
+		if err := d.Decode(&u.Object); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return errors.Wrap(err, errDecodeManifest)
+		}
+		if u.GroupVersionKind() != snsv1alpha1.TopicGroupVersionKind {
+			continue
+		}
+		cr := &snsv1alpha1.Topic{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, cr); err != nil {
+			return errors.Wrapf(err, errConvertObject, snsv1alpha1.TopicKind)
+		}
+		if err := t.AddTopic(cr); err != nil {
+			return err
+		}
+	}
+}
+
+// AddTopic adds an AWS::SNS::Topic resource with the resolved configuration
+// of the supplied Topic to the template. The access policy of the topic isn't
+// a property of AWS::SNS::Topic, so a Topic with a policy also gets an
+// AWS::SNS::TopicPolicy resource referencing the topic.
+func (t *Template) AddTopic(cr *snsv1alpha1.Topic) error {
+	id := LogicalID(cr.GetName())
+	if _, ok := t.Resources[id]; ok {
+		return errors.Errorf(errDuplicateLogical, id, cr.GetName())
+	}
+
+	name := meta.GetExternalName(cr)
+	if a, err := arn.Parse(name); err == nil {
+		name = a.Resource
+	}
+	if name == "" {
+		name = cr.GetName()
+	}
+	props := map[string]interface{}{"TopicName": name}
+
+	// The properties of AWS::SNS::Topic are named after the topic attributes,
+	// except for the delivery status logging attributes, which are grouped by
+	// protocol.
+	p := *cr.Spec.ForProvider.DeepCopy()
+	p.DeliveryStatusLogging = nil
+	for k, v := range sns.GenerateTopicAttributeMap(p) {
+		switch k {
+		case snsv1alpha1.TopicPolicy:
+			continue
+		case snsv1alpha1.FifoTopic, snsv1alpha1.FifoTopicContentBasedDeduplication:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return errors.Wrapf(err, errParseAttribute, k, cr.GetName())
+			}
+			props[k] = b
+		case snsv1alpha1.TopicDeliveryPolicy, snsv1alpha1.TopicArchivePolicy:
+			// DeliveryPolicy and ArchivePolicy are JSON objects rather than
+			// strings.
+			var doc interface{}
+			if err := json.Unmarshal([]byte(v), &doc); err != nil {
+				return errors.Wrapf(err, errParseAttribute, k, cr.GetName())
+			}
+			props[k] = doc
+		default:
+			props[k] = v
+		}
+	}
+	if l := deliveryStatusLogging(cr.Spec.ForProvider.DeliveryStatusLogging); len(l) > 0 {
+		props["DeliveryStatusLogging"] = l
+	}
+	if len(cr.Spec.ForProvider.Tags) > 0 {
+		keys := make([]string, 0, len(cr.Spec.ForProvider.Tags))
+		for k := range cr.Spec.ForProvider.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		tags := make([]map[string]string, len(keys))
+		for i, k := range keys {
+			tags[i] = map[string]string{"Key": k, "Value": cr.Spec.ForProvider.Tags[k]}
+		}
+		props["Tags"] = tags
+	}
+	t.Resources[id] = Resource{Type: TypeTopic, Properties: props}
+
+	if p := cr.Spec.ForProvider.Policy; p != nil && *p != "" {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(*p), &doc); err != nil {
+			return errors.Wrapf(err, errParseAttribute, snsv1alpha1.TopicPolicy, cr.GetName())
+		}
+		t.Resources[id+"Policy"] = Resource{Type: TypeTopicPolicy, Properties: map[string]interface{}{
+			"PolicyDocument": doc,
+			"Topics":         []interface{}{map[string]string{"Ref": id}},
+		}}
+	}
+	return nil
+}
+
+// LogicalID returns the CloudFormation logical ID of the resource converted
+// from the object with the supplied name. Logical IDs must be alphanumeric,
+// so e.g. orders.fifo becomes OrdersFifo.
+func LogicalID(name string) string {
+	b := &strings.Builder{}
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) || r > unicode.MaxASCII {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// deliveryStatusLogging returns the DeliveryStatusLogging property of an
+// AWS::SNS::Topic, which has a LoggingConfig for each protocol.
+func deliveryStatusLogging(l *snsv1alpha1.DeliveryStatusLogging) []map[string]string {
+	if l == nil {
+		return nil
+	}
+	protocols := []struct {
+		name   string
+		config *snsv1alpha1.DeliveryStatusLoggingConfig
+	}{
+		{name: "http/s", config: l.HTTP},
+		{name: "sqs", config: l.SQS},
+		{name: "lambda", config: l.Lambda},
+		{name: "application", config: l.Application},
+		{name: "firehose", config: l.Firehose},
+	}
+	var out []map[string]string
+	for _, p := range protocols {
+		if p.config == nil {
+			continue
+		}
+		c := map[string]string{"Protocol": p.name}
+		if p.config.SuccessFeedbackRoleARN != nil {
+			c["SuccessFeedbackRoleArn"] = *p.config.SuccessFeedbackRoleARN
+		}
+		if p.config.SuccessFeedbackSampleRate != nil {
+			c["SuccessFeedbackSampleRate"] = strconv.Itoa(int(*p.config.SuccessFeedbackSampleRate))
+		}
+		if p.config.FailureFeedbackRoleARN != nil {
+			c["FailureFeedbackRoleArn"] = *p.config.FailureFeedbackRoleARN
+		}
+		out = append(out, c)
+	}
+	return out
+}
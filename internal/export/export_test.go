@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+const manifests = `
+apiVersion: sns.awscontrolapi.crossplane.io/v1alpha1
+kind: Topic
+metadata:
+  name: orders.fifo
+spec:
+  forProvider:
+    region: us-east-1
+    displayName: Orders
+    fifoTopic: true
+    contentBasedDeduplication: true
+    kmsMasterKeyId: alias/aws/sns
+    deliveryPolicy: |
+      {"http": {"defaultHealthyRetryPolicy": {"numRetries": 3}}}
+    policy: |
+      {"Version": "2012-10-17", "Statement": []}
+    deliveryStatusLogging:
+      sqs:
+        successFeedbackRoleArn: arn:aws:iam::123456789012:role/sns-logging
+        successFeedbackSampleRate: 10
+        failureFeedbackRoleArn: arn:aws:iam::123456789012:role/sns-logging
+    tags:
+      team: payments
+      env: prod
+---
+apiVersion: sns.awscontrolapi.crossplane.io/v1alpha1
+kind: Topic
+metadata:
+  name: events
+  annotations:
+    crossplane.io/external-name: arn:aws:sns:us-east-1:123456789012:legacy-events
+spec:
+  forProvider:
+    region: us-east-1
+---
+apiVersion: sns.awscontrolapi.crossplane.io/v1alpha1
+kind: Subscription
+metadata:
+  name: orders
+spec:
+  forProvider:
+    region: us-east-1
+    topicArn: arn:aws:sns:us-east-1:123456789012:orders.fifo
+    protocol: sqs
+`
+
+const duplicateManifests = `
+apiVersion: sns.awscontrolapi.crossplane.io/v1alpha1
+kind: Topic
+metadata:
+  name: orders
+spec:
+  forProvider:
+    region: us-east-1
+---
+apiVersion: sns.awscontrolapi.crossplane.io/v1alpha1
+kind: Topic
+metadata:
+  name: orders-
+spec:
+  forProvider:
+    region: eu-west-1
+`
+
+func TestAddManifests(t *testing.T) {
+	cases := map[string]struct {
+		reason    string
+		manifests string
+		want      map[string]Resource
+		err       error
+	}{
+		"Topics": {
+			reason:    "Every Topic should be exported with its resolved configuration, and its policy as a separate resource.",
+			manifests: manifests,
+			want: map[string]Resource{
+				"OrdersFifo": {
+					Type: TypeTopic,
+					Properties: map[string]interface{}{
+						"TopicName":                 "orders.fifo",
+						"DisplayName":               "Orders",
+						"FifoTopic":                 true,
+						"ContentBasedDeduplication": true,
+						"KmsMasterKeyId":            "alias/aws/sns",
+						"DeliveryPolicy": map[string]interface{}{
+							"http": map[string]interface{}{
+								"defaultHealthyRetryPolicy": map[string]interface{}{"numRetries": float64(3)},
+							},
+						},
+						"DeliveryStatusLogging": []map[string]string{{
+							"Protocol":                  "sqs",
+							"SuccessFeedbackRoleArn":    "arn:aws:iam::123456789012:role/sns-logging",
+							"SuccessFeedbackSampleRate": "10",
+							"FailureFeedbackRoleArn":    "arn:aws:iam::123456789012:role/sns-logging",
+						}},
+						"Tags": []map[string]string{
+							{"Key": "env", "Value": "prod"},
+							{"Key": "team", "Value": "payments"},
+						},
+					},
+				},
+				"OrdersFifoPolicy": {
+					Type: TypeTopicPolicy,
+					Properties: map[string]interface{}{
+						"PolicyDocument": map[string]interface{}{"Version": "2012-10-17", "Statement": []interface{}{}},
+						"Topics":         []interface{}{map[string]string{"Ref": "OrdersFifo"}},
+					},
+				},
+				"Events": {
+					Type:       TypeTopic,
+					Properties: map[string]interface{}{"TopicName": "legacy-events"},
+				},
+			},
+		},
+		"DuplicateLogicalID": {
+			reason:    "Topics whose names map to the same logical ID should be reported.",
+			manifests: duplicateManifests,
+			err:       errors.Errorf(errDuplicateLogical, "Orders", "orders-"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tpl := NewTemplate()
+			err := tpl.AddManifests(strings.NewReader(tc.manifests))
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nAddManifests(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want, tpl.Resources); diff != "" {
+				t.Errorf("\n%s\nAddManifests(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLogicalID(t *testing.T) {
+	cases := map[string]string{
+		"orders":           "Orders",
+		"orders.fifo":      "OrdersFifo",
+		"team-payments-v2": "TeamPaymentsV2",
+	}
+	for name, want := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := LogicalID(name); got != want {
+				t.Errorf("LogicalID(%q): want %q, got %q", name, want, got)
+			}
+		})
+	}
+}
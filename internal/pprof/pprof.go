@@ -0,0 +1,57 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pprof serves the runtime profiling endpoints of the provider.
+package pprof
+
+import (
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/pkg/errors"
+)
+
+const errRegisterHandler = "cannot register pprof handler"
+
+// A Registry registers extra HTTP handlers, e.g. the metrics server of a
+// controller manager.
+type Registry interface {
+	AddMetricsExtraHandler(path string, handler http.Handler) error
+}
+
+// handlers are the pprof endpoints, keyed by the path they're served at.
+var handlers = map[string]http.Handler{
+	"/debug/pprof/":        http.HandlerFunc(pprof.Index),
+	"/debug/pprof/cmdline": http.HandlerFunc(pprof.Cmdline),
+	"/debug/pprof/profile": http.HandlerFunc(pprof.Profile),
+	"/debug/pprof/symbol":  http.HandlerFunc(pprof.Symbol),
+	"/debug/pprof/trace":   http.HandlerFunc(pprof.Trace),
+}
+
+// Setup registers the pprof endpoints with the supplied registry if enabled
+// is true. The endpoints are served alongside the metrics, so they're only
+// reachable when the metrics server is.
+func Setup(r Registry, enabled bool) error {
+	if !enabled {
+		return nil
+	}
+	for path, h := range handlers {
+		if err := r.AddMetricsExtraHandler(path, h); err != nil {
+			return errors.Wrapf(err, "%s %s", errRegisterHandler, path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pprof
+
+import (
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+type registry struct {
+	paths []string
+	err   error
+}
+
+func (r *registry) AddMetricsExtraHandler(path string, _ http.Handler) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.paths = append(r.paths, path)
+	return nil
+}
+
+func TestSetup(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		paths []string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		enabled bool
+		err     error
+		want    want
+	}{
+		"Disabled": {
+			reason: "No endpoints should be registered when profiling is disabled.",
+		},
+		"Enabled": {
+			reason:  "The pprof endpoints should be registered when profiling is enabled.",
+			enabled: true,
+			want: want{paths: []string{
+				"/debug/pprof/",
+				"/debug/pprof/cmdline",
+				"/debug/pprof/profile",
+				"/debug/pprof/symbol",
+				"/debug/pprof/trace",
+			}},
+		},
+		"RegisterFailed": {
+			reason:  "Errors registering an endpoint should be returned.",
+			enabled: true,
+			err:     errBoom,
+			want:    want{err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &registry{err: tc.err}
+			err := Setup(r, tc.enabled)
+			if diff := cmp.Diff(tc.want.err, errors.Cause(err), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSetup(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			sort.Strings(r.paths)
+			if diff := cmp.Diff(tc.want.paths, r.paths); diff != "" {
+				t.Errorf("\n%s\nSetup(...): -want paths, +got paths:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
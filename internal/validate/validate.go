@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validate checks managed resource manifests offline, before they are
+// applied to a cluster.
+package validate
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	snsv1alpha1 "provider-aws-controlapi/apis/sns/v1alpha1"
+	"provider-aws-controlapi/internal/clients/sns"
+)
+
+const (
+	errDecodeManifest = "cannot decode manifest"
+	errConvertObject  = "cannot convert %s"
+)
+
+// Manifests validates every Topic and Subscription in the supplied stream of
+// YAML or JSON documents and returns one finding per problem, prefixed with
+// the kind and name of the offending object. Other kinds are ignored. An
+// error is returned only if the stream can't be decoded.
+func Manifests(r io.Reader) ([]string, error) {
+	var findings []string
+	d := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		u := &unstructured.Unstructured{}
+		if err := d.Decode(&u.Object); err != nil {
+			if err == io.EOF {
+				return findings, nil
+			}
+			return findings, errors.Wrap(err, errDecodeManifest)
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+		errs, err := object(u)
+		if err != nil {
+			return findings, err
+		}
+		for _, e := range errs {
+			findings = append(findings, fmt.Sprintf("%s/%s: %s", u.GetKind(), u.GetName(), e))
+		}
+	}
+}
+
+func object(u *unstructured.Unstructured) ([]error, error) {
+	switch u.GroupVersionKind() {
+	case snsv1alpha1.TopicGroupVersionKind:
+		t := &snsv1alpha1.Topic{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, t); err != nil {
+			return nil, errors.Wrapf(err, errConvertObject, snsv1alpha1.TopicKind)
+		}
+		return Topic(t), nil
+	case snsv1alpha1.SubscriptionGroupVersionKind:
+		s := &snsv1alpha1.Subscription{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, s); err != nil {
+			return nil, errors.Wrapf(err, errConvertObject, snsv1alpha1.SubscriptionKind)
+		}
+		return Subscription(s), nil
+	}
+	return nil, nil
+}
+
+// Topic validates the supplied Topic. The topic is created with its external
+// name, which defaults to the name of the object, or is named by the ARN set
+// as its external name.
+func Topic(cr *snsv1alpha1.Topic) []error {
+	name := meta.GetExternalName(cr)
+	if a, err := arn.Parse(name); err == nil {
+		name = a.Resource
+	}
+	if name == "" {
+		name = cr.GetName()
+	}
+	return sns.ValidateTopic(name, cr.Spec.ForProvider)
+}
+
+// Subscription validates the supplied Subscription.
+func Subscription(cr *snsv1alpha1.Subscription) []error {
+	if cr.Spec.ForProvider.FilterPolicy == nil {
+		return nil
+	}
+	if err := sns.ValidateFilterPolicy(aws.ToString(cr.Spec.ForProvider.FilterPolicy)); err != nil {
+		return []error{errors.Wrap(err, "invalid filterPolicy")}
+	}
+	return nil
+}
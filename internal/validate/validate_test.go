@@ -0,0 +1,138 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+const validManifests = `
+apiVersion: sns.awscontrolapi.crossplane.io/v1alpha1
+kind: Topic
+metadata:
+  name: orders.fifo
+spec:
+  forProvider:
+    region: us-east-1
+    fifoTopic: true
+    contentBasedDeduplication: true
+    kmsMasterKeyId: alias/aws/sns
+    policy: |
+      {"Version": "2012-10-17", "Statement": []}
+    tags:
+      team: payments
+---
+apiVersion: sns.awscontrolapi.crossplane.io/v1alpha1
+kind: Subscription
+metadata:
+  name: orders
+spec:
+  forProvider:
+    region: us-east-1
+    topicArn: arn:aws:sns:us-east-1:123456789012:orders.fifo
+    protocol: sqs
+    filterPolicy: |
+      {"store": ["example_corp"]}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored
+`
+
+const invalidManifests = `
+apiVersion: sns.awscontrolapi.crossplane.io/v1alpha1
+kind: Topic
+metadata:
+  name: orders
+  annotations:
+    crossplane.io/external-name: arn:aws:sns:us-east-1:123456789012:orders
+spec:
+  forProvider:
+    region: us-east-1
+    fifoTopic: true
+    kmsMasterKeyId: not-a-key
+---
+apiVersion: sns.awscontrolapi.crossplane.io/v1alpha1
+kind: Subscription
+metadata:
+  name: orders
+spec:
+  forProvider:
+    region: us-east-1
+    topicArn: arn:aws:sns:us-east-1:123456789012:orders
+    protocol: sqs
+    filterPolicy: |
+      {"store": "example_corp"}
+`
+
+func TestManifests(t *testing.T) {
+	type want struct {
+		findings []string
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason    string
+		manifests string
+		want      want
+	}{
+		"Valid": {
+			reason:    "Valid manifests should produce no findings, and unknown kinds should be ignored.",
+			manifests: validManifests,
+		},
+		"Invalid": {
+			reason:    "Every problem should be reported against the object it was found in.",
+			manifests: invalidManifests,
+			want: want{
+				findings: []string{
+					`Topic/orders: FIFO topic name "orders" must end with ".fifo"`,
+					`Topic/orders: kmsMasterKeyId "not-a-key" must be a key ID, key ARN, alias name or alias ARN`,
+					`Subscription/orders: invalid filterPolicy: filter policy attribute "store" must be an array of values or a nested object`,
+				},
+			},
+		},
+		"Empty": {
+			reason:    "Empty documents should be skipped.",
+			manifests: "---\n---\n",
+		},
+		"Malformed": {
+			reason:    "A stream that isn't YAML or JSON should return an error.",
+			manifests: "kind: [Topic",
+			want: want{
+				err: errors.Wrap(errors.New("error converting YAML to JSON: yaml: line 1: did not find expected ',' or ']'"), errDecodeManifest),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Manifests(strings.NewReader(tc.manifests))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nManifests(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.findings, got); diff != "" {
+				t.Errorf("\n%s\nManifests(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package version reports the build version and capabilities of the provider.
+package version
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Path is the path the capability report is served at.
+const Path = "/version"
+
+const errRegisterHandler = "cannot register version handler"
+
+// Version of the provider. It's set at build time with
+// -ldflags "-X provider-aws-controlapi/internal/version.Version=v0.1.0".
+var Version = "dev"
+
+// A Registry registers extra HTTP handlers, e.g. the metrics server of a
+// controller manager.
+type Registry interface {
+	AddMetricsExtraHandler(path string, handler http.Handler) error
+}
+
+// A Report describes the provider that's running.
+type Report struct {
+	// Version of the provider.
+	Version string `json:"version"`
+
+	// Kinds of the resources the provider reconciles.
+	Kinds []string `json:"kinds"`
+
+	// Features are the optional features of the provider, and whether
+	// they're enabled.
+	Features map[string]bool `json:"features"`
+}
+
+// NewReport returns a report of the provider version, the supplied kinds and
+// the supplied optional features.
+func NewReport(kinds []string, features map[string]bool) Report {
+	k := append([]string{}, kinds...)
+	sort.Strings(k)
+	return Report{Version: Version, Kinds: k, Features: features}
+}
+
+// ServeHTTP serves the report as JSON.
+func (r Report) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(r)
+}
+
+// Setup registers the supplied report with the supplied registry. It's served
+// alongside the metrics, so it's only reachable when the metrics server is.
+func Setup(r Registry, report Report) error {
+	return errors.Wrap(r.AddMetricsExtraHandler(Path, report), errRegisterHandler)
+}
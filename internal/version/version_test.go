@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+type registry struct {
+	handlers map[string]http.Handler
+	err      error
+}
+
+func (r *registry) AddMetricsExtraHandler(path string, h http.Handler) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.handlers[path] = h
+	return nil
+}
+
+func TestSetup(t *testing.T) {
+	errBoom := errors.New("boom")
+	report := NewReport([]string{"Topic", "ProviderConfig"}, map[string]bool{"pprof": false})
+
+	type want struct {
+		report *Report
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"Served": {
+			reason: "The report should be served as JSON, with its kinds sorted.",
+			want: want{report: &Report{
+				Version:  Version,
+				Kinds:    []string{"ProviderConfig", "Topic"},
+				Features: map[string]bool{"pprof": false},
+			}},
+		},
+		"RegisterFailed": {
+			reason: "Errors registering the endpoint should be returned.",
+			err:    errBoom,
+			want:   want{err: errors.Wrap(errBoom, errRegisterHandler)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &registry{handlers: map[string]http.Handler{}, err: tc.err}
+			got := want{err: Setup(r, report)}
+			if h, ok := r.handlers[Path]; ok {
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+				got.report = &Report{}
+				if err := json.Unmarshal(rec.Body.Bytes(), got.report); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(want{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSetup(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}